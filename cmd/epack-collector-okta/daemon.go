@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/attest"
+	"github.com/locktivity/epack-collector-okta/pkg/collector"
+)
+
+// minDaemonInterval is the shortest allowed collection interval.
+const minDaemonInterval = time.Minute
+
+// snapshotTimeFormat names snapshot directories so they sort chronologically.
+const snapshotTimeFormat = "20060102T150405Z"
+
+// runDaemon collects posture on a fixed interval until interrupted.
+//
+// The epack SDK permits a single Emit per process, so each snapshot is
+// written to output_dir/<timestamp>/ using the artifact paths the one-shot
+// mode emits, below artifacts/. The collector and its Okta client (including
+// the OAuth access token, which is refreshed before expiry) are reused
+// across runs.
+func runDaemon() int {
+	cfg, err := loadConfigFile(os.Getenv("EPACK_COLLECTOR_CONFIG"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	config, err := buildConfig(cfg, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	if config.DryRun {
+		fmt.Fprintf(os.Stderr, "error: dry_run is not supported in daemon mode\n")
+		return 2
+	}
+
+	outputs, err := buildOutputs(cfg, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	signer, err := buildSigner(cfg, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	interval, err := time.ParseDuration(getString(cfg, "interval"))
+	if err != nil || interval < minDaemonInterval {
+		fmt.Fprintf(os.Stderr, "error: interval must be a duration of at least %s (e.g. \"15m\")\n", minDaemonInterval)
+		return 2
+	}
+	// Each run is its own slot; a fixed key would mark every run a retry
+	if config.IdempotencyKey != "" {
+		fmt.Fprintf(os.Stderr, "error: idempotency_key is not supported in daemon mode\n")
+		return 2
+	}
+	if config.ScheduleInterval == 0 {
+		config.ScheduleInterval = interval
+	}
+	outputDir := getString(cfg, "output_dir")
+	if outputDir == "" {
+		fmt.Fprintf(os.Stderr, "error: output_dir is required in daemon mode\n")
+		return 2
+	}
+
+	config.OnStatus = func(message string) {
+		fmt.Fprintf(os.Stderr, "%s %s\n", time.Now().UTC().Format(time.RFC3339), message)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	c, err := collector.New(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: creating collector: %v\n", err)
+		return 2
+	}
+
+	metrics := &daemonMetrics{orgDomain: config.OrgDomain}
+	if addr := getString(cfg, "metrics_addr"); addr != "" {
+		go func() {
+			if err := serveMetrics(ctx, addr, metrics); err != nil {
+				config.OnStatus(fmt.Sprintf("Metrics server stopped: %v", err))
+			}
+		}()
+	}
+
+	for {
+		start := time.Now()
+		posture, dir, err := collectSnapshot(ctx, c, config, signer, outputs, outputDir)
+		metrics.record(posture, err, time.Since(start), c.Stats())
+		if err != nil {
+			config.OnStatus(fmt.Sprintf("Collection failed (%s): %v", collector.ClassifyError(err), err))
+		} else {
+			config.OnStatus(fmt.Sprintf("Snapshot written to %s", dir))
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(time.Until(start.Add(interval))):
+		}
+	}
+}
+
+// collectSnapshot runs one collection, delivers it to the configured outputs,
+// and writes its artifacts to a new timestamped directory under outputDir.
+func collectSnapshot(ctx context.Context, c *collector.Collector, config collector.Config, signer *attest.Signer, outputs outputs, outputDir string) (*collector.OrgPosture, string, error) {
+	posture, err := c.Collect(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	artifacts, err := buildArtifacts(posture, config, signer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	outputs.deliver(ctx, config.OnStatus, posture, artifacts)
+
+	dir := filepath.Join(outputDir, snapshotName(posture))
+
+	// Write into a temporary directory and rename so readers never see a
+	// partially written snapshot.
+	tmp := dir + ".tmp"
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		return nil, "", fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	for _, artifact := range artifacts {
+		data, err := json.MarshalIndent(artifact.Data, "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("encoding %s: %w", artifact.Path, err)
+		}
+		// Keep the path below artifacts/ so the paths in the evidence and
+		// signature manifests resolve within the snapshot
+		path := filepath.Join(tmp, filepath.FromSlash(strings.TrimPrefix(artifact.Path, "artifacts/")))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, "", fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, "", fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return nil, "", fmt.Errorf("finalizing snapshot: %w", err)
+	}
+
+	return posture, dir, nil
+}
+
+// snapshotName returns the collection time formatted for directory and
+// object names.
+func snapshotName(posture *collector.OrgPosture) string {
+	collectedAt, err := time.Parse(time.RFC3339, posture.CollectedAt)
+	if err != nil {
+		collectedAt = time.Now().UTC()
+	}
+	return collectedAt.Format(snapshotTimeFormat)
+}
+
+// loadConfigFile reads a JSON config file in the same format the epack runner
+// passes via EPACK_COLLECTOR_CONFIG.
+func loadConfigFile(path string) (map[string]any, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("EPACK_COLLECTOR_CONFIG must point to a JSON config file in daemon mode")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/locktivity/epack-collector-okta/internal/oktafake"
+	"github.com/locktivity/epack-collector-okta/pkg/attest"
+	"github.com/locktivity/epack-collector-okta/pkg/collector"
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// newFakeCollector returns a collector for the medium fixture org.
+func newFakeCollector(t *testing.T, config collector.Config) *collector.Collector {
+	t.Helper()
+	fake, err := oktafake.Load(filepath.Join("..", "..", "pkg", "collector", "testdata", "orgs", "medium.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := okta.NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("daemon-token")
+	return collector.NewWithClient(config, client)
+}
+
+func TestCollectSnapshot_ArtifactPaths(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := attest.NewSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := collector.Config{OrgDomain: "medium.okta.com", Detail: true, Entities: true, EvidenceChunkSize: 1}
+	c := newFakeCollector(t, config)
+	_, dir, err := collectSnapshot(context.Background(), c, config, signer, outputs{}, t.TempDir())
+	if err != nil {
+		t.Fatalf("collectSnapshot() error = %v", err)
+	}
+
+	// resolve maps an artifact path to its file in the snapshot
+	resolve := func(path string) string {
+		return filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(path, "artifacts/")))
+	}
+	read := func(path string, v any) {
+		t.Helper()
+		data, err := os.ReadFile(resolve(path))
+		if err != nil {
+			t.Fatalf("reading %s: %v", path, err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatalf("parsing %s: %v", path, err)
+		}
+	}
+
+	var evidence collector.EvidenceManifest
+	read("artifacts/okta.evidence.json", &evidence)
+	if len(evidence.Chunks) < 2 {
+		t.Fatalf("expected evidence in several chunks, got %+v", evidence)
+	}
+	for _, chunk := range evidence.Chunks {
+		if _, err := os.Stat(resolve(chunk.Path)); err != nil {
+			t.Errorf("evidence chunk %s not in snapshot: %v", chunk.Path, err)
+		}
+	}
+
+	var signatures attest.Manifest
+	read("artifacts/okta.sig.json", &signatures)
+	users := 0
+	for _, sig := range signatures.Signatures {
+		if _, err := os.Stat(resolve(sig.Path)); err != nil {
+			t.Errorf("signed artifact %s not in snapshot: %v", sig.Path, err)
+		}
+		if strings.HasPrefix(sig.Path, "artifacts/okta/users/") {
+			users++
+		}
+	}
+	if users == 0 {
+		t.Error("expected user entity documents in the snapshot")
+	}
+}
+
+func TestCollectSnapshot_RunIDPerRun(t *testing.T) {
+	fixture := `{
+  "version": 1,
+  "responses": {
+    "GET /api/v1/users?limit=200": {"status": 200, "body": [{"id": "user1", "status": "ACTIVE"}]},
+    "GET /api/v1/users/user1/factors": {"status": 200, "body": []},
+    "GET /api/v1/apps?limit=200": {"status": 200, "body": []},
+    "GET /api/v1/policies?type=OKTA_SIGN_ON": {"status": 200, "body": []},
+    "GET /api/v1/policies?type=MFA_ENROLL": {"status": 200, "body": []}
+  }
+}`
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(path, []byte(fixture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record each User-Agent sent, in order
+	var mu sync.Mutex
+	var userAgents []string
+	capture := func(next http.RoundTripper) http.RoundTripper {
+		return okta.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			if ua := req.Header.Get("User-Agent"); len(userAgents) == 0 || userAgents[len(userAgents)-1] != ua {
+				userAgents = append(userAgents, ua)
+			}
+			mu.Unlock()
+			return next.RoundTrip(req)
+		})
+	}
+	config := collector.Config{
+		OrgDomain:   "test.okta.com",
+		FixtureMode: collector.FixtureModeReplay,
+		FixturePath: path,
+		Middleware:  []okta.Middleware{capture},
+	}
+	c, err := collector.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var runIDs []string
+	for range 2 {
+		posture, _, err := collectSnapshot(context.Background(), c, config, nil, outputs{}, t.TempDir())
+		if err != nil {
+			t.Fatalf("collectSnapshot() error = %v", err)
+		}
+		runIDs = append(runIDs, posture.RunID)
+	}
+	if runIDs[0] == "" || runIDs[0] == runIDs[1] {
+		t.Errorf("expected a distinct run ID per run, got %v", runIDs)
+	}
+	if len(userAgents) != 2 || !strings.Contains(userAgents[0], runIDs[0]) || !strings.Contains(userAgents[1], runIDs[1]) {
+		t.Errorf("expected each run's User-Agent to carry its run ID, got %v for %v", userAgents, runIDs)
+	}
+}
@@ -5,17 +5,51 @@
 package main
 
 import (
-	"github.com/locktivity/epack-collector-okta/internal/collector"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/attest"
+	"github.com/locktivity/epack-collector-okta/pkg/collector"
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
 	"github.com/locktivity/epack/componentsdk"
 )
 
 // Build-time variables set via -ldflags
 var (
-	Version = "dev"
-	Commit  = "unknown"
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown" // RFC 3339; the commit date, so rebuilds are reproducible
 )
 
 func main() {
+	resolveBuildInfo()
+
+	// Standalone modes handled before the SDK takes over argument parsing
+	for i, arg := range os.Args[1:] {
+		if arg == "--build-info" {
+			printBuildInfo()
+			os.Exit(0)
+		}
+		if arg == "--generate-schema" {
+			os.Exit(generateSchema(collector.SchemaVersion))
+		}
+		if version, ok := strings.CutPrefix(arg, "--generate-schema="); ok {
+			os.Exit(generateSchema(version))
+		}
+		if arg == "--daemon" {
+			os.Exit(runDaemon())
+		}
+		if arg == "--compare" {
+			os.Exit(compareDocuments(os.Args[i+2:]))
+		}
+	}
+
 	componentsdk.RunCollector(componentsdk.CollectorSpec{
 		Name:        "okta",
 		Version:     Version,
@@ -25,55 +59,448 @@ func main() {
 }
 
 func run(ctx componentsdk.CollectorContext) error {
-	// Build config from SDK context
-	cfg := ctx.Config()
+	config, err := buildConfig(ctx.Config(), ctx.Secret)
+	if err != nil {
+		return err
+	}
+	config.OnStatus = ctx.Status
+	config.OnProgress = ctx.Progress
+
+	outputs, err := buildOutputs(ctx.Config(), ctx.Secret)
+	if err != nil {
+		return err
+	}
+	signer, err := buildSigner(ctx.Config(), ctx.Secret)
+	if err != nil {
+		return err
+	}
+
+	// Create collector and collect posture
+	c, err := collector.New(config)
+	if err != nil {
+		return componentsdk.NewConfigError("creating collector: %v", err)
+	}
+
+	// A dry run emits only the estimate; outputs and signing are skipped
+	if config.DryRun {
+		estimate, err := c.Estimate(ctx.Context())
+		if err != nil {
+			return collectError(err)
+		}
+		return ctx.Emit([]componentsdk.CollectedArtifact{{
+			Data: estimate,
+			Path: "artifacts/okta.estimate.json",
+		}})
+	}
+
+	posture, err := c.Collect(ctx.Context())
+	if err != nil {
+		return collectError(err)
+	}
+
+	artifacts, err := buildArtifacts(posture, config, signer)
+	if err != nil {
+		return err
+	}
+	outputs.deliver(ctx.Context(), ctx.Status, posture, artifacts)
+
+	return ctx.Emit(artifacts)
+}
+
+// minScheduleInterval is the shortest schedule slot idempotency keys can
+// be derived from.
+const minScheduleInterval = time.Minute
+
+// buildConfig validates the raw config and secrets and builds the collector
+// configuration. Errors are SDK config errors.
+func buildConfig(cfg map[string]any, secret func(string) string) (collector.Config, error) {
+	// Secrets may be passed inline or as paths to mounted files
+	privateKey, err := readSecret(secret, "OKTA_PRIVATE_KEY")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+	nextPrivateKey, err := readSecret(secret, "OKTA_NEXT_PRIVATE_KEY")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+	clientCert, err := readSecret(secret, "OKTA_CLIENT_CERTIFICATE")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+	clientCertKey, err := readSecret(secret, "OKTA_CLIENT_CERTIFICATE_KEY")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+	apiToken, err := readSecret(secret, "OKTA_API_TOKEN")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+	pamKeyID, err := readSecret(secret, "OKTA_PAM_KEY_ID")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+	pamKeySecret, err := readSecret(secret, "OKTA_PAM_KEY_SECRET")
+	if err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+
+	// Validate raw config against the schema before reading values
+	if err := collector.ValidateConfig(cfg); err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("invalid config: %v", err)
+	}
+
 	config := collector.Config{
-		OrgDomain:  getString(cfg, "org_domain"),
-		ClientID:   getString(cfg, "client_id"),
-		PrivateKey: ctx.Secret("OKTA_PRIVATE_KEY"),
-		APIToken:   ctx.Secret("OKTA_API_TOKEN"),
-		OnStatus:   ctx.Status,
-		OnProgress: ctx.Progress,
+		OrgDomain:             getString(cfg, "org_domain"),
+		ClientID:              getString(cfg, "client_id"),
+		PrivateKey:            privateKey,
+		APIToken:              strings.TrimSpace(apiToken),
+		PrivateKeyID:          getString(cfg, "private_key_id"),
+		NextPrivateKey:        nextPrivateKey,
+		NextPrivateKeyID:      getString(cfg, "next_private_key_id"),
+		ClientCertificate:     clientCert,
+		ClientCertificateKey:  clientCertKey,
+		AuthorizationServerID: getString(cfg, "authorization_server_id"),
+		TokenCachePath:        getString(cfg, "token_cache_path"),
+		SchemaVersions:        getStringSlice(cfg, "schema_versions"),
+		Compression:           getString(cfg, "compression"),
+		CompressionMinBytes:   getInt(cfg, "compression_min_bytes"),
+		OutputFormat:          getString(cfg, "output_format"),
+		OAuthScopes:           getStringSlice(cfg, "oauth_scopes"),
+		GroupsInclude:         getStringSlice(cfg, "groups_include"),
+		UserFilter:            getString(cfg, "user_filter"),
+		SystemLogLookbackDays: getInt(cfg, "system_log_lookback_days"),
+		PAMTeam:               getString(cfg, "pam_team"),
+		PAMKeyID:              strings.TrimSpace(pamKeyID),
+		PAMKeySecret:          strings.TrimSpace(pamKeySecret),
+		Entities:              getBool(cfg, "entities"),
+		Detail:                getBool(cfg, "detail"),
+		PIIPolicy:             getString(cfg, "pii_policy"),
+		EvidenceChunkSize:     getInt(cfg, "evidence_chunk_size"),
+		PrimaryEmailDomains:   getStringSlice(cfg, "primary_email_domains"),
+		CrownJewelApps:        getStringSlice(cfg, "crown_jewel_apps"),
+		MFAGroups:             getStringSlice(cfg, "mfa_groups"),
+		SensitiveAttributes:   getStringSlice(cfg, "sensitive_attributes"),
+		Benchmark:             getString(cfg, "benchmark"),
+		Exemptions:            getExemptions(cfg),
+		IdempotencyKey:        getString(cfg, "idempotency_key"),
+		Definitions:           getDefinitions(cfg),
+		ReadTimeoutSeconds:    getInt(cfg, "read_timeout_seconds"),
+		DryRun:                getBool(cfg, "dry_run"),
+		FixtureMode:           getString(cfg, "fixture_mode"),
+		FixturePath:           getString(cfg, "fixture_path"),
+		Version:               Version,
+		Commit:                Commit,
+		BuildDate:             BuildDate,
 	}
 
 	if config.OrgDomain == "" {
-		return componentsdk.NewConfigError("org_domain is required")
+		return collector.Config{}, componentsdk.NewConfigError("org_domain is required")
+	}
+	if _, err := okta.ParseOrgDomain(config.OrgDomain); err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+
+	if config.UserFilter != "" && len(config.GroupsInclude) > 0 {
+		return collector.Config{}, componentsdk.NewConfigError("user_filter and groups_include cannot be combined")
+	}
+
+	if interval := getString(cfg, "schedule_interval"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d < minScheduleInterval {
+			return collector.Config{}, componentsdk.NewConfigError("schedule_interval must be a duration of at least %s (e.g. \"24h\")", minScheduleInterval)
+		}
+		config.ScheduleInterval = d
+	}
+
+	if err := collector.ValidateExemptions(config.Exemptions); err != nil {
+		return collector.Config{}, componentsdk.NewConfigError("%v", err)
+	}
+
+	if config.ReadTimeoutSeconds < 0 {
+		return collector.Config{}, componentsdk.NewConfigError("read_timeout_seconds must be positive")
+	}
+
+	if config.FixtureMode != "" && config.FixturePath == "" {
+		return collector.Config{}, componentsdk.NewConfigError("fixture_path is required when fixture_mode is set")
+	}
+
+	if config.PAMTeam != "" && (config.PAMKeyID == "" || config.PAMKeySecret == "") {
+		return collector.Config{}, componentsdk.NewConfigError("OKTA_PAM_KEY_ID and OKTA_PAM_KEY_SECRET are required when pam_team is set")
 	}
 
-	// Check for valid auth configuration
+	if (config.ClientCertificate == "") != (config.ClientCertificateKey == "") {
+		return collector.Config{}, componentsdk.NewConfigError("OKTA_CLIENT_CERTIFICATE and OKTA_CLIENT_CERTIFICATE_KEY must be set together")
+	}
+	if config.ClientCertificate != "" {
+		if _, err := tls.X509KeyPair([]byte(config.ClientCertificate), []byte(config.ClientCertificateKey)); err != nil {
+			return collector.Config{}, componentsdk.NewConfigError("invalid client certificate: %v", err)
+		}
+	}
+
+	// Check for valid auth configuration (replay mode needs no credentials)
 	hasOAuthAuth := config.ClientID != "" && config.PrivateKey != ""
 	hasTokenAuth := config.APIToken != ""
-	if !hasOAuthAuth && !hasTokenAuth {
-		return componentsdk.NewConfigError("authentication required: provide client_id + OKTA_PRIVATE_KEY or OKTA_API_TOKEN")
+	isReplay := config.FixtureMode == collector.FixtureModeReplay
+	if !hasOAuthAuth && !hasTokenAuth && !isReplay {
+		return collector.Config{}, componentsdk.NewConfigError("authentication required: provide client_id + OKTA_PRIVATE_KEY (or OKTA_PRIVATE_KEY_FILE) or OKTA_API_TOKEN (or OKTA_API_TOKEN_FILE)")
 	}
 
-	// Create collector and collect posture
-	c, err := collector.New(config)
+	return config, nil
+}
+
+// buildArtifacts assembles the artifacts emitted for a collected posture.
+// With a signer, a signature manifest covering every document is appended.
+func buildArtifacts(posture *collector.OrgPosture, config collector.Config, signer *attest.Signer) ([]componentsdk.CollectedArtifact, error) {
+	// Evidence too large for one document moves to chunk documents listed
+	// in a manifest, and the posture documents point to the manifest
+	var evidence []componentsdk.CollectedArtifact
+	if manifest, chunks := posture.EvidenceChunks(config.EvidenceChunkSize); manifest != nil {
+		for i, chunk := range chunks {
+			manifest.Chunks[i].Path = fmt.Sprintf("artifacts/okta/evidence/%04d.json", chunk.Sequence)
+		}
+		evidence = append(evidence, componentsdk.CollectedArtifact{
+			Data: manifest,
+			Path: "artifacts/okta.evidence.json",
+		})
+		for i, chunk := range chunks {
+			evidence = append(evidence, componentsdk.CollectedArtifact{
+				Data: chunk,
+				Path: manifest.Chunks[i].Path,
+			})
+		}
+		chunked := *posture
+		chunked.Evidence = nil
+		chunked.EvidenceManifest = "artifacts/okta.evidence.json"
+		posture = &chunked
+	}
+
+	// Detailed Okta-specific output, one document per requested schema version
+	docs, err := posture.Documents(config.SchemaVersions)
 	if err != nil {
-		return componentsdk.NewConfigError("creating collector: %v", err)
+		return nil, componentsdk.NewConfigError("%v", err)
 	}
-	posture, err := c.Collect(ctx.Context())
+	var artifacts []componentsdk.CollectedArtifact
+	for _, version := range []struct{ version, path string }{
+		{collector.SchemaVersion, "artifacts/okta"},
+		{collector.SchemaVersionV2, "artifacts/okta.v2"},
+	} {
+		doc, ok := docs[version.version]
+		if !ok {
+			continue
+		}
+		artifact, err := postureArtifact(doc, version.path, config)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	artifacts = append(artifacts, evidence...)
+
+	// Normalized IDP posture for profile evaluation
+	artifacts = append(artifacts, componentsdk.CollectedArtifact{
+		Data:   posture.ToIDPPosture(),
+		Schema: "evidencepack/idp-posture@v1",
+		Path:   "artifacts/okta.idp-posture.json",
+	})
+
+	// One document per user, app, and policy for entity-level indexing
+	for _, doc := range posture.EntityDocuments() {
+		artifacts = append(artifacts, componentsdk.CollectedArtifact{
+			Data: doc,
+			Path: fmt.Sprintf("artifacts/okta/%ss/%s.json", doc.Kind, doc.ID),
+		})
+	}
+
+	if signer != nil {
+		manifest := signer.Manifest()
+		for _, artifact := range artifacts {
+			sig, err := signer.Sign(artifact.Path, artifact.Data)
+			if err != nil {
+				return nil, fmt.Errorf("signing %s: %w", artifact.Path, err)
+			}
+			manifest.Signatures = append(manifest.Signatures, sig)
+		}
+		artifacts = append(artifacts, componentsdk.CollectedArtifact{
+			Data: manifest,
+			Path: "artifacts/okta.sig.json",
+		})
+	}
+
+	return artifacts, nil
+}
+
+// postureArtifact wraps a posture document in an artifact at path plus
+// ".json". With compression on, a document of at least the configured size
+// is emitted as a collector.CompressedDocument at path plus
+// ".compressed.json" instead, and with proto output as a
+// collector.ProtoDocument at path plus ".proto.json", so consumers reading
+// the plain path never get a wrapper they don't expect.
+func postureArtifact(doc any, path string, config collector.Config) (componentsdk.CollectedArtifact, error) {
+	if config.OutputFormat == collector.OutputFormatProto {
+		encoded, err := collector.EncodeProto(doc)
+		if err != nil {
+			return componentsdk.CollectedArtifact{}, fmt.Errorf("encoding %s.proto.json: %w", path, err)
+		}
+		return componentsdk.CollectedArtifact{Data: encoded, Path: path + ".proto.json"}, nil
+	}
+	if config.Compression == collector.CompressionGzip {
+		minBytes := config.CompressionMinBytes
+		if minBytes <= 0 {
+			minBytes = collector.DefaultCompressionMinBytes
+		}
+		compressed, err := collector.CompressDocument(doc, minBytes)
+		if err != nil {
+			return componentsdk.CollectedArtifact{}, fmt.Errorf("compressing %s.json: %w", path, err)
+		}
+		if compressed != nil {
+			return componentsdk.CollectedArtifact{Data: compressed, Path: path + ".compressed.json"}, nil
+		}
+	}
+	return componentsdk.CollectedArtifact{Data: doc, Path: path + ".json"}, nil
+}
+
+// buildSigner loads the optional Ed25519 signing key. It returns nil when
+// signing is not configured.
+func buildSigner(cfg map[string]any, secret func(string) string) (*attest.Signer, error) {
+	key, err := readSecret(secret, "SIGNING_KEY")
+	if err != nil {
+		return nil, componentsdk.NewConfigError("%v", err)
+	}
+	if key == "" {
+		return nil, nil
+	}
+	signer, err := attest.NewSigner([]byte(key), getString(cfg, "signing_key_id"))
 	if err != nil {
+		return nil, componentsdk.NewConfigError("%v", err)
+	}
+	return signer, nil
+}
+
+// resolveBuildInfo fills in the commit and build date from the VCS stamp
+// the Go toolchain embeds when they weren't set via -ldflags, as in builds
+// with go install or a plain go build.
+func resolveBuildInfo() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if Commit == "unknown" {
+				Commit = setting.Value
+			}
+		case "vcs.time":
+			if BuildDate == "unknown" {
+				BuildDate = setting.Value
+			}
+		}
+	}
+}
+
+// printBuildInfo writes the collector's build metadata to stdout.
+func printBuildInfo() {
+	fmt.Printf("epack-collector-okta %s\n", Version)
+	fmt.Printf("commit:     %s\n", Commit)
+	fmt.Printf("build date: %s\n", BuildDate)
+	fmt.Printf("go:         %s\n", runtime.Version())
+}
+
+// generateSchema writes the JSON Schema for an output document version to stdout.
+func generateSchema(version string) int {
+	schema, err := collector.GenerateSchema(version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error generating schema: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(schema))
+	return 0
+}
+
+// compareDocuments reads the posture documents at paths and prints their
+// comparison as JSON.
+func compareDocuments(paths []string) int {
+	postures := make([]*collector.OrgPosture, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading posture document: %v\n", err)
+			return 2
+		}
+		posture, err := collector.ParsePostureDocument(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error parsing %s: %v\n", path, err)
+			return 2
+		}
+		postures = append(postures, posture)
+	}
+
+	comparison, err := collector.Compare(postures)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error comparing orgs: %v\n", err)
+		return 2
+	}
+	out, err := json.MarshalIndent(comparison, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error encoding comparison: %v\n", err)
+		return 1
+	}
+	fmt.Println(string(out))
+	return 0
+}
+
+// collectError maps a collection failure to the SDK error type for its
+// category, so the runner only retries failures that can succeed on retry.
+func collectError(err error) error {
+	switch collector.ClassifyError(err) {
+	case collector.ErrorCategoryAuth:
+		return componentsdk.NewAuthError("collecting posture: authentication failed: %v", err)
+	case collector.ErrorCategoryPermission:
+		return componentsdk.NewAuthError("collecting posture: insufficient permissions (check granted scopes and admin role): %v", err)
+	case collector.ErrorCategoryParse:
+		return fmt.Errorf("collecting posture: unexpected API response: %w", err)
+	default:
+		// Rate limits, network failures, and unknown errors are retryable
 		return componentsdk.NewNetworkError("collecting posture: %v", err)
 	}
+}
 
-	// Transform to normalized idp-posture format
-	normalized := posture.ToIDPPosture()
-
-	// Emit both detailed and normalized artifacts
-	return ctx.Emit([]componentsdk.CollectedArtifact{
-		{
-			// Detailed Okta-specific output
-			Data: posture,
-			Path: "artifacts/okta.json",
-		},
-		{
-			// Normalized IDP posture for profile evaluation
-			Data:   normalized,
-			Schema: "evidencepack/idp-posture@v1",
-			Path:   "artifacts/okta.idp-posture.json",
-		},
-	})
+// maxSecretFileSize caps the size of secret files read from disk.
+const maxSecretFileSize = 1 << 20
+
+// readSecret returns the secret named name, either inline from the
+// environment or read from the file path given in name_FILE.
+func readSecret(secret func(string) string, name string) (string, error) {
+	inline := secret(name)
+	path := secret(name + "_FILE")
+
+	if path == "" {
+		return inline, nil
+	}
+	if inline != "" {
+		return "", fmt.Errorf("set only one of %s or %s_FILE", name, name)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s_FILE: %w", name, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("reading %s_FILE: %s is not a regular file", name, path)
+	}
+	if info.Size() > maxSecretFileSize {
+		return "", fmt.Errorf("reading %s_FILE: %s exceeds %d bytes", name, path, maxSecretFileSize)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s_FILE: %w", name, err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("reading %s_FILE: %s is empty", name, path)
+	}
+
+	return string(data), nil
 }
 
 // getString safely extracts a string from config map
@@ -86,3 +513,77 @@ func getString(cfg map[string]any, key string) string {
 	}
 	return ""
 }
+
+// getInt safely extracts an integer from config map
+func getInt(cfg map[string]any, key string) int {
+	if cfg == nil {
+		return 0
+	}
+	v, _ := cfg[key].(float64)
+	return int(v)
+}
+
+// getBool safely extracts a boolean from config map
+func getBool(cfg map[string]any, key string) bool {
+	if cfg == nil {
+		return false
+	}
+	v, _ := cfg[key].(bool)
+	return v
+}
+
+// getStringSlice safely extracts a string list from config map
+func getStringSlice(cfg map[string]any, key string) []string {
+	if cfg == nil {
+		return nil
+	}
+	items, ok := cfg[key].([]any)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, item := range items {
+		if v, ok := item.(string); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// getExemptions extracts the exemption list. The config schema has already
+// checked that every entry is an object of strings.
+func getExemptions(cfg map[string]any) []collector.Exemption {
+	items, _ := cfg["exemptions"].([]any)
+	var exemptions []collector.Exemption
+	for _, item := range items {
+		m, _ := item.(map[string]any)
+		exemptions = append(exemptions, collector.Exemption{
+			Finding:       strings.TrimSpace(getString(m, "finding")),
+			ObjectID:      strings.TrimSpace(getString(m, "object_id")),
+			Expires:       strings.TrimSpace(getString(m, "expires")),
+			Justification: strings.TrimSpace(getString(m, "justification")),
+		})
+	}
+	return exemptions
+}
+
+// getDefinitions extracts the metric definition overrides from config map.
+// A list given as [] stays empty rather than falling back to the default.
+func getDefinitions(cfg map[string]any) collector.Definitions {
+	m, _ := cfg["definitions"].(map[string]any)
+	list := func(key string) []string {
+		if _, ok := m[key]; !ok {
+			return nil
+		}
+		return append([]string{}, getStringSlice(m, key)...)
+	}
+	return collector.Definitions{
+		SSOSignOnModes:           list("sso_sign_on_modes"),
+		ProvisioningFeatures:     list("provisioning_features"),
+		DeprovisioningFeatures:   list("deprovisioning_features"),
+		PhishingResistantFactors: list("phishing_resistant_factors"),
+		PasswordlessFactors:      list("passwordless_factors"),
+		ExcludedUserStatuses:     list("excluded_user_statuses"),
+		InactiveDays:             getInt(m, "inactive_days"),
+	}
+}
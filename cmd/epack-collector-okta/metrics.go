@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/collector"
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// metricsPrefix namespaces every metric served on /metrics.
+const metricsPrefix = "okta_"
+
+// metricsShutdownTimeout bounds how long the metrics server drains on exit.
+const metricsShutdownTimeout = 5 * time.Second
+
+// daemonMetrics holds the latest posture and collector internals served in
+// the Prometheus text exposition format.
+type daemonMetrics struct {
+	mu           sync.Mutex
+	orgDomain    string
+	posture      []collector.Metric
+	successes    int64
+	failures     int64
+	lastSuccess  time.Time
+	lastDuration time.Duration
+	stats        okta.RequestStats
+}
+
+// record updates the metrics after a collection run. Posture gauges keep
+// their previous values when a run fails.
+func (m *daemonMetrics) record(posture *collector.OrgPosture, err error, duration time.Duration, stats okta.RequestStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastDuration = duration
+	m.stats = stats
+	if err != nil {
+		m.failures++
+		return
+	}
+	m.successes++
+	m.lastSuccess = time.Now()
+	m.posture = posture.Metrics()
+}
+
+// ServeHTTP writes the current metrics.
+func (m *daemonMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	labels := fmt.Sprintf(`{org_domain=%q}`, m.orgDomain)
+
+	for _, metric := range m.posture {
+		name := metricsPrefix + metric.Name
+		fmt.Fprintf(&b, "# TYPE %s gauge\n%s%s %s\n", name, name, labels, formatFloat(metric.Value))
+	}
+
+	writeMetric(&b, "collector_runs_total", "counter", "Completed collection runs by result.",
+		fmt.Sprintf(`{org_domain=%q,result="success"} %d`, m.orgDomain, m.successes),
+		fmt.Sprintf(`{org_domain=%q,result="failure"} %d`, m.orgDomain, m.failures))
+	if !m.lastSuccess.IsZero() {
+		writeMetric(&b, "collector_last_success_timestamp_seconds", "gauge", "Unix time of the last successful collection.",
+			labels+" "+strconv.FormatInt(m.lastSuccess.Unix(), 10))
+	}
+	writeMetric(&b, "collector_last_duration_seconds", "gauge", "Duration of the last collection run.",
+		labels+" "+formatFloat(m.lastDuration.Seconds()))
+	writeMetric(&b, "collector_api_requests_total", "counter", "Okta API requests sent, including retries.",
+		labels+" "+strconv.FormatInt(m.stats.Requests, 10))
+	writeMetric(&b, "collector_api_rate_limited_total", "counter", "Okta API responses with status 429.",
+		labels+" "+strconv.FormatInt(m.stats.RateLimited, 10))
+	writeMetric(&b, "collector_api_page_retries_total", "counter", "Okta list pages re-fetched after a transient failure.",
+		labels+" "+strconv.FormatInt(m.stats.PageRetries, 10))
+	writeMetric(&b, "collector_api_throttled_total", "counter", "Okta API requests held back to keep rate limit headroom.",
+		labels+" "+strconv.FormatInt(m.stats.Throttled, 10))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// writeMetric writes one metric family with HELP and TYPE lines. Each sample
+// is the label set and value that follow the metric name.
+func writeMetric(b *strings.Builder, name, kind, help string, samples ...string) {
+	name = metricsPrefix + name
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	for _, sample := range samples {
+		fmt.Fprintf(b, "%s%s\n", name, sample)
+	}
+}
+
+// formatFloat formats a sample value without a trailing exponent for
+// integral values.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// serveMetrics serves /metrics on addr until ctx is cancelled.
+func serveMetrics(ctx context.Context, addr string, metrics *daemonMetrics) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/locktivity/epack-collector-okta/internal/export"
+	"github.com/locktivity/epack-collector-okta/pkg/collector"
+	"github.com/locktivity/epack/componentsdk"
+)
+
+// outputs are optional destinations that receive each snapshot in addition
+// to the artifacts emitted to the epack runner.
+type outputs struct {
+	webhook       *export.Webhook
+	archive       *export.ObjectStore
+	archivePrefix string
+	ndjsonPath    string
+	csvPath       string
+}
+
+// buildOutputs configures the optional outputs. Errors are SDK config errors.
+func buildOutputs(cfg map[string]any, secret func(string) string) (outputs, error) {
+	var o outputs
+
+	if webhookURL := getString(cfg, "webhook_url"); webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return outputs{}, componentsdk.NewConfigError("webhook_url must be an http(s) URL")
+		}
+		webhookSecret, err := readSecret(secret, "WEBHOOK_SECRET")
+		if err != nil {
+			return outputs{}, componentsdk.NewConfigError("%v", err)
+		}
+		if webhookSecret == "" {
+			return outputs{}, componentsdk.NewConfigError("WEBHOOK_SECRET (or WEBHOOK_SECRET_FILE) is required when webhook_url is set")
+		}
+		o.webhook = &export.Webhook{URL: webhookURL, Secret: []byte(webhookSecret)}
+	}
+
+	if bucket := getString(cfg, "archive_bucket"); bucket != "" {
+		creds := map[string]string{}
+		for _, name := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+			value, err := readSecret(secret, name)
+			if err != nil {
+				return outputs{}, componentsdk.NewConfigError("%v", err)
+			}
+			creds[name] = strings.TrimSpace(value)
+		}
+		if creds["AWS_ACCESS_KEY_ID"] == "" || creds["AWS_SECRET_ACCESS_KEY"] == "" {
+			return outputs{}, componentsdk.NewConfigError("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required when archive_bucket is set")
+		}
+		o.archive = &export.ObjectStore{
+			Endpoint:        getString(cfg, "archive_endpoint"),
+			Region:          getString(cfg, "archive_region"),
+			Bucket:          bucket,
+			AccessKeyID:     creds["AWS_ACCESS_KEY_ID"],
+			SecretAccessKey: creds["AWS_SECRET_ACCESS_KEY"],
+			SessionToken:    creds["AWS_SESSION_TOKEN"],
+		}
+		o.archivePrefix = getString(cfg, "archive_prefix")
+	}
+
+	if ndjsonPath := getString(cfg, "ndjson_path"); ndjsonPath != "" {
+		if !getBool(cfg, "detail") && !getBool(cfg, "entities") {
+			return outputs{}, componentsdk.NewConfigError("ndjson_path requires detail or entities to be enabled")
+		}
+		o.ndjsonPath = ndjsonPath
+	}
+	o.csvPath = getString(cfg, "csv_path")
+
+	return o, nil
+}
+
+// snapshot is the document delivered to outputs: every artifact of a run,
+// keyed by its artifact path.
+type snapshot struct {
+	RunID          string         `json:"run_id,omitempty"`
+	IdempotencyKey string         `json:"idempotency_key,omitempty"` // Same for retries of a run, for deduplication
+	OrgDomain      string         `json:"org_domain"`
+	CollectedAt    string         `json:"collected_at"`
+	Artifacts      map[string]any `json:"artifacts"`
+}
+
+// deliver sends a snapshot to every configured output. Delivery failures are
+// reported through status but never fail the collection, so the artifacts
+// still reach the epack runner.
+func (o outputs) deliver(ctx context.Context, status func(string), posture *collector.OrgPosture, artifacts []componentsdk.CollectedArtifact) {
+	if o.webhook != nil {
+		o.deliverWebhook(ctx, status, posture, artifacts)
+	}
+	if o.archive != nil {
+		o.deliverArchive(ctx, status, posture, artifacts)
+	}
+	if o.ndjsonPath != "" {
+		o.deliverNDJSON(status, posture)
+	}
+	if o.csvPath != "" {
+		o.deliverCSV(status, posture)
+	}
+}
+
+// deliverWebhook posts every artifact of the run as one snapshot document.
+func (o outputs) deliverWebhook(ctx context.Context, status func(string), posture *collector.OrgPosture, artifacts []componentsdk.CollectedArtifact) {
+	snap := snapshot{
+		RunID:          posture.RunID,
+		IdempotencyKey: posture.IdempotencyKey,
+		OrgDomain:      posture.OrgDomain,
+		CollectedAt:    posture.CollectedAt,
+		Artifacts:      make(map[string]any, len(artifacts)),
+	}
+	for _, artifact := range artifacts {
+		snap.Artifacts[artifact.Path] = artifact.Data
+	}
+	body, err := json.Marshal(snap)
+	if err != nil {
+		status(fmt.Sprintf("Warning: encoding snapshot: %v", err))
+		return
+	}
+
+	if err := o.webhook.Send(ctx, body); err != nil {
+		status(fmt.Sprintf("Warning: %v", err))
+		return
+	}
+	status("Snapshot delivered to webhook")
+}
+
+// deliverArchive uploads each artifact as its own object under
+// <prefix>/<org_domain>/<timestamp>/, keeping its path below artifacts/.
+func (o outputs) deliverArchive(ctx context.Context, status func(string), posture *collector.OrgPosture, artifacts []componentsdk.CollectedArtifact) {
+	dir := path.Join(o.archivePrefix, posture.OrgDomain, snapshotName(posture))
+	for _, artifact := range artifacts {
+		body, err := json.Marshal(artifact.Data)
+		if err != nil {
+			status(fmt.Sprintf("Warning: encoding %s: %v", artifact.Path, err))
+			return
+		}
+		key := path.Join(dir, strings.TrimPrefix(artifact.Path, "artifacts/"))
+		if err := o.archive.Put(ctx, key, body, "application/json"); err != nil {
+			status(fmt.Sprintf("Warning: archive export failed: %v", err))
+			return
+		}
+	}
+	status(fmt.Sprintf("Snapshot archived to %s/%s", o.archive.Bucket, dir))
+}
+
+// deliverNDJSON writes the evidence and entity records to ndjson_path,
+// replacing the file from the previous run only once the new one is
+// complete.
+func (o outputs) deliverNDJSON(status func(string), posture *collector.OrgPosture) {
+	tmp, err := os.CreateTemp(filepath.Dir(o.ndjsonPath), ".okta-*.ndjson")
+	if err != nil {
+		status(fmt.Sprintf("Warning: NDJSON export failed: %v", err))
+		return
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	err = posture.WriteNDJSON(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), o.ndjsonPath)
+	}
+	if err != nil {
+		status(fmt.Sprintf("Warning: NDJSON export failed: %v", err))
+		return
+	}
+	status("Records written to " + o.ndjsonPath)
+}
+
+// deliverCSV appends the posture metrics to csv_path as one row, writing
+// the header first if the file is new. A file whose header doesn't match
+// the current columns, e.g. from an older collector version, is left
+// untouched.
+func (o outputs) deliverCSV(status func(string), posture *collector.OrgPosture) {
+	file, err := os.OpenFile(o.csvPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		status(fmt.Sprintf("Warning: CSV export failed: %v", err))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	existing, err := csv.NewReader(file).Read()
+	switch {
+	case err == io.EOF:
+		existing = nil
+	case err != nil:
+		status(fmt.Sprintf("Warning: CSV export failed: reading %s: %v", o.csvPath, err))
+		return
+	case !slices.Equal(existing, collector.CSVHeader()):
+		status(fmt.Sprintf("Warning: CSV export skipped: %s has different columns; move it aside to start a new file", o.csvPath))
+		return
+	}
+
+	var row bytes.Buffer
+	if err := posture.WriteCSV(&row, existing == nil); err != nil {
+		status(fmt.Sprintf("Warning: CSV export failed: %v", err))
+		return
+	}
+	if _, err = file.Seek(0, io.SeekEnd); err == nil {
+		_, err = file.Write(row.Bytes())
+	}
+	if err != nil {
+		status(fmt.Sprintf("Warning: CSV export failed: %v", err))
+		return
+	}
+	status("Metrics appended to " + o.csvPath)
+}
@@ -0,0 +1,28 @@
+package export
+
+import "time"
+
+// Webhook delivery.
+const (
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+	// the timestamp, a ".", and the request body.
+	SignatureHeader = "X-Epack-Signature"
+
+	// TimestampHeader carries the Unix time the request was signed, so
+	// receivers can reject replayed deliveries.
+	TimestampHeader = "X-Epack-Timestamp"
+
+	webhookTimeout = 30 * time.Second
+)
+
+// Object storage.
+const (
+	defaultRegion      = "us-east-1"
+	objectStoreTimeout = 60 * time.Second
+)
+
+// Retries for all destinations. The backoff grows linearly per attempt.
+const (
+	deliveryAttempts = 3
+	deliveryBackoff  = 2 * time.Second
+)
@@ -0,0 +1,168 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ObjectStore writes objects to an S3-compatible bucket, signing requests
+// with AWS Signature Version 4. Google Cloud Storage is supported through
+// its XML API (https://storage.googleapis.com) with HMAC keys.
+type ObjectStore struct {
+	Endpoint        string // Defaults to https://s3.<region>.amazonaws.com
+	Region          string // Defaults to us-east-1
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string       // Optional, for temporary credentials
+	Client          *http.Client // Defaults to a client with a 60s timeout
+
+	now func() time.Time // Overridden in tests
+}
+
+// Put uploads body to key. Network errors and 5xx responses are retried.
+func (s *ObjectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: objectStoreTimeout}
+	}
+
+	if err := withRetry(ctx, func() (bool, error) { return s.put(ctx, client, key, body, contentType) }); err != nil {
+		return fmt.Errorf("uploading %s: %w", key, err)
+	}
+	return nil
+}
+
+// put makes a single upload attempt and reports whether a failure is worth
+// retrying.
+func (s *ObjectStore) put(ctx context.Context, client *http.Client, key string, body []byte, contentType string) (bool, error) {
+	objectURL := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.endpoint(), "/"), s.Bucket, escapePath(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, body)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	// S3 error bodies are small XML documents naming the error code
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode >= 500, fmt.Errorf("storage returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+// endpoint returns the configured endpoint or the regional AWS default.
+func (s *ObjectStore) endpoint() string {
+	if s.Endpoint != "" {
+		return s.Endpoint
+	}
+	return fmt.Sprintf("https://s3.%s.amazonaws.com", s.region())
+}
+
+// region returns the configured region or the default.
+func (s *ObjectStore) region() string {
+	if s.Region != "" {
+		return s.Region
+	}
+	return defaultRegion
+}
+
+// sign adds AWS Signature Version 4 headers to req. Host and every header
+// already set on the request are signed.
+func (s *ObjectStore) sign(req *http.Request, body []byte) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region() + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.region())
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapePath URI-encodes each segment of an object key.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,96 @@
+// Package export delivers collected posture snapshots to destinations
+// outside the epack runner.
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook POSTs snapshots to an HTTP endpoint, signed with HMAC-SHA256.
+type Webhook struct {
+	URL    string
+	Secret []byte
+	Client *http.Client // Defaults to a client with a 30s timeout
+}
+
+// Send delivers body to the webhook. Network errors and 5xx responses are
+// retried; other non-2xx responses fail immediately.
+func (w *Webhook) Send(ctx context.Context, body []byte) error {
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+
+	if err := withRetry(ctx, func() (bool, error) { return w.send(ctx, client, body) }); err != nil {
+		return fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	return nil
+}
+
+// send makes a single delivery attempt and reports whether a failure is
+// worth retrying.
+func (w *Webhook) send(ctx context.Context, client *http.Client, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(w.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	return resp.StatusCode >= 500, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+}
+
+// withRetry calls attempt until it succeeds, reports a permanent failure, or
+// the attempts are exhausted, and returns the last error.
+func withRetry(ctx context.Context, attempt func() (retry bool, err error)) error {
+	var lastErr error
+	for i := 1; i <= deliveryAttempts; i++ {
+		retry, err := attempt()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || i == deliveryAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deliveryBackoff * time.Duration(i)):
+		}
+	}
+	return lastErr
+}
+
+// Sign returns the signature header value for a delivery: "sha256=" followed
+// by the hex HMAC-SHA256 of timestamp + "." + body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWebhookSend(t *testing.T) {
+	secret := []byte("shh")
+	body := []byte(`{"org_domain":"test.okta.com"}`)
+
+	var received []byte
+	var signature, timestamp string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		timestamp = r.Header.Get(TimestampHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, Secret: secret, Client: server.Client()}
+	if err := webhook.Send(context.Background(), body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(received) != string(body) {
+		t.Errorf("expected body %s, got %s", body, received)
+	}
+	if timestamp == "" {
+		t.Fatal("expected timestamp header")
+	}
+	if want := Sign(secret, timestamp, body); signature != want {
+		t.Errorf("expected signature %s, got %s", want, signature)
+	}
+}
+
+func TestWebhookSend_ClientErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, Secret: []byte("shh"), Client: server.Client()}
+	if err := webhook.Send(context.Background(), []byte(`{}`)); err == nil {
+		t.Fatal("expected error for 401 response")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Reference value: printf '1700000000.{}' | openssl dgst -sha256 -hmac key
+	got := Sign([]byte("key"), "1700000000", []byte("{}"))
+	want := "sha256=9d713ed406bb7076d4123f0dc2c39d2df5c654ed4b0cd56b52c8b4c940bd63ae"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if Sign([]byte("other"), "1700000000", []byte("{}")) == got {
+		t.Error("expected signature to depend on the secret")
+	}
+	if Sign([]byte("key"), "1700000001", []byte("{}")) == got {
+		t.Error("expected signature to depend on the timestamp")
+	}
+}
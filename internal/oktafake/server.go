@@ -0,0 +1,229 @@
+// Package oktafake serves a fixed Okta org described by a JSON file, for
+// golden-file and integration tests.
+//
+// An org file maps API paths to the response bodies Okta would return:
+//
+//	{
+//	  "page_size": 2,
+//	  "responses": {
+//	    "/api/v1/org": {"id": "00o1", "subdomain": "acme"},
+//	    "/api/v1/users": [{"id": "00u1", "status": "ACTIVE", "lastLogin": "{{now-120d}}"}],
+//	    "/api/v1/policies?type=OKTA_SIGN_ON": []
+//	  },
+//	  "errors": {"/api/v1/captchas": 403}
+//	}
+//
+// A key with a query matches only requests with that query, ignoring the
+// limit and after paging parameters; a key without one matches any query.
+// Array bodies are paged like Okta's list APIs, with a Link header to the
+// next page. Requests for paths not in the file get a 404. Strings of the
+// form {{now-120d}} or {{now+2h}} are replaced with a timestamp relative to
+// when the file is loaded, so metrics that depend on age stay stable as
+// the fixture gets older; {{now-50h|ms}} gives the time as Unix
+// milliseconds instead, for fields such as an agent's lastConnection.
+package oktafake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultPageSize is the largest page served, matching Okta's list APIs.
+const defaultPageSize = 200
+
+// Org is the org file format.
+type Org struct {
+	PageSize  int                        `json:"page_size,omitempty"` // Largest page served (default: 200)
+	Responses map[string]json.RawMessage `json:"responses"`           // Path, optionally with a query, to response body
+	Errors    map[string]int             `json:"errors,omitempty"`    // Path, optionally with a query, to error status
+}
+
+// Server serves an org file over the Okta REST API.
+type Server struct {
+	org Org
+
+	mu     sync.Mutex
+	misses map[string]bool
+}
+
+// relativeTime matches relative timestamp placeholders.
+var relativeTime = regexp.MustCompile(`^\{\{now([+-]\d+)([dhm])(\|ms)?\}\}$`)
+
+// Load reads an org file, resolving relative timestamps against now.
+func Load(path string) (*Server, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading org file: %w", err)
+	}
+	var org Org
+	if err := json.Unmarshal(data, &org); err != nil {
+		return nil, fmt.Errorf("parsing org file: %w", err)
+	}
+	if org.PageSize <= 0 {
+		org.PageSize = defaultPageSize
+	}
+
+	now := time.Now().UTC()
+	for key, body := range org.Responses {
+		resolved, err := resolveTimes(body, now)
+		if err != nil {
+			return nil, fmt.Errorf("response %s: %w", key, err)
+		}
+		org.Responses[key] = resolved
+	}
+	return &Server{org: org, misses: make(map[string]bool)}, nil
+}
+
+// Misses returns the requested paths the org file has no response for,
+// sorted, to help fill in a new fixture.
+func (s *Server) Misses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var misses []string
+	for key := range s.misses {
+		misses = append(misses, key)
+	}
+	slices.Sort(misses)
+	return misses
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	after, _ := strconv.Atoi(query.Get("after"))
+	query.Del("limit")
+	query.Del("after")
+
+	keys := []string{r.URL.Path}
+	if len(query) > 0 {
+		keys = []string{r.URL.Path + "?" + query.Encode(), r.URL.Path}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	for _, key := range keys {
+		if status, ok := s.org.Errors[key]; ok {
+			writeError(w, status)
+			return
+		}
+		if body, ok := s.org.Responses[key]; ok {
+			s.serve(w, r, body, limit, after)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.misses[keys[0]] = true
+	s.mu.Unlock()
+	writeError(w, http.StatusNotFound)
+}
+
+// serve writes a response body, paging arrays.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, body json.RawMessage, limit, after int) {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		_, _ = w.Write(body)
+		return
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		writeError(w, http.StatusInternalServerError)
+		return
+	}
+	size := s.org.PageSize
+	if limit > 0 && limit < size {
+		size = limit
+	}
+	start := min(after, len(items))
+	end := min(start+size, len(items))
+	if end < len(items) {
+		next := *r.URL
+		next.Scheme, next.Host = "http", r.Host
+		query := next.Query()
+		query.Set("after", strconv.Itoa(end))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	}
+	page := items[start:end]
+	if page == nil {
+		page = []json.RawMessage{}
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+// writeError writes an Okta-style error response.
+func writeError(w http.ResponseWriter, status int) {
+	codes := map[int]string{
+		http.StatusUnauthorized: "E0000011",
+		http.StatusForbidden:    "E0000006",
+		http.StatusNotFound:     "E0000007",
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"errorCode":    codes[status],
+		"errorSummary": http.StatusText(status),
+	})
+}
+
+// resolveTimes replaces relative timestamp placeholders in a JSON document.
+func resolveTimes(body json.RawMessage, now time.Time) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	resolved, err := resolveValue(doc, now)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
+
+func resolveValue(v any, now time.Time) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			resolved, err := resolveValue(value, now)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+	case []any:
+		for i, value := range v {
+			resolved, err := resolveValue(value, now)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+	case string:
+		m := relativeTime.FindStringSubmatch(v)
+		if m == nil {
+			return v, nil
+		}
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, err
+		}
+		unit := map[string]time.Duration{"d": 24 * time.Hour, "h": time.Hour, "m": time.Minute}[m[2]]
+		t := now.Add(time.Duration(n) * unit)
+		if m[3] != "" {
+			return json.Number(strconv.FormatInt(t.UnixMilli(), 10)), nil
+		}
+		return t.Format("2006-01-02T15:04:05.000Z"), nil
+	}
+	return v, nil
+}
+
+// Ensure Server implements http.Handler.
+var _ http.Handler = (*Server)(nil)
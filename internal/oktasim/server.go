@@ -0,0 +1,226 @@
+// Package oktasim provides a synthetic Okta org for load and resilience testing.
+//
+// Users, apps, and policies are generated deterministically from their index
+// on each request, so very large orgs (100k+ users) cost no memory up front.
+package oktasim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// pageLimit is the maximum page size served, matching Okta's list APIs.
+const pageLimit = 200
+
+// Options parameterizes the synthetic org.
+type Options struct {
+	Users            int           // Number of users
+	Apps             int           // Number of applications
+	Policies         int           // Number of sign-on policies (one rule each)
+	MFAPercent       int           // Percentage of users with an active factor (0-100)
+	Latency          time.Duration // Added to every response
+	RateLimitEvery   int           // Respond 429 to every Nth request (0 disables)
+	RateLimitBackoff time.Duration // Reset window advertised on injected 429s
+}
+
+// Server serves a synthetic Okta org over the Okta REST API.
+type Server struct {
+	opts     Options
+	requests atomic.Int64
+	limited  atomic.Int64
+}
+
+// New creates a synthetic org server.
+func New(opts Options) *Server {
+	return &Server{opts: opts}
+}
+
+// Requests returns the number of requests served, including injected 429s.
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// RateLimited returns the number of injected 429 responses.
+func (s *Server) RateLimited() int64 {
+	return s.limited.Load()
+}
+
+// ExpectedUsers returns the number of non-deprovisioned users in the org.
+func (s *Server) ExpectedUsers() int {
+	count := 0
+	for i := 0; i < s.opts.Users; i++ {
+		if userStatus(i) != "DEPROVISIONED" {
+			count++
+		}
+	}
+	return count
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := s.requests.Add(1)
+	if s.opts.Latency > 0 {
+		time.Sleep(s.opts.Latency)
+	}
+
+	if s.opts.RateLimitEvery > 0 && n%int64(s.opts.RateLimitEvery) == 0 {
+		s.limited.Add(1)
+		reset := time.Now().Add(s.opts.RateLimitBackoff)
+		w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	path := r.URL.Path
+
+	switch {
+	case path == "/api/v1/users":
+		s.servePage(w, r, s.opts.Users, user)
+	case strings.HasPrefix(path, "/api/v1/users/") && strings.HasSuffix(path, "/factors"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/api/v1/users/"), "/factors")
+		s.serveFactors(w, id)
+	case path == "/api/v1/apps":
+		s.servePage(w, r, s.opts.Apps, app)
+	case path == "/api/v1/policies":
+		s.servePolicies(w, r.URL.Query().Get("type"))
+	case strings.HasPrefix(path, "/api/v1/policies/") && strings.HasSuffix(path, "/rules"):
+		writeJSON(w, []any{signOnRule()})
+	case path == "/api/v1/org":
+		writeJSON(w, map[string]any{"id": "00osim", "subdomain": "sim", "companyName": "Simulated Org", "status": "ACTIVE"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]any{"errorCode": "E0000007", "errorSummary": "Not found: " + path})
+	}
+}
+
+// servePage serves one page of a generated list using index cursors.
+func (s *Server) servePage(w http.ResponseWriter, r *http.Request, total int, item func(int) map[string]any) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("after"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > pageLimit {
+		limit = pageLimit
+	}
+	end := min(start+limit, total)
+
+	if end < total {
+		next := fmt.Sprintf("http://%s%s?after=%d&limit=%d", r.Host, r.URL.Path, end, limit)
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+
+	items := make([]map[string]any, 0, max(end-start, 0))
+	for i := start; i < end; i++ {
+		items = append(items, item(i))
+	}
+	writeJSON(w, items)
+}
+
+// serveFactors serves the factors for a generated user.
+func (s *Server) serveFactors(w http.ResponseWriter, id string) {
+	i, err := strconv.Atoi(strings.TrimPrefix(id, "00u"))
+	if err != nil || i >= s.opts.Users {
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]any{"errorCode": "E0000007", "errorSummary": "Not found: " + id})
+		return
+	}
+
+	factors := []map[string]any{}
+	if i%100 < s.opts.MFAPercent {
+		factors = append(factors, map[string]any{"id": "fp" + id, "factorType": "push", "provider": "OKTA", "status": "ACTIVE"})
+		if i%10 == 0 {
+			factors = append(factors, map[string]any{"id": "fw" + id, "factorType": "webauthn", "provider": "FIDO", "status": "ACTIVE"})
+		}
+	}
+	writeJSON(w, factors)
+}
+
+// servePolicies serves generated sign-on policies.
+func (s *Server) servePolicies(w http.ResponseWriter, policyType string) {
+	policies := []map[string]any{}
+	if policyType == "OKTA_SIGN_ON" {
+		for i := 0; i < s.opts.Policies; i++ {
+			policies = append(policies, map[string]any{"id": fmt.Sprintf("00p%04d", i), "name": fmt.Sprintf("Policy %d", i), "type": policyType, "status": "ACTIVE", "priority": i + 1})
+		}
+	}
+	writeJSON(w, policies)
+}
+
+// userStatus returns the deterministic status of user i.
+func userStatus(i int) string {
+	switch {
+	case i%30 == 29:
+		return "DEPROVISIONED"
+	case i%50 == 49:
+		return "LOCKED_OUT"
+	case i%40 == 39:
+		return "PASSWORD_EXPIRED"
+	}
+	return "ACTIVE"
+}
+
+// user generates user i with a realistically sized profile.
+func user(i int) map[string]any {
+	lastLogin := time.Now().AddDate(0, 0, -1)
+	if i%5 == 0 {
+		lastLogin = time.Now().AddDate(0, 0, -200)
+	}
+	return map[string]any{
+		"id":        fmt.Sprintf("00u%d", i),
+		"status":    userStatus(i),
+		"created":   "2020-01-01T00:00:00.000Z",
+		"lastLogin": lastLogin.UTC().Format(time.RFC3339),
+		"profile": map[string]any{
+			"login":      fmt.Sprintf("user%d@sim.example.com", i),
+			"email":      fmt.Sprintf("user%d@sim.example.com", i),
+			"firstName":  "Sim",
+			"lastName":   fmt.Sprintf("User%d", i),
+			"department": "Engineering",
+			"title":      strings.Repeat("x", 256),
+		},
+	}
+}
+
+// app generates application i across common sign-on modes.
+func app(i int) map[string]any {
+	modes := []string{"SAML_2_0", "OPENID_CONNECT", "BROWSER_PLUGIN", "WS_FEDERATION", "BOOKMARK"}
+	features := []string{}
+	if i%3 == 0 {
+		features = append(features, "PUSH_NEW_USERS", "PUSH_USER_DEACTIVATION")
+	}
+	return map[string]any{
+		"id":         fmt.Sprintf("0oa%d", i),
+		"name":       fmt.Sprintf("app_%d", i),
+		"label":      fmt.Sprintf("App %d", i),
+		"status":     "ACTIVE",
+		"signOnMode": modes[i%len(modes)],
+		"features":   features,
+	}
+}
+
+// signOnRule generates an active sign-on rule requiring MFA.
+func signOnRule() map[string]any {
+	return map[string]any{
+		"id":     "0prsim",
+		"status": "ACTIVE",
+		"actions": map[string]any{
+			"signon": map[string]any{
+				"access":        "ALLOW",
+				"requireFactor": true,
+				"session": map[string]any{
+					"maxSessionIdleMinutes":     120,
+					"maxSessionLifetimeMinutes": 720,
+				},
+			},
+		},
+	}
+}
+
+// writeJSON encodes v as the response body.
+func writeJSON(w http.ResponseWriter, v any) {
+	_ = json.NewEncoder(w).Encode(v)
+}
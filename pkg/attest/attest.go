@@ -0,0 +1,154 @@
+// Package attest signs and verifies collector output documents with
+// Ed25519, so evidence can be checked for modification after collection.
+//
+// Documents are signed in a canonical JSON form (compact, object keys
+// sorted) rather than as emitted bytes, since the epack runner re-encodes
+// artifacts when it writes them. Verifiers canonicalize the document they
+// received the same way:
+//
+//	var doc any
+//	_ = json.Unmarshal(artifactBytes, &doc)
+//	err := attest.Verify(publicKey, sig, doc)
+package attest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Algorithm identifies the signature scheme in signature documents.
+const Algorithm = "ed25519"
+
+// keyIDLength is the number of hex characters of the public key hash used
+// as the default key ID.
+const keyIDLength = 16
+
+// ErrInvalidSignature is returned when a document does not match its signature.
+var ErrInvalidSignature = errors.New("signature does not match document")
+
+// Signer signs documents with an Ed25519 private key.
+type Signer struct {
+	KeyID string
+	key   ed25519.PrivateKey
+}
+
+// Signature is the detached signature of a single document.
+type Signature struct {
+	Path      string `json:"path"`      // Artifact path of the signed document
+	SHA256    string `json:"sha256"`    // Hex SHA-256 of the canonical document
+	Signature string `json:"signature"` // Base64 Ed25519 signature of the canonical document
+}
+
+// Manifest holds the signatures for every document of one run.
+type Manifest struct {
+	Algorithm  string      `json:"algorithm"`
+	KeyID      string      `json:"key_id"`
+	PublicKey  string      `json:"public_key"` // Base64 raw Ed25519 public key
+	Signatures []Signature `json:"signatures"`
+}
+
+// NewSigner parses a PEM-encoded PKCS#8 Ed25519 private key, as produced by
+// "openssl genpkey -algorithm ed25519". An empty keyID defaults to a prefix
+// of the SHA-256 of the public key.
+func NewSigner(keyPEM []byte, keyID string) (*Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key is %T, expected an Ed25519 key", parsed)
+	}
+
+	if keyID == "" {
+		keyID = KeyID(key.Public().(ed25519.PublicKey))
+	}
+	return &Signer{KeyID: keyID, key: key}, nil
+}
+
+// KeyID returns the default key ID for a public key.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:])[:keyIDLength]
+}
+
+// PublicKey returns the signer's public key.
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// Manifest returns an empty manifest describing the signer's key.
+func (s *Signer) Manifest() *Manifest {
+	return &Manifest{
+		Algorithm:  Algorithm,
+		KeyID:      s.KeyID,
+		PublicKey:  base64.StdEncoding.EncodeToString(s.PublicKey()),
+		Signatures: []Signature{},
+	}
+}
+
+// Sign returns the signature of doc, recorded under the given artifact path.
+func (s *Signer) Sign(path string, doc any) (Signature, error) {
+	data, err := Canonicalize(doc)
+	if err != nil {
+		return Signature{}, err
+	}
+	sum := sha256.Sum256(data)
+	return Signature{
+		Path:      path,
+		SHA256:    hex.EncodeToString(sum[:]),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, data)),
+	}, nil
+}
+
+// Verify checks that sig is a valid signature of doc by pub.
+func Verify(pub ed25519.PublicKey, sig Signature, doc any) error {
+	data, err := Canonicalize(doc)
+	if err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", err)
+	}
+	if !ed25519.Verify(pub, data, raw) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Canonicalize returns the canonical JSON encoding of v: compact, with
+// object keys sorted and numbers kept as written. Structs and the maps a
+// verifier decodes them into canonicalize identically.
+func Canonicalize(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding document: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+
+	// encoding/json sorts map keys
+	data, err = json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("encoding document: %w", err)
+	}
+	return data, nil
+}
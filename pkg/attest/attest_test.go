@@ -0,0 +1,85 @@
+package attest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+type testDoc struct {
+	Zeta  int    `json:"zeta"`
+	Alpha string `json:"alpha"`
+	Ratio int    `json:"ratio"`
+}
+
+func newTestSigner(t *testing.T) *Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), "")
+	if err != nil {
+		t.Fatalf("NewSigner error = %v", err)
+	}
+	return signer
+}
+
+func TestSignVerify(t *testing.T) {
+	signer := newTestSigner(t)
+	doc := testDoc{Zeta: 1, Alpha: "a<b", Ratio: 87}
+
+	sig, err := signer.Sign("artifacts/okta.json", doc)
+	if err != nil {
+		t.Fatalf("Sign error = %v", err)
+	}
+
+	// A verifier only has the emitted JSON, decoded generically
+	emitted, _ := json.MarshalIndent(doc, "", "  ")
+	var decoded any
+	if err := json.Unmarshal(emitted, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(signer.PublicKey(), sig, decoded); err != nil {
+		t.Errorf("Verify error = %v", err)
+	}
+
+	// Any modification invalidates the signature
+	decoded.(map[string]any)["ratio"] = 100
+	if err := Verify(signer.PublicKey(), sig, decoded); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	got, err := Canonicalize(testDoc{Zeta: 1, Alpha: "x", Ratio: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"alpha":"x","ratio":2,"zeta":1}`
+	if string(got) != want {
+		t.Errorf("Canonicalize = %s, want %s", got, want)
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	signer := newTestSigner(t)
+	if len(signer.KeyID) != keyIDLength {
+		t.Errorf("expected default key ID of length %d, got %q", keyIDLength, signer.KeyID)
+	}
+	if signer.KeyID != KeyID(signer.PublicKey()) {
+		t.Error("expected default key ID derived from the public key")
+	}
+
+	if _, err := NewSigner([]byte("not pem"), ""); err == nil {
+		t.Error("expected error for non-PEM key")
+	}
+}
@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// signInEventTypes are the System Log events that show a user actively
+// authenticating, including through federation and app-level SSO that may
+// not update the user's lastLogin.
+var signInEventTypes = []string{
+	EventTypeSessionStart,
+	EventTypeAuthSSO,
+	EventTypeAuthViaIDP,
+}
+
+// collectSignInActivity returns the most recent successful sign-in per user
+// ID within the System Log lookback window.
+func (c *Collector) collectSignInActivity(ctx context.Context) (map[string]time.Time, error) {
+	since, until := c.logWindow("users", min(c.config.SystemLogLookbackDays, MaxSystemLogLookbackDays))
+
+	clauses := make([]string, len(signInEventTypes))
+	for i, eventType := range signInEventTypes {
+		clauses[i] = fmt.Sprintf("eventType eq %q", eventType)
+	}
+	filter := fmt.Sprintf("(%s) and outcome.result eq %q", strings.Join(clauses, " or "), OutcomeSuccess)
+
+	lastSeen := make(map[string]time.Time)
+	events := 0
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		events++
+		if events%logStatusInterval == 0 {
+			c.status(fmt.Sprintf("Read %d sign-in events...", events))
+		}
+		if event.Actor.Type != LogActorUser || event.Actor.ID == "" {
+			return nil
+		}
+		if event.Published.After(lastSeen[event.Actor.ID]) {
+			lastSeen[event.Actor.ID] = event.Published
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lastSeen, nil
+}
+
+// lastActivity returns the later of the user's lastLogin and their most
+// recent sign-in seen in the System Log.
+func lastActivity(user okta.User, lastSeen map[string]time.Time) time.Time {
+	if seen, ok := lastSeen[user.ID]; ok && seen.After(user.LastLogin) {
+		return seen
+	}
+	return user.LastLogin
+}
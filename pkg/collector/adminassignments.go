@@ -0,0 +1,220 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// AdminAssignments reports how admin roles are granted: through group
+// membership or directly to users.
+type AdminAssignments struct {
+	Admins       int `json:"admins"`                       // Users holding an admin role
+	Grants       int `json:"grants"`                       // Admin role grants held by those users
+	GroupGrants  int `json:"group_grants"`                 // Grants received through group membership
+	DirectGrants int `json:"direct_grants"`                // Grants assigned directly to a user
+	GroupBased   int `json:"group_based" schema:"percent"` // % of grants received through groups
+	DirectAdmins int `json:"direct_admins"`                // Users with at least one direct grant
+
+	// Admins whose email domain is not a primary domain; set only when
+	// primary_email_domains is configured
+	ExternalAdmins *int `json:"external_admins,omitempty"`
+
+	// Admins with no Admin Console sign-in in the last DormantAdminDays;
+	// set only with System Log enrichment
+	DormantAdmins *int `json:"dormant_admins,omitempty"`
+}
+
+// AdminGroup is a group that confers admin roles on its members.
+type AdminGroup struct {
+	ID    string   `json:"id" proto:"1"`
+	Name  string   `json:"name" proto:"2"`
+	Roles []string `json:"roles" proto:"3"` // Role types, e.g. SUPER_ADMIN
+}
+
+// collectAdminAssignments counts admin role grants by assignment type and,
+// with primary email domains configured, external admins. In detail mode it
+// adds admin groups and external admins to evidence. It returns nil if role
+// assignments can't be read.
+func (c *Collector) collectAdminAssignments(ctx context.Context, evidence *Evidence) *AdminAssignments {
+	assignees, err := c.client.FetchRoleAssignees(ctx)
+	if err != nil {
+		c.skip("admin_assignments", ScopeRolesRead, err)
+		return nil
+	}
+
+	result := &AdminAssignments{}
+	var admins, groupAdmins []string
+	for _, assignee := range assignees {
+		roles, err := c.client.FetchUserRoles(ctx, assignee.ID)
+		if err != nil {
+			c.skip("admin_assignments", ScopeRolesRead, err)
+			return nil
+		}
+		direct, viaGroup := 0, 0
+		for _, role := range roles {
+			if role.AssignmentType == AssignmentTypeGroup {
+				viaGroup++
+			} else {
+				direct++
+			}
+		}
+		if direct+viaGroup == 0 {
+			continue
+		}
+
+		result.Admins++
+		admins = append(admins, assignee.ID)
+		result.DirectGrants += direct
+		result.GroupGrants += viaGroup
+		if direct > 0 {
+			result.DirectAdmins++
+		}
+		if viaGroup > 0 {
+			groupAdmins = append(groupAdmins, assignee.ID)
+		}
+	}
+	result.Grants = result.DirectGrants + result.GroupGrants
+	result.GroupBased = percent(result.GroupGrants, result.Grants)
+
+	if len(c.config.PrimaryEmailDomains) > 0 {
+		if external, err := c.collectExternalAdmins(ctx, admins); err == nil {
+			count := len(external)
+			result.ExternalAdmins = &count
+			if evidence != nil {
+				evidence.ExternalAdmins = external
+			}
+		}
+	}
+
+	if c.config.SystemLogLookbackDays > 0 {
+		if dormant, err := c.collectDormantAdmins(ctx, admins); err == nil {
+			count := len(dormant)
+			result.DormantAdmins = &count
+			if evidence != nil {
+				evidence.DormantAdmins = c.adminRefs(ctx, dormant)
+			}
+		}
+	}
+
+	if evidence != nil {
+		evidence.AdminGroups = c.collectAdminGroups(ctx, groupAdmins)
+	}
+	return result
+}
+
+// collectDormantAdmins returns the IDs of the admins with no Admin Console
+// sign-in in the System Log over the last DormantAdminDays, the candidates
+// for role removal. The window is fixed rather than following
+// system_log_lookback_days, since a shorter window would flag admins who
+// sign in monthly.
+func (c *Collector) collectDormantAdmins(ctx context.Context, userIDs []string) ([]string, error) {
+	until := time.Now()
+	since := until.AddDate(0, 0, -DormantAdminDays)
+	filter := fmt.Sprintf("eventType eq %q and outcome.result eq %q", EventTypeAdminAccess, OutcomeSuccess)
+
+	active := make(map[string]bool)
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		if event.Actor.Type == LogActorUser {
+			active[event.Actor.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dormant := []string{}
+	for _, userID := range userIDs {
+		if !active[userID] {
+			dormant = append(dormant, userID)
+		}
+	}
+	return dormant, nil
+}
+
+// adminRefs returns evidence entries for the given admins. It returns nil
+// if any lookup fails.
+func (c *Collector) adminRefs(ctx context.Context, userIDs []string) []UserRef {
+	refs := make([]UserRef, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := c.client.FetchUser(ctx, userID)
+		if err != nil {
+			return nil
+		}
+		refs = append(refs, c.userRef(*user))
+	}
+	return refs
+}
+
+// collectExternalAdmins returns the admins whose email domain is not one of
+// the primary email domains.
+func (c *Collector) collectExternalAdmins(ctx context.Context, userIDs []string) ([]UserRef, error) {
+	external := []UserRef{}
+	for _, userID := range userIDs {
+		user, err := c.client.FetchUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isPrimaryEmailDomain(user.Profile.Email, c.config.PrimaryEmailDomains) {
+			external = append(external, c.userRef(*user))
+		}
+	}
+	return external, nil
+}
+
+// isPrimaryEmailDomain reports whether email belongs to one of domains or
+// a subdomain of one.
+func isPrimaryEmailDomain(email string, domains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, primary := range domains {
+		primary = strings.ToLower(strings.TrimPrefix(primary, "@"))
+		if domain == primary || strings.HasSuffix(domain, "."+primary) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectAdminGroups finds the groups conferring admin roles on the given
+// users. It returns nil if any lookup fails.
+func (c *Collector) collectAdminGroups(ctx context.Context, userIDs []string) []AdminGroup {
+	adminGroups := []AdminGroup{}
+	checked := make(map[string]bool)
+	for _, userID := range userIDs {
+		groups, err := c.client.FetchUserGroups(ctx, userID)
+		if err != nil {
+			return nil
+		}
+		for _, group := range groups {
+			if checked[group.ID] {
+				continue
+			}
+			checked[group.ID] = true
+
+			roles, err := c.client.FetchGroupRoles(ctx, group.ID)
+			if err != nil {
+				return nil
+			}
+			if len(roles) == 0 {
+				continue
+			}
+			adminGroup := AdminGroup{ID: group.ID, Name: group.Profile.Name, Roles: []string{}}
+			for _, role := range roles {
+				if !slices.Contains(adminGroup.Roles, role.Type) {
+					adminGroup.Roles = append(adminGroup.Roles, role.Type)
+				}
+			}
+			adminGroups = append(adminGroups, adminGroup)
+		}
+	}
+	return adminGroups
+}
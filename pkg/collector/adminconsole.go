@@ -0,0 +1,139 @@
+package collector
+
+import (
+	"context"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// AdminConsolePolicy describes the authentication policy protecting the
+// Okta Admin Console. Each requirement holds only if every active ALLOW rule
+// enforces it, since any such rule can grant access.
+type AdminConsolePolicy struct {
+	PolicyID                  string `json:"policy_id"`
+	AllowRules                int    `json:"allow_rules"`                  // Active rules that grant access
+	MFARequired               bool   `json:"mfa_required"`                 // Every allow rule requires two factors
+	PhishingResistantRequired bool   `json:"phishing_resistant_required"`  // Every allow rule requires a phishing-resistant possession factor
+	SessionLifetimeMaxMinutes *int   `json:"session_lifetime_max_minutes"` // Longest re-authentication interval; null if no rule sets one
+	NetworkRestricted         bool   `json:"network_restricted"`           // Every allow rule is limited to network zones
+}
+
+// isoDurationPattern matches the ISO 8601 durations Okta uses, e.g. PT2H or P1DT12H.
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// evaluateAppPolicy evaluates the authentication policy of an app, such as
+// the Admin Console. It returns nil if the app was not listed, has no
+// authentication policy (Classic Engine orgs), or the rules can't be read.
+func (c *Collector) evaluateAppPolicy(ctx context.Context, app *okta.Application) *AdminConsolePolicy {
+	if app == nil || app.Links.AccessPolicy == nil {
+		return nil
+	}
+
+	policyID := accessPolicyID(*app)
+	rules, err := c.client.FetchPolicyRules(ctx, policyID)
+	if err != nil {
+		return nil
+	}
+	return evaluateAccessRules(policyID, rules)
+}
+
+// accessPolicyID returns the ID of the authentication policy linked from an
+// app, or "" if it has none.
+func accessPolicyID(app okta.Application) string {
+	if app.Links.AccessPolicy == nil {
+		return ""
+	}
+	return path.Base(app.Links.AccessPolicy.Href)
+}
+
+// evaluateAccessRules evaluates the rules of an authentication policy.
+func evaluateAccessRules(policyID string, rules []okta.PolicyRule) *AdminConsolePolicy {
+	result := &AdminConsolePolicy{
+		PolicyID:                  policyID,
+		MFARequired:               true,
+		PhishingResistantRequired: true,
+		NetworkRestricted:         true,
+	}
+	for _, rule := range rules {
+		if rule.Status != StatusActive || rule.Actions.AppSignOn == nil || rule.Actions.AppSignOn.Access != AccessAllow {
+			continue
+		}
+		result.AllowRules++
+
+		method := rule.Actions.AppSignOn.VerificationMethod
+		if method.FactorMode != FactorMode2FA {
+			result.MFARequired = false
+		}
+		if !requiresPhishingResistant(method.Constraints) {
+			result.PhishingResistantRequired = false
+		}
+		if !isNetworkRestricted(rule.Conditions) {
+			result.NetworkRestricted = false
+		}
+		if minutes, ok := parseISODurationMinutes(method.ReauthenticateIn); ok {
+			if result.SessionLifetimeMaxMinutes == nil || minutes > *result.SessionLifetimeMaxMinutes {
+				result.SessionLifetimeMaxMinutes = &minutes
+			}
+		}
+	}
+
+	// Without allow rules nobody can sign in, which is restrictive but
+	// almost certainly a read problem; don't report it as protected.
+	if result.AllowRules == 0 {
+		result.MFARequired = false
+		result.PhishingResistantRequired = false
+		result.NetworkRestricted = false
+	}
+	return result
+}
+
+// requiresPhishingResistant reports whether any constraint requires a
+// phishing-resistant possession factor.
+func requiresPhishingResistant(constraints []okta.AuthenticatorConstraint) bool {
+	for _, constraint := range constraints {
+		if constraint.Possession != nil && constraint.Possession.PhishingResistant == ConstraintRequired {
+			return true
+		}
+	}
+	return false
+}
+
+// isNetworkRestricted reports whether a rule only applies on specific networks.
+func isNetworkRestricted(conditions okta.PolicyRuleConditions) bool {
+	network := conditions.Network
+	if network == nil {
+		return false
+	}
+	switch network.Connection {
+	case NetworkOnNetwork:
+		return true
+	case NetworkZone:
+		return len(network.Include) > 0
+	}
+	return false
+}
+
+// parseISODurationMinutes converts an ISO 8601 duration to whole minutes.
+// Durations too long to count in seconds are rejected.
+func parseISODurationMinutes(duration string) (int, bool) {
+	match := isoDurationPattern.FindStringSubmatch(duration)
+	if match == nil || duration == "P" || duration == "PT" {
+		return 0, false
+	}
+	total := 0
+	for i, unit := range []int{24 * 60 * 60, 60 * 60, 60, 1} {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil || n > (math.MaxInt-total)/unit {
+			return 0, false
+		}
+		total += n * unit
+	}
+	return total / 60, true
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// AgentHealth summarizes the connectivity of on-premises directory agents.
+// The embedded totals cover all agent types.
+type AgentHealth struct {
+	AgentTypeHealth
+	AD   AgentTypeHealth `json:"ad"`   // Active Directory agents
+	LDAP AgentTypeHealth `json:"ldap"` // LDAP agents
+	IWA  AgentTypeHealth `json:"iwa"`  // IWA web agents (desktop SSO)
+}
+
+// AgentTypeHealth counts agents by connectivity.
+type AgentTypeHealth struct {
+	Total                      int  `json:"total"`                          // Agents installed
+	Connected                  int  `json:"connected"`                      // Operational or degraded agents
+	Disconnected               int  `json:"disconnected"`                   // Disrupted or inactive agents
+	MaxDaysSinceLastConnection *int `json:"max_days_since_last_connection"` // Longest silence of any agent; null without agents
+}
+
+// collectAgentHealth fetches directory agent pools. It returns nil if no
+// pool type could be read, for example without the okta.agentPools.read scope.
+func (c *Collector) collectAgentHealth(ctx context.Context) *AgentHealth {
+	health := &AgentHealth{}
+	now := time.Now()
+	fetched := false
+	var denied error
+
+	for _, pool := range []struct {
+		poolType string
+		health   *AgentTypeHealth
+	}{
+		{AgentPoolAD, &health.AD},
+		{AgentPoolLDAP, &health.LDAP},
+		{AgentPoolIWA, &health.IWA},
+	} {
+		pools, err := c.client.FetchAgentPools(ctx, pool.poolType)
+		if err != nil {
+			denied = err
+			continue
+		}
+		fetched = true
+		for _, p := range pools {
+			for _, agent := range p.Agents {
+				if agent.IsHidden {
+					continue
+				}
+				pool.health.add(agent, now)
+				health.add(agent, now)
+			}
+		}
+	}
+
+	if !fetched {
+		c.skip("agents", ScopeAgentPoolsRead, denied)
+		return nil
+	}
+	return health
+}
+
+// add counts one agent.
+func (h *AgentTypeHealth) add(agent okta.Agent, now time.Time) {
+	h.Total++
+	switch agent.OperationalStatus {
+	case AgentStatusOperational, AgentStatusDegraded:
+		h.Connected++
+	case AgentStatusDisrupted, AgentStatusInactive:
+		h.Disconnected++
+	}
+
+	if agent.LastConnection > 0 {
+		days := max(0, int(now.Sub(time.UnixMilli(agent.LastConnection)).Hours()/24))
+		if h.MaxDaysSinceLastConnection == nil || days > *h.MaxDaysSinceLastConnection {
+			h.MaxDaysSinceLastConnection = &days
+		}
+	}
+}
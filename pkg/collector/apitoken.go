@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// apiTokenDeprecation is the advice reported with every API token collection.
+const apiTokenDeprecation = "API tokens act with the full permissions of the admin who created them and can't be limited to read-only scopes; use OAuth 2.0 with a service app (client_id and OKTA_PRIVATE_KEY) instead"
+
+// APITokenStatus describes the API (SSWS) token a collection ran with.
+type APITokenStatus struct {
+	Name               string  `json:"name"`
+	CreatedAt          string  `json:"created_at"`
+	LastUpdatedAt      string  `json:"last_updated_at"`
+	ExpiresAt          *string `json:"expires_at"`     // When the token expires unless used again; null if Okta reports none
+	DaysRemaining      *int    `json:"days_remaining"` // Days until expires_at; null if Okta reports none
+	TokenWindow        string  `json:"token_window"`   // Idle expiry window as an ISO 8601 duration, e.g. P30D
+	DeprecationWarning string  `json:"deprecation_warning"`
+}
+
+// usesAPIToken reports whether the collector authenticates with an API
+// token: one is configured, OAuth credentials are not, and responses are
+// not replayed from a fixture.
+func (config Config) usesAPIToken() bool {
+	oauth := config.ClientID != "" && config.PrivateKey != ""
+	return config.APIToken != "" && !oauth && config.FixtureMode != FixtureModeReplay
+}
+
+// checkAPIToken verifies the API token before anything else is read, so a
+// revoked or expired token fails in one request instead of partway through
+// the user scan. It returns an error only if Okta rejects the token; if the
+// metadata can't be read otherwise, the status is nil.
+func (c *Collector) checkAPIToken(ctx context.Context) (*APITokenStatus, error) {
+	c.status(fmt.Sprintf("Warning: %s", apiTokenDeprecation))
+
+	token, err := c.client.FetchCurrentAPIToken(ctx)
+	if err != nil {
+		var apiErr *okta.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("API token is invalid, expired, or revoked: %w", err)
+		}
+		c.status(fmt.Sprintf("Warning: API token metadata unavailable: %v", err))
+		return nil, nil
+	}
+
+	result := &APITokenStatus{
+		Name:               token.Name,
+		CreatedAt:          token.Created.UTC().Format(time.RFC3339),
+		LastUpdatedAt:      token.LastUpdated.UTC().Format(time.RFC3339),
+		TokenWindow:        token.TokenWindow,
+		DeprecationWarning: apiTokenDeprecation,
+	}
+	if token.ExpiresAt != nil {
+		expires := token.ExpiresAt.UTC().Format(time.RFC3339)
+		days := max(0, int(time.Until(*token.ExpiresAt).Hours()/24))
+		result.ExpiresAt = &expires
+		result.DaysRemaining = &days
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package collector
+
+import "github.com/locktivity/epack-collector-okta/pkg/okta"
+
+// AppPolicy maps an app to the authentication policy that protects it.
+// Requirements are null when the policy is inactive or its rules can't be
+// read.
+type AppPolicy struct {
+	ID                        string `json:"id" proto:"1"`
+	Label                     string `json:"label" proto:"2"`
+	PolicyID                  string `json:"policy_id" proto:"3"`
+	PolicyName                string `json:"policy_name" proto:"4"`
+	DefaultPolicy             bool   `json:"default_policy" proto:"5"`              // The org's built-in Default Policy
+	MFARequired               *bool  `json:"mfa_required" proto:"6"`                // Every allow rule requires two factors
+	PhishingResistantRequired *bool  `json:"phishing_resistant_required" proto:"7"` // Every allow rule requires a phishing-resistant factor
+}
+
+// accessPolicySummary is an authentication policy with its evaluated rules.
+type accessPolicySummary struct {
+	policy     okta.Policy
+	evaluation *AdminConsolePolicy // Nil if the policy is inactive or its rules can't be read
+}
+
+// mapAppPolicies fills in the policy name and requirements of each app's
+// authentication policy. It returns nil on Classic Engine, where apps have
+// no authentication policy.
+func mapAppPolicies(apps []AppPolicy, policies map[string]accessPolicySummary) []AppPolicy {
+	if len(apps) == 0 {
+		return nil
+	}
+	for i := range apps {
+		summary, ok := policies[apps[i].PolicyID]
+		if !ok {
+			continue
+		}
+		apps[i].PolicyName = summary.policy.Name
+		apps[i].DefaultPolicy = summary.policy.System
+		if summary.evaluation == nil {
+			continue
+		}
+		apps[i].MFARequired = &summary.evaluation.MFARequired
+		apps[i].PhishingResistantRequired = &summary.evaluation.PhishingResistantRequired
+	}
+	return apps
+}
@@ -0,0 +1,30 @@
+package collector
+
+import "context"
+
+// Automations counts the org's lifecycle automations (Workflow >
+// Automations), such as inactivity and password expiry actions.
+type Automations struct {
+	Total    int `json:"total"`    // Configured automations
+	Active   int `json:"active"`   // Automations that run on schedule
+	Inactive int `json:"inactive"` // Configured but disabled automations
+}
+
+// collectAutomations counts lifecycle automations, which Okta stores as
+// USER_LIFECYCLE policies. It returns nil if they can't be read.
+func (c *Collector) collectAutomations(ctx context.Context) *Automations {
+	policies, err := c.client.FetchPolicies(ctx, PolicyTypeUserLifecycle)
+	if err != nil {
+		return nil
+	}
+
+	result := &Automations{Total: len(policies)}
+	for _, policy := range policies {
+		if policy.Status == StatusActive {
+			result.Active++
+		} else {
+			result.Inactive++
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Benchmarks for the user pipeline, the hot path of a large org. Each pass
+// runs under a pprof label, so a profile can be narrowed to one:
+//
+//	go test ./pkg/collector -run '^$' -bench UserPipeline -cpuprofile cpu.out
+//	go tool pprof -tagfocus=phase=user_factors cpu.out
+var benchUsers = flag.Int("bench.users", 100000, "synthetic user count for benchmarks")
+
+// syntheticUsers returns a mock org of n users with a realistic mix of
+// statuses, activity, and factors.
+func syntheticUsers(n int) *mockOktaClient {
+	now := time.Now()
+	statuses := []string{StatusActive, StatusActive, StatusActive, StatusActive, StatusActive,
+		StatusActive, StatusActive, StatusLockedOut, StatusPasswordExpired, StatusDeprovisioned}
+	factorSets := [][]okta.Factor{
+		{{FactorType: FactorTypeWebAuthn, Status: StatusActive}, {FactorType: "push", Status: StatusActive}},
+		{{FactorType: "push", Status: StatusActive}},
+		{{FactorType: "sms", Status: StatusActive}, {FactorType: "token:software:totp", Status: StatusActive}},
+		{{FactorType: FactorTypeSignedNonce, Status: StatusActive}},
+		nil,
+	}
+
+	mock := &mockOktaClient{factors: make(map[string][]okta.Factor, n)}
+	mock.users = make([]okta.User, n)
+	for i := range mock.users {
+		id := fmt.Sprintf("00u%07d", i)
+		mock.users[i] = okta.User{
+			ID:        id,
+			Status:    statuses[i%len(statuses)],
+			LastLogin: now.AddDate(0, 0, -(i % 180)),
+			Profile: okta.UserProfile{
+				Login: id + "@example.com",
+				Email: id + "@example.com",
+			},
+		}
+		mock.factors[id] = factorSets[i%len(factorSets)]
+	}
+	return mock
+}
+
+// BenchmarkUserPipeline runs both user passes, listing and factors, over
+// the synthetic org in each output mode.
+func BenchmarkUserPipeline(b *testing.B) {
+	mock := syntheticUsers(*benchUsers)
+	modes := []struct {
+		name   string
+		config Config
+	}{
+		{"summary", Config{}},
+		{"detail", Config{Detail: true}},
+		{"entities", Config{Entities: true}},
+	}
+	for _, mode := range modes {
+		b.Run(mode.name, func(b *testing.B) {
+			c := NewWithClient(mode.config, mock)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := c.collectUserMetrics(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/float64(*benchUsers), "ns/user")
+		})
+	}
+}
+
+// BenchmarkProcessUser measures the listing pass alone.
+func BenchmarkProcessUser(b *testing.B) {
+	mock := syntheticUsers(*benchUsers)
+	c := NewWithClient(Config{}, mock)
+	threshold := time.Now().AddDate(0, 0, -InactiveDaysThreshold)
+	b.ReportAllocs()
+	for b.Loop() {
+		metrics := &userMetricsCollector{}
+		for _, user := range mock.users {
+			c.processUser(user, threshold, metrics)
+		}
+	}
+}
+
+// BenchmarkProcessUserFactors measures the factor pass alone, without the
+// API latency that dominates it against a real org.
+func BenchmarkProcessUserFactors(b *testing.B) {
+	mock := syntheticUsers(*benchUsers)
+	c := NewWithClient(Config{}, mock)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		metrics := &userMetricsCollector{}
+		for _, user := range mock.users {
+			c.processUserFactors(ctx, user.ID, metrics)
+		}
+	}
+}
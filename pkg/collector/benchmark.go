@@ -0,0 +1,215 @@
+package collector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Benchmark is the posture checked against a baseline profile. Business
+// units held to different baselines select different profiles, so the
+// result is labeled with the profile it was checked against.
+type Benchmark struct {
+	Profile      string            `json:"profile"`       // Profile selected with the benchmark config key
+	Passed       int               `json:"passed"`        // Checks the posture meets
+	Failed       int               `json:"failed"`        // Checks the posture misses, without an exemption
+	RiskAccepted int               `json:"risk_accepted"` // Checks the posture misses under an exemption
+	NotEvaluated int               `json:"not_evaluated"` // Checks on metrics the run didn't report
+	Checks       []BenchmarkCheck  `json:"checks"`        // In profile order
+	Exemptions   []ExemptionResult `json:"exemptions"`    // Configured exemptions, in config order
+}
+
+// BenchmarkCheck is one threshold of a profile and how the posture fared.
+type BenchmarkCheck struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Metric      string   `json:"metric"`     // Metric name as in the CSV and Prometheus outputs
+	Comparison  string   `json:"comparison"` // at_least or at_most
+	Threshold   float64  `json:"threshold"`
+	Value       *float64 `json:"value"`  // null when the run didn't report the metric
+	Status      string   `json:"status"` // pass, fail, risk_accepted, or not_evaluated
+}
+
+// Exemption accepts the risk of a finding until it expires, so a known
+// exception is reported as risk_accepted instead of failing the check.
+type Exemption struct {
+	Finding       string `json:"finding"`       // Benchmark check ID, e.g. "idle-timeout"
+	ObjectID      string `json:"object_id"`     // Object the finding is about; the org domain for benchmark checks
+	Expires       string `json:"expires"`       // Last day (YYYY-MM-DD, UTC) the exemption applies
+	Justification string `json:"justification"` // Why the risk is accepted
+}
+
+// ExemptionResult echoes a configured exemption and whether it applied.
+type ExemptionResult struct {
+	Exemption
+	Status string `json:"status"` // applied, expired, or unused
+}
+
+// benchmarkRule is a threshold on one metric.
+type benchmarkRule struct {
+	id          string
+	description string
+	metric      string
+	comparison  string
+	threshold   float64
+}
+
+// benchmarkProfiles are the built-in baselines, keyed by profile name.
+// Booleans are metrics of 0 or 1, so "at least 1" requires a setting and
+// "at most 0" forbids it.
+var benchmarkProfiles = map[string][]benchmarkRule{
+	BenchmarkCIS12: {
+		{"mfa-coverage", "At least 95% of users are enrolled in MFA", "posture_mfa_coverage", BenchmarkAtLeast, 95},
+		{"mfa-required-all", "Every sign-on policy requires MFA", "policy_mfa_required_all", BenchmarkAtLeast, 1},
+		{"no-catch-all-without-mfa", "No sign-on policy's catch-all rule allows access without MFA", "policy_catch_all_allow_without_mfa", BenchmarkAtMost, 0},
+		{"no-security-question", "Security question is not accepted as a factor", "posture_security_question_enabled", BenchmarkAtMost, 0},
+		{"no-voice-factor", "Voice call is not accepted as a factor", "posture_voice_factor_enabled", BenchmarkAtMost, 0},
+		{"session-lifetime", "Sessions last at most 12 hours", "policy_session_lifetime_max_minutes", BenchmarkAtMost, 720},
+		{"idle-timeout", "Idle sessions end within 2 hours", "policy_idle_timeout_max_minutes", BenchmarkAtMost, 120},
+		{"inactive-users", "At most 10% of users are inactive", "users_inactive", BenchmarkAtMost, 10},
+		{"common-password-check", "Password policies reject common passwords", "password_policy_common_password_check", BenchmarkAtLeast, 1},
+		{"recovery-mfa", "Self-service password recovery requires MFA", "password_policy_recovery_mfa_required", BenchmarkAtLeast, 1},
+	},
+	BenchmarkInternalStrict: {
+		{"mfa-coverage", "Every user is enrolled in MFA", "posture_mfa_coverage", BenchmarkAtLeast, 100},
+		{"mfa-phishing-resistant", "At least 80% of users have a phishing-resistant factor", "posture_mfa_phishing_resistant", BenchmarkAtLeast, 80},
+		{"mfa-required-all", "Every sign-on policy requires MFA", "policy_mfa_required_all", BenchmarkAtLeast, 1},
+		{"no-catch-all-without-mfa", "No sign-on policy's catch-all rule allows access without MFA", "policy_catch_all_allow_without_mfa", BenchmarkAtMost, 0},
+		{"no-security-question", "Security question is not accepted as a factor", "posture_security_question_enabled", BenchmarkAtMost, 0},
+		{"no-voice-factor", "Voice call is not accepted as a factor", "posture_voice_factor_enabled", BenchmarkAtMost, 0},
+		{"no-sms-factor", "SMS is not accepted as a factor", "posture_sms_factor_enabled", BenchmarkAtMost, 0},
+		{"no-email-factor", "Email is not accepted as an MFA factor", "posture_email_factor_as_mfa_enabled", BenchmarkAtMost, 0},
+		{"push-number-challenge", "Okta Verify push requires number challenge", "push_protection_number_challenge_enforced", BenchmarkAtLeast, 1},
+		{"session-lifetime", "Sessions last at most 8 hours", "policy_session_lifetime_max_minutes", BenchmarkAtMost, 480},
+		{"idle-timeout", "Idle sessions end within 30 minutes", "policy_idle_timeout_max_minutes", BenchmarkAtMost, 30},
+		{"inactive-users", "At most 5% of users are inactive", "users_inactive", BenchmarkAtMost, 5},
+		{"everyone-apps", "At most 5% of apps are assigned to Everyone", "apps_assigned_to_everyone", BenchmarkAtMost, 5},
+		{"common-password-check", "Password policies reject common passwords", "password_policy_common_password_check", BenchmarkAtLeast, 1},
+		{"breached-password-protection", "Password policies reject breached passwords", "password_policy_breached_protection", BenchmarkAtLeast, 1},
+		{"recovery-mfa", "Self-service password recovery requires MFA", "password_policy_recovery_mfa_required", BenchmarkAtLeast, 1},
+		{"log-streaming", "System Log events are streamed to a SIEM", "log_streaming_active", BenchmarkAtLeast, 1},
+		{"no-support-access", "Okta Support has no access to the org", "support_access_enabled", BenchmarkAtMost, 0},
+	},
+}
+
+// BenchmarkProfiles returns the names of the built-in benchmark profiles,
+// sorted.
+func BenchmarkProfiles() []string {
+	names := make([]string, 0, len(benchmarkProfiles))
+	for name := range benchmarkProfiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// validateBenchmark reports an error for a profile that isn't built in.
+// An empty profile turns benchmarking off.
+func validateBenchmark(profile string) error {
+	if _, ok := benchmarkProfiles[profile]; profile != "" && !ok {
+		return fmt.Errorf("unknown benchmark %q (available: %v)", profile, BenchmarkProfiles())
+	}
+	return nil
+}
+
+// ValidateExemptions reports the first exemption that is incomplete or
+// whose expiry isn't a date.
+func ValidateExemptions(exemptions []Exemption) error {
+	for i, exemption := range exemptions {
+		switch {
+		case exemption.Finding == "":
+			return fmt.Errorf("exemptions[%d].finding is required", i)
+		case exemption.ObjectID == "":
+			return fmt.Errorf("exemptions[%d].object_id is required", i)
+		case exemption.Justification == "":
+			return fmt.Errorf("exemptions[%d].justification is required", i)
+		}
+		if _, err := exemption.expiry(); err != nil {
+			return fmt.Errorf("exemptions[%d].expires must be a date (YYYY-MM-DD), got %q", i, exemption.Expires)
+		}
+	}
+	return nil
+}
+
+// expiry returns the instant the exemption stops applying: the end of its
+// expiry day in UTC.
+func (e Exemption) expiry() (time.Time, error) {
+	day, err := time.Parse(time.DateOnly, e.Expires)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// evaluateBenchmark checks the posture's metrics against a profile's
+// thresholds. Checks on metrics the posture doesn't report, such as
+// sections Okta denied, are not evaluated rather than failed. A failed
+// check with an exemption for the org that hasn't expired at now is
+// risk-accepted instead.
+func evaluateBenchmark(profile string, posture *OrgPosture, exemptions []Exemption, now time.Time) *Benchmark {
+	values := make(map[string]float64)
+	for _, metric := range posture.Metrics() {
+		values[metric.Name] = metric.Value
+	}
+
+	result := &Benchmark{Profile: profile, Checks: []BenchmarkCheck{}, Exemptions: []ExemptionResult{}}
+	for _, exemption := range exemptions {
+		status := ExemptionUnused
+		if expiry, err := exemption.expiry(); err != nil || !now.Before(expiry) {
+			status = ExemptionExpired
+		}
+		result.Exemptions = append(result.Exemptions, ExemptionResult{Exemption: exemption, Status: status})
+	}
+
+	for _, rule := range benchmarkProfiles[profile] {
+		check := BenchmarkCheck{
+			ID:          rule.id,
+			Description: rule.description,
+			Metric:      rule.metric,
+			Comparison:  rule.comparison,
+			Threshold:   rule.threshold,
+			Status:      BenchmarkNotEvaluated,
+		}
+		if value, ok := values[rule.metric]; ok {
+			check.Value = &value
+			passed := value >= rule.threshold
+			if rule.comparison == BenchmarkAtMost {
+				passed = value <= rule.threshold
+			}
+			check.Status = BenchmarkFail
+			if passed {
+				check.Status = BenchmarkPass
+			} else if result.applyExemption(rule.id, posture.OrgDomain) {
+				check.Status = BenchmarkRiskAccepted
+			}
+		}
+
+		switch check.Status {
+		case BenchmarkPass:
+			result.Passed++
+		case BenchmarkFail:
+			result.Failed++
+		case BenchmarkRiskAccepted:
+			result.RiskAccepted++
+		default:
+			result.NotEvaluated++
+		}
+		result.Checks = append(result.Checks, check)
+	}
+	return result
+}
+
+// applyExemption marks the unexpired exemptions for a failed check on an
+// object as applied, and reports whether there were any.
+func (b *Benchmark) applyExemption(finding, objectID string) bool {
+	applied := false
+	for i, exemption := range b.Exemptions {
+		if exemption.Status == ExemptionExpired || exemption.Finding != finding || !strings.EqualFold(exemption.ObjectID, objectID) {
+			continue
+		}
+		b.Exemptions[i].Status = ExemptionApplied
+		applied = true
+	}
+	return applied
+}
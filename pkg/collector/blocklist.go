@@ -0,0 +1,42 @@
+package collector
+
+import "context"
+
+// BlocklistZones reports whether the org's blocklist network zones are
+// enforced by policy. A blocklist no active DENY rule refers to gives false
+// comfort.
+type BlocklistZones struct {
+	Total        int       `json:"total"`        // Active blocklist zones
+	Referenced   int       `json:"referenced"`   // Zones an active sign-on or authentication DENY rule applies to
+	Unreferenced []ZoneRef `json:"unreferenced"` // Zones no active DENY rule applies to
+}
+
+// ZoneRef identifies a network zone.
+type ZoneRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// collectBlocklistZones checks each active blocklist zone against the zones
+// that active DENY rules apply to. It returns nil if zones can't be read.
+func (c *Collector) collectBlocklistZones(ctx context.Context, denyZones map[string]bool) *BlocklistZones {
+	zones, err := c.client.FetchNetworkZones(ctx)
+	if err != nil {
+		c.skip("blocklist_zones", ScopeNetworkZonesRead, err)
+		return nil
+	}
+
+	result := &BlocklistZones{Unreferenced: []ZoneRef{}}
+	for _, zone := range zones {
+		if zone.Usage != ZoneUsageBlocklist || zone.Status != StatusActive {
+			continue
+		}
+		result.Total++
+		if denyZones[zone.ID] {
+			result.Referenced++
+		} else {
+			result.Unreferenced = append(result.Unreferenced, ZoneRef{ID: zone.ID, Name: zone.Name})
+		}
+	}
+	return result
+}
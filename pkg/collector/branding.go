@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Branding reports how the org's sign-in pages are customized. Code added
+// to a sign-in page runs where users type their credentials, and the
+// default Okta footer and help link tell a phisher which page to copy.
+type Branding struct {
+	CustomSignInCode  bool          `json:"custom_sign_in_code"` // Any brand's sign-in page HTML differs from Okta's default
+	OktaFooterExposed bool          `json:"okta_footer_exposed"` // Any brand shows the "Powered by Okta" footer
+	DefaultHelpLinks  bool          `json:"default_help_links"`  // Any brand's sign-in page links to Okta's help page
+	Brands            []BrandStatus `json:"brands"`
+}
+
+// BrandStatus is the sign-in page customization of one brand.
+type BrandStatus struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	IsDefault        bool     `json:"is_default"`
+	CustomSignInPage bool     `json:"custom_sign_in_page"` // The sign-in page was customized
+	CustomCode       bool     `json:"custom_code"`         // The customized HTML differs from Okta's default page
+	ExternalScripts  []string `json:"external_scripts"`    // Hosts of scripts the customized page adds, sorted
+	PoweredByOkta    bool     `json:"powered_by_okta"`     // The "Powered by Okta" footer is shown
+	DefaultHelpLink  bool     `json:"default_help_link"`   // The help link goes to Okta's help page
+}
+
+// scriptSourcePattern matches the source of a <script src="..."> tag.
+var scriptSourcePattern = regexp.MustCompile(`(?i)<script\b[^>]*\bsrc\s*=\s*["']([^"']+)["']`)
+
+// collectBranding fetches the org's brands and their sign-in pages. It
+// returns nil if the brands or a sign-in page can't be read.
+func (c *Collector) collectBranding(ctx context.Context) *Branding {
+	brands, err := c.client.FetchBrands(ctx)
+	if err != nil {
+		c.skip("branding", ScopeBrandsRead, err)
+		return nil
+	}
+
+	result := &Branding{Brands: []BrandStatus{}}
+	for _, brand := range brands {
+		status, err := c.brandStatus(ctx, brand)
+		if err != nil {
+			c.skip("branding", ScopeBrandsRead, err)
+			return nil
+		}
+		result.CustomSignInCode = result.CustomSignInCode || status.CustomCode
+		result.OktaFooterExposed = result.OktaFooterExposed || status.PoweredByOkta
+		result.DefaultHelpLinks = result.DefaultHelpLinks || status.DefaultHelpLink
+		result.Brands = append(result.Brands, status)
+	}
+	return result
+}
+
+// brandStatus compares a brand's customized sign-in page, if it has one,
+// with Okta's default page.
+func (c *Collector) brandStatus(ctx context.Context, brand okta.Brand) (BrandStatus, error) {
+	status := BrandStatus{
+		ID:              brand.ID,
+		Name:            brand.Name,
+		IsDefault:       brand.IsDefault,
+		ExternalScripts: []string{},
+		PoweredByOkta:   !brand.RemovePoweredByOkta,
+		DefaultHelpLink: true,
+	}
+
+	customized, err := c.client.FetchSignInPage(ctx, brand.ID, SignInPageCustomized)
+	var apiErr *okta.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return status, nil
+	}
+	if err != nil {
+		return BrandStatus{}, err
+	}
+	defaultPage, err := c.client.FetchSignInPage(ctx, brand.ID, SignInPageDefault)
+	if err != nil {
+		return BrandStatus{}, err
+	}
+
+	status.CustomSignInPage = true
+	status.DefaultHelpLink = customized.WidgetCustomizations.HelpURL == ""
+	if customized.PageContent != "" && customized.PageContent != defaultPage.PageContent {
+		status.CustomCode = true
+		defaultScripts := scriptHosts(defaultPage.PageContent)
+		for _, host := range scriptHosts(customized.PageContent) {
+			if !slices.Contains(defaultScripts, host) {
+				status.ExternalScripts = append(status.ExternalScripts, host)
+			}
+		}
+	}
+	return status, nil
+}
+
+// scriptHosts returns the hosts that a page's script tags load from,
+// sorted and without duplicates. Relative sources are left out.
+func scriptHosts(html string) []string {
+	var hosts []string
+	for _, match := range scriptSourcePattern.FindAllStringSubmatch(html, -1) {
+		source, err := url.Parse(match[1])
+		if err != nil || source.Host == "" {
+			continue
+		}
+		hosts = append(hosts, source.Host)
+	}
+	slices.Sort(hosts)
+	return slices.Compact(hosts)
+}
@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"context"
+	"slices"
+)
+
+// CaptchaSettings reports the CAPTCHA protection of end-user flows.
+type CaptchaSettings struct {
+	Configured    bool    `json:"configured"`     // A CAPTCHA instance is selected for the org
+	Provider      *string `json:"provider"`       // HCAPTCHA or RECAPTCHA_V2; null if unknown
+	SignIn        bool    `json:"sign_in"`        // Sign-in page is protected
+	Registration  bool    `json:"registration"`   // Self-service registration is protected
+	PasswordReset bool    `json:"password_reset"` // Self-service password reset is protected
+}
+
+// collectCaptcha fetches the org CAPTCHA settings. It returns nil if they
+// can't be read.
+func (c *Collector) collectCaptcha(ctx context.Context) *CaptchaSettings {
+	settings, err := c.client.FetchOrgCaptchaSettings(ctx)
+	if err != nil || settings == nil {
+		c.skip("captcha", ScopeCaptchasRead, err)
+		return nil
+	}
+
+	result := &CaptchaSettings{Configured: settings.CaptchaID != ""}
+	if !result.Configured {
+		return result
+	}
+	result.SignIn = slices.Contains(settings.EnabledPages, CaptchaPageSignIn)
+	result.Registration = slices.Contains(settings.EnabledPages, CaptchaPageRegistration)
+	result.PasswordReset = slices.Contains(settings.EnabledPages, CaptchaPagePasswordReset)
+
+	// Best-effort: the provider only adds context
+	captchas, err := c.client.FetchCaptchas(ctx)
+	if err != nil {
+		return result
+	}
+	for _, captcha := range captchas {
+		if captcha.ID == settings.CaptchaID {
+			provider := captcha.Type
+			result.Provider = &provider
+			break
+		}
+	}
+	return result
+}
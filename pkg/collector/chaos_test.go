@@ -0,0 +1,90 @@
+//go:build chaos
+// +build chaos
+
+package collector
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/internal/oktasim"
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Chaos flags. Org size comes from the simulator flags:
+//
+//	go test -tags=chaos ./pkg/collector -run TestChaos -v -args -sim.users=20000 -chaos.error-rate=0.05
+var (
+	chaosSeed          = flag.Uint64("chaos.seed", 1, "seed for fault draws")
+	chaosLatency       = flag.Duration("chaos.latency", 0, "latency added to every request")
+	chaosRateLimitRate = flag.Float64("chaos.ratelimit-rate", 0.005, "fraction of requests answered 429; each costs about a second of backoff")
+	chaosErrorRate     = flag.Float64("chaos.error-rate", 0.02, "fraction of requests answered 503")
+	chaosMalformedRate = flag.Float64("chaos.malformed-rate", 0.02, "fraction of responses cut off mid-document")
+)
+
+// TestChaos collects a synthetic org while faults hit the user and app
+// listings, whose pages are retried from their cursor, and the per-user
+// factor lookups, which degrade to a user without MFA. The collection must
+// still succeed and count every user and app.
+func TestChaos(t *testing.T) {
+	sim := oktasim.New(oktasim.Options{
+		Users:      *simUsers,
+		Apps:       *simApps,
+		Policies:   *simPolicies,
+		MFAPercent: 100,
+	})
+	server := httptest.NewServer(sim)
+	defer server.Close()
+
+	client := okta.NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("sim-token")
+	injector := okta.NewFaultInjector(okta.Faults{
+		Seed:            *chaosSeed,
+		Latency:         *chaosLatency,
+		RateLimitRate:   *chaosRateLimitRate,
+		ServerErrorRate: *chaosErrorRate,
+		MalformedRate:   *chaosMalformedRate,
+		Match: func(req *http.Request) bool {
+			return strings.HasPrefix(req.URL.Path, "/api/v1/users") || req.URL.Path == "/api/v1/apps"
+		},
+	})
+	client.Use(injector.Middleware())
+
+	start := time.Now()
+	c := NewWithClient(Config{OrgDomain: "sim.okta.com"}, client)
+	posture, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("collection under faults failed: %v", err)
+	}
+
+	if posture.counts.Users != sim.ExpectedUsers() {
+		t.Errorf("expected %d users, got %d", sim.ExpectedUsers(), posture.counts.Users)
+	}
+	if posture.counts.Apps != *simApps {
+		t.Errorf("expected %d apps, got %d", *simApps, posture.counts.Apps)
+	}
+
+	faults := injector.Counts()
+	stats := posture.CollectionStats
+	if stats == nil {
+		t.Fatal("expected collection stats")
+	}
+	if int64(stats.RateLimited) != faults.RateLimited {
+		t.Errorf("expected %d rate-limited responses in collection stats, got %d", faults.RateLimited, stats.RateLimited)
+	}
+	if faults.RateLimited > 0 && stats.Retries == 0 {
+		t.Error("expected retries after injected rate limits")
+	}
+
+	if posture.Posture.MFACoverage == nil {
+		t.Fatal("expected MFA coverage")
+	}
+	t.Logf("users=%d apps=%d faults=%+v requests=%d retries=%d backoff=%.1fs mfa_coverage=%d%% duration=%s",
+		*simUsers, *simApps, faults, stats.Requests, stats.Retries, stats.BackoffSeconds,
+		*posture.Posture.MFACoverage, time.Since(start))
+}
@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// CollectionStats describes the API traffic of one collection and how the
+// collector behaved under Okta's rate limits, per rate limit bucket, so
+// throttling can be explained to Okta support from the document alone.
+type CollectionStats struct {
+	Requests       int             `json:"requests"`        // HTTP requests sent, including retries
+	RateLimited    int             `json:"rate_limited"`    // Responses with status 429
+	Retries        int             `json:"retries"`         // Requests re-sent after a 429 or a transient page failure
+	Throttled      int             `json:"throttled"`       // Requests held back to keep rate limit headroom
+	BackoffSeconds float64         `json:"backoff_seconds"` // Time spent waiting out rate limits and before retries
+	Endpoints      []EndpointStats `json:"endpoints"`       // By rate limit bucket, sorted
+}
+
+// EndpointStats is the traffic to one rate limit bucket.
+type EndpointStats struct {
+	Endpoint       string  `json:"endpoint"` // Rate limit bucket, e.g. "/api/v1/users" or "/api/v1/users*"
+	Requests       int     `json:"requests"`
+	RateLimited    int     `json:"rate_limited"`
+	Retries        int     `json:"retries"`
+	BackoffSeconds float64 `json:"backoff_seconds"`
+}
+
+// requestStats returns the client's request counters, and false when the
+// client doesn't count requests, e.g. a test double.
+func (c *Collector) requestStats() (okta.RequestStats, bool) {
+	s, ok := c.client.(interface{ Stats() okta.RequestStats })
+	if !ok {
+		return okta.RequestStats{}, false
+	}
+	return s.Stats(), true
+}
+
+// collectionStats returns the traffic since start, the counters taken when
+// the collection began, or nil when the client doesn't count requests.
+func (c *Collector) collectionStats(start okta.RequestStats) *CollectionStats {
+	end, ok := c.requestStats()
+	if !ok {
+		return nil
+	}
+
+	stats := &CollectionStats{
+		Requests:    int(end.Requests - start.Requests),
+		RateLimited: int(end.RateLimited - start.RateLimited),
+		Throttled:   int(end.Throttled - start.Throttled),
+		Endpoints:   []EndpointStats{},
+	}
+	var backoff time.Duration
+	for bucket, e := range end.Endpoints {
+		s := start.Endpoints[bucket]
+		endpoint := EndpointStats{
+			Endpoint:       bucket,
+			Requests:       int(e.Requests - s.Requests),
+			RateLimited:    int(e.RateLimited - s.RateLimited),
+			Retries:        int(e.Retries - s.Retries),
+			BackoffSeconds: roundSeconds(e.Backoff - s.Backoff),
+		}
+		if endpoint.Requests == 0 && endpoint.Retries == 0 {
+			continue
+		}
+		stats.Retries += endpoint.Retries
+		backoff += e.Backoff - s.Backoff
+		stats.Endpoints = append(stats.Endpoints, endpoint)
+	}
+	stats.BackoffSeconds = roundSeconds(backoff)
+	slices.SortFunc(stats.Endpoints, func(a, b EndpointStats) int { return cmp.Compare(a.Endpoint, b.Endpoint) })
+	return stats
+}
+
+// roundSeconds returns a duration in seconds, rounded to milliseconds.
+func roundSeconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
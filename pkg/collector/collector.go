@@ -0,0 +1,1311 @@
+package collector
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"runtime/pprof"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Collector collects Okta organization security posture.
+type Collector struct {
+	client   okta.OktaClient
+	config   Config
+	defs     Definitions    // Config.Definitions with defaults applied
+	recorder *okta.Recorder // Set in fixture record mode
+	pam      pamClient      // Set when Config.PAMTeam is
+
+	generatedRunID bool // Config.RunID was generated, so each collection gets a new one
+
+	callbackMu sync.Mutex // Serializes OnStatus and OnProgress across sections
+
+	skipMu sync.Mutex
+	skips  []SkippedSection // Sections denied during the current collection
+
+	windowMu sync.Mutex
+	windows  []LogWindow // System Log windows read during the current collection
+}
+
+// status reports an indeterminate status update.
+func (c *Collector) status(message string) {
+	if c.config.OnStatus != nil {
+		c.callbackMu.Lock()
+		defer c.callbackMu.Unlock()
+		c.config.OnStatus(message)
+	}
+}
+
+// progress reports a determinate progress update.
+func (c *Collector) progress(current, total int64, message string) {
+	if c.config.OnProgress != nil {
+		c.callbackMu.Lock()
+		defer c.callbackMu.Unlock()
+		c.config.OnProgress(current, total, message)
+	}
+}
+
+// New creates a new Collector with the given configuration.
+// It supports two authentication methods:
+//   - OAuth 2.0 (recommended): Set ClientID and PrivateKey
+//   - API Token (legacy): Set APIToken
+func New(config Config) (*Collector, error) {
+	var client *okta.Client
+	var err error
+
+	if _, err := okta.ParseOrgDomain(config.OrgDomain); err != nil {
+		return nil, err
+	}
+
+	var cert *tls.Certificate
+	if config.ClientCertificate != "" && config.FixtureMode != FixtureModeReplay {
+		pair, err := tls.X509KeyPair([]byte(config.ClientCertificate), []byte(config.ClientCertificateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		cert = &pair
+	}
+
+	if config.FixtureMode == FixtureModeReplay {
+		// Replay recorded responses (no credentials needed)
+		client, err = okta.NewReplayClient(config.FixturePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fixture: %w", err)
+		}
+	} else if config.ClientID != "" && config.PrivateKey != "" {
+		// OAuth 2.0 auth (recommended)
+		client, err = okta.NewClientWithOAuthConfig(okta.OAuthConfig{
+			OrgDomain:         config.OrgDomain,
+			ClientID:          config.ClientID,
+			PrivateKey:        []byte(config.PrivateKey),
+			KeyID:             config.PrivateKeyID,
+			FallbackKeys:      config.fallbackKeys(),
+			ExtraScopes:       config.extraScopes(),
+			AuthServerID:      config.AuthorizationServerID,
+			TokenCachePath:    config.TokenCachePath,
+			ClientCertificate: cert,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OAuth client: %w", err)
+		}
+	} else if config.APIToken != "" {
+		// API token auth (legacy)
+		if cert != nil {
+			client = okta.NewClientWithCertificate(config.OrgDomain, config.APIToken, *cert)
+		} else {
+			client = okta.NewClient(config.OrgDomain, config.APIToken)
+		}
+	} else {
+		return nil, fmt.Errorf("authentication required: provide client_id + private_key (recommended) or api_token")
+	}
+
+	generatedRunID := config.RunID == ""
+	if generatedRunID {
+		config.RunID = newRunID()
+	}
+	client.SetUserAgent(userAgent(config.Version, config.RunID))
+	client.SetReadTimeout(time.Duration(config.ReadTimeoutSeconds) * time.Second)
+
+	var recorder *okta.Recorder
+	if config.FixtureMode == FixtureModeRecord {
+		recorder = okta.NewRecorder()
+		client.EnableRecording(recorder)
+	}
+	client.Use(config.Middleware...)
+	client.Use(okta.LimitConcurrency(MaxConcurrentRequests))
+
+	collector := &Collector{
+		client:   client,
+		config:   config,
+		defs:     config.Definitions.withDefaults(),
+		recorder: recorder,
+
+		generatedRunID: generatedRunID,
+	}
+	// Privileged Access is a separate API, outside fixtures and dry runs
+	if config.PAMTeam != "" && config.FixtureMode == "" && !config.DryRun {
+		collector.pam = okta.NewPAMClient(config.PAMTeam, config.PAMKeyID, config.PAMKeySecret)
+	}
+	return collector, nil
+}
+
+// extraScopes returns the OAuth scopes needed beyond okta.DefaultScopes by
+// the enabled features, plus any configured OAuthScopes.
+func (config Config) extraScopes() []string {
+	scopes := slices.Clone(config.OAuthScopes)
+	if len(config.GroupsInclude) > 0 || len(config.MFAGroups) > 0 {
+		scopes = append(scopes, ScopeGroupsRead)
+	}
+	if config.SystemLogLookbackDays > 0 {
+		scopes = append(scopes, ScopeLogsRead)
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// fallbackKeys returns the keys to try when Okta rejects PrivateKey.
+func (config Config) fallbackKeys() []okta.OAuthKey {
+	if config.NextPrivateKey == "" {
+		return nil
+	}
+	return []okta.OAuthKey{{PrivateKey: []byte(config.NextPrivateKey), KeyID: config.NextPrivateKeyID}}
+}
+
+// NewWithClient creates a Collector with a custom client, for tests or for
+// embedders that manage their own okta.OktaClient.
+func NewWithClient(config Config, client okta.OktaClient) *Collector {
+	return &Collector{
+		client: client,
+		config: config,
+		defs:   config.Definitions.withDefaults(),
+	}
+}
+
+// Collect fetches and aggregates security posture metrics for the organization.
+func (c *Collector) Collect(ctx context.Context) (*OrgPosture, error) {
+	if c.config.OrgDomain == "" {
+		return nil, fmt.Errorf("org_domain is required")
+	}
+	domain, err := okta.ParseOrgDomain(c.config.OrgDomain)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.UserFilter != "" && len(c.config.GroupsInclude) > 0 {
+		return nil, fmt.Errorf("user_filter and groups_include cannot be combined")
+	}
+	if err := validateBenchmark(c.config.Benchmark); err != nil {
+		return nil, err
+	}
+	if err := ValidateExemptions(c.config.Exemptions); err != nil {
+		return nil, err
+	}
+
+	c.nextRunID()
+	c.status(fmt.Sprintf("Connecting to Okta org %s...", c.config.OrgDomain))
+	c.takeSkips() // Discard denials and windows left by a failed collection
+	c.takeWindows()
+	startStats, _ := c.requestStats()
+
+	posture := NewOrgPosture(c.config.OrgDomain)
+	posture.Cell = domain.Cell
+	posture.RunID = c.config.RunID
+	posture.CollectionStartedAt = posture.CollectedAt
+	started, _ := time.Parse(time.RFC3339, posture.CollectedAt)
+	posture.IdempotencyKey = c.idempotencyKey(started)
+	if c.config.Version != "" {
+		posture.Collector = &CollectorBuild{Version: c.config.Version, Commit: c.config.Commit, BuildDate: c.config.BuildDate}
+	}
+	posture.Definitions = c.defs
+	posture.Config = c.config.effectiveConfig()
+	if len(c.config.GroupsInclude) > 0 || c.config.UserFilter != "" {
+		posture.Scope = &Scope{Groups: c.config.GroupsInclude, UserFilter: c.config.echoedUserFilter()}
+	}
+
+	// Fail fast on a rejected API token
+	if c.config.usesAPIToken() {
+		c.status("Checking API token...")
+		token, err := c.checkAPIToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		posture.APIToken = token
+	}
+
+	// Best-effort: context for interpreting the metrics below
+	c.status("Checking org engine and features...")
+	posture.Features = c.collectOrgFeatures(ctx)
+
+	// Users, apps, and policies are independent, so apps and policies don't
+	// wait behind the user scan, which dominates on large orgs. The first
+	// failure cancels the others.
+	var userMetrics *userMetricsCollector
+	var appMetrics *appMetricsCollector
+	var policyMetrics *policyMetricsCollector
+	g, gctx := newGroup(ctx)
+	g.Go(func() error {
+		c.status("Collecting user metrics...")
+		metrics, err := c.collectUserMetrics(gctx)
+		if err != nil {
+			return fmt.Errorf("failed to collect user metrics: %w", err)
+		}
+		userMetrics = metrics
+		return nil
+	})
+	g.Go(func() error {
+		c.status("Collecting application metrics...")
+		metrics, err := c.collectAppMetrics(gctx)
+		if err != nil {
+			return fmt.Errorf("failed to collect app metrics: %w", err)
+		}
+		appMetrics = metrics
+		return nil
+	})
+	g.Go(func() error {
+		c.status("Collecting policy metrics...")
+		metrics, err := c.collectPolicyMetrics(gctx)
+		if err != nil {
+			return fmt.Errorf("failed to collect policy metrics: %w", err)
+		}
+		policyMetrics = metrics
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Best-effort: omitted if password policies can't be read
+	c.status("Checking password policies...")
+	posture.PasswordPolicy = c.collectPasswordPolicy(ctx)
+
+	// Best-effort: omitted on Classic Engine or if the rules can't be read
+	c.status("Checking Admin Console access policy...")
+	posture.AdminConsole = c.evaluateAppPolicy(ctx, appMetrics.adminConsole)
+
+	c.status("Checking privileged access...")
+	posture.PrivilegedAccess = c.collectPrivilegedAccess(ctx, appMetrics.privilegedAccessApps > 0)
+
+	if len(c.config.CrownJewelApps) > 0 {
+		c.status("Checking crown jewel apps...")
+		posture.CrownJewelApps = c.collectCrownJewels(ctx, appMetrics.crownJewels)
+	}
+
+	// Best-effort: omitted if any configured group can't be read, or if
+	// factors couldn't be read
+	if len(c.config.MFAGroups) > 0 && !userMetrics.factorsDenied {
+		c.status("Rolling up MFA coverage by group...")
+		mfaByGroup, err := c.collectGroupMFA(ctx, userMetrics.mfaByUser)
+		if err != nil {
+			c.skip("mfa_by_group", ScopeGroupsRead, err)
+			c.status(fmt.Sprintf("Warning: MFA by group unavailable: %v", err))
+		} else {
+			posture.MFAByGroup = mfaByGroup
+		}
+	}
+
+	// Best-effort: internal endpoint, omitted if unreadable
+	c.status("Checking security notification settings...")
+	posture.Notifications = c.collectSecurityNotifications(ctx)
+
+	// Best-effort: internal endpoint, omitted if unreadable
+	c.status("Checking end-user settings...")
+	posture.EndUserSettings = c.collectEndUserSettings(ctx)
+
+	// Best-effort: omitted without okta.orgs.read
+	c.status("Checking Okta Support access...")
+	posture.SupportAccess = c.collectSupportAccess(ctx)
+
+	// Best-effort: omitted without okta.captchas.read
+	c.status("Checking CAPTCHA settings...")
+	posture.Captcha = c.collectCaptcha(ctx)
+
+	// Best-effort: omitted without okta.threatInsights.read
+	c.status("Checking ThreatInsight...")
+	posture.ThreatInsight = c.collectThreatInsight(ctx)
+
+	// Best-effort: omitted without okta.networkZones.read
+	c.status("Checking blocklist zones...")
+	posture.BlocklistZones = c.collectBlocklistZones(ctx, policyMetrics.denyZones)
+
+	// Best-effort: authenticators are unreadable on Classic Engine or
+	// without okta.authenticators.read
+	c.status("Checking authenticators...")
+	authenticators, err := c.client.FetchAuthenticators(ctx)
+	if err != nil {
+		c.skip("push_protection", ScopeAuthenticatorsRead, err)
+		authenticators = nil
+	} else {
+		posture.PushProtection = collectPushProtection(authenticators)
+	}
+	weak := c.collectWeakFactors(ctx, authenticators, policyMetrics.classicFactors)
+
+	// Best-effort: omitted without okta.logStreams.read
+	c.status("Checking log streaming...")
+	posture.LogStreaming = c.collectLogStreaming(ctx)
+
+	// Best-effort: omitted if Okta rejects the automation policy type
+	c.status("Checking lifecycle automations...")
+	posture.Automations = c.collectAutomations(ctx)
+
+	// Best-effort: omitted without okta.roles.read
+	c.status("Checking custom admin roles...")
+	posture.CustomAdminRoles = c.collectCustomAdminRoles(ctx)
+
+	// Best-effort: omitted without okta.groups.read
+	c.status("Checking group rules...")
+	posture.GroupRules = c.collectGroupRules(ctx)
+
+	// Best-effort: omitted without okta.schemas.read
+	c.status("Auditing user profile attributes...")
+	posture.UserSchema = c.collectUserSchema(ctx)
+
+	// Best-effort: omitted without okta.profileMappings.read
+	c.status("Checking profile mappings...")
+	posture.ProfileMappings = c.collectProfileMappings(ctx, posture.UserSchema)
+
+	// Best-effort: omitted without okta.brands.read
+	c.status("Checking sign-in page customization...")
+	posture.Branding = c.collectBranding(ctx)
+
+	// Best-effort: session statistics need the System Log
+	if c.config.SystemLogLookbackDays > 0 {
+		c.status("Reading session activity from System Log...")
+		sessions, err := c.collectSessionStats(ctx, policyMetrics.sessionLifetimeMax)
+		if err != nil {
+			c.skip("sessions", ScopeLogsRead, err)
+			c.status(fmt.Sprintf("Warning: session statistics unavailable: %v", err))
+		} else {
+			posture.Sessions = sessions
+		}
+	}
+
+	// Best-effort: threat signals need the System Log
+	if c.config.SystemLogLookbackDays > 0 {
+		c.status("Reading threat signals from System Log...")
+		signals, err := c.collectThreatSignals(ctx)
+		if err != nil {
+			c.skip("threat_signals", ScopeLogsRead, err)
+			c.status(fmt.Sprintf("Warning: threat signals unavailable: %v", err))
+		} else {
+			posture.ThreatSignals = signals
+		}
+	}
+
+	// Best-effort: new countries need a baseline before the recent window
+	if c.config.SystemLogLookbackDays > NewCountryWindowDays {
+		c.status("Reading sign-in countries from System Log...")
+		countries, err := c.collectSignInCountries(ctx)
+		if err != nil {
+			c.skip("sign_in_countries", ScopeLogsRead, err)
+			c.status(fmt.Sprintf("Warning: sign-in countries unavailable: %v", err))
+		} else {
+			posture.SignInCountries = countries
+		}
+	}
+
+	// Best-effort: rate limit events need the System Log
+	if c.config.SystemLogLookbackDays > 0 {
+		c.status("Reading rate limit events from System Log...")
+		rateLimits, err := c.collectRateLimits(ctx)
+		if err != nil {
+			c.skip("rate_limits", ScopeLogsRead, err)
+			c.status(fmt.Sprintf("Warning: rate limit events unavailable: %v", err))
+		} else {
+			posture.RateLimits = rateLimits
+		}
+	}
+
+	// Best-effort: offboarding metrics are omitted if the search fails.
+	// Group membership can't select deprovisioned users, so group-scoped
+	// runs skip them.
+	if len(c.config.GroupsInclude) == 0 {
+		c.status("Collecting offboarding metrics...")
+		offboarding, err := c.collectOffboarding(ctx)
+		if err == nil {
+			posture.Offboarding = offboarding
+		}
+	}
+
+	// Best-effort: omitted without agent pool access
+	c.status("Checking directory agents...")
+	posture.Agents = c.collectAgentHealth(ctx)
+
+	// Best-effort: omitted without Identity Governance
+	c.status("Checking Identity Governance...")
+	posture.Governance = c.collectGovernance(ctx)
+
+	posture.Posture = Posture{
+		MFACoverage:          userMetrics.mfaEnrolled,
+		MFAPhishingResistant: userMetrics.mfaPhishingResistant,
+		PasswordlessEnabled:  policyMetrics.passwordOptionalPolicies > 0 || policyMetrics.anyFactorPrimaryRules > 0,
+		PasswordlessEligible: userMetrics.passwordlessEligible,
+		SSOCoverage:          appMetrics.ssoCoverage,
+		SMSFactorEnabled:     weak.sms,
+		VoiceFactorEnabled:   weak.voice,
+		EmailFactorAsMFA:     weak.email,
+		SecurityQuestion:     weak.question,
+	}
+
+	posture.Users = UserMetrics{
+		PasswordExpired: userMetrics.passwordExpired,
+		LockedOut:       userMetrics.lockedOut,
+		Inactive:        userMetrics.inactive,
+	}
+
+	posture.Apps = AppMetrics{
+		ProvisioningEnabled:   appMetrics.provisioningEnabled,
+		DeprovisioningEnabled: appMetrics.deprovisioningEnabled,
+		ProvisioningFailing:   appMetrics.provisioningFailing,
+		AssignedToEveryone:    appMetrics.assignedToEveryone,
+		SignOnClasses:         appMetrics.signOnClasses,
+		SignOnModes:           appMetrics.signOnModes,
+		HiddenFromUsers:       appMetrics.hiddenFromUsers,
+		AutoSubmitToolbar:     appMetrics.autoSubmitToolbar,
+	}
+
+	posture.counts = Counts{
+		Users:                 userMetrics.totalUsers,
+		PasswordExpired:       userMetrics.passwordExpiredCount,
+		LockedOut:             userMetrics.lockedOutCount,
+		Inactive:              userMetrics.inactiveCount,
+		Apps:                  appMetrics.totalApps,
+		SSOApps:               appMetrics.ssoApps,
+		ProvisioningApps:      appMetrics.provisioningCount,
+		DeprovisioningApps:    appMetrics.deprovisioningCount,
+		EveryoneApps:          len(appMetrics.everyoneApps),
+		ActiveApps:            appMetrics.activeApps,
+		HiddenApps:            appMetrics.hiddenCount,
+		AutoSubmitToolbarApps: appMetrics.autoSubmitCount,
+	}
+	if !userMetrics.factorsDenied {
+		posture.counts.MFAEnrolled = &userMetrics.mfaEnrolledCount
+		posture.counts.MFAPhishingResistant = &userMetrics.phishingResistantCount
+		posture.counts.PasswordlessEligible = &userMetrics.passwordlessCount
+	}
+	if !policyMetrics.denied {
+		posture.counts.MFARequiredPolicyCount = &policyMetrics.mfaRequiredCount
+	}
+
+	posture.Evidence = userMetrics.evidence
+	if posture.Evidence != nil {
+		posture.Evidence.EveryoneApps = appMetrics.everyoneApps
+		posture.Evidence.AppPolicies = mapAppPolicies(appMetrics.policyApps, policyMetrics.accessPolicies)
+	}
+
+	// Best-effort: omitted without okta.roles.read
+	c.status("Checking admin role assignments...")
+	posture.AdminAssignments = c.collectAdminAssignments(ctx, posture.Evidence)
+	if posture.Evidence != nil {
+		posture.Evidence.sort()
+	}
+
+	if c.config.Entities {
+		posture.entities = &Entities{
+			Users:    userEntities(userMetrics.entities),
+			Apps:     appMetrics.entities,
+			Policies: policyMetrics.entities,
+		}
+		sortEntities(posture.entities)
+	}
+
+	posture.MFAEnrollment = policyMetrics.enrollment
+
+	// Omitted rather than reported as zeros when policies can't be read
+	if !policyMetrics.denied {
+		posture.Policy = &PolicyConfig{
+			PolicyCount:               policyMetrics.policyCount,
+			MFARequiredAll:            policyMetrics.policyCount > 0 && policyMetrics.mfaRequiredCount >= policyMetrics.policyCount,
+			MFARequiredAny:            policyMetrics.mfaRequiredCount > 0 || policyMetrics.mfaBestCase > 0,
+			SessionLifetimeMinMinutes: policyMetrics.sessionLifetimeMin,
+			SessionLifetimeMaxMinutes: policyMetrics.sessionLifetimeMax,
+			IdleTimeoutMinMinutes:     policyMetrics.idleTimeoutMin,
+			IdleTimeoutMaxMinutes:     policyMetrics.idleTimeoutMax,
+			RiskBasedRules:            policyMetrics.riskBasedRules,
+			RiskBasedEnforced:         policyMetrics.riskBasedRules > 0,
+			NetworkRestricted:         percent(policyMetrics.networkRules, policyMetrics.activeRules),
+			ZoneDenyRules:             policyMetrics.zoneDenyRules,
+			DenyRules:                 policyMetrics.denyRules,
+			CatchAllAllowWithoutMFA:   policyMetrics.catchAllWithoutMFA,
+			MFAWorstCasePolicies:      policyMetrics.mfaWorstCase,
+			MFABestCasePolicies:       policyMetrics.mfaBestCase,
+		}
+	}
+
+	// Checked last, against every section the run collected
+	if c.config.Benchmark != "" {
+		posture.Benchmark = evaluateBenchmark(c.config.Benchmark, posture, c.config.Exemptions, started)
+	}
+
+	posture.Skipped = c.takeSkips()
+	posture.SystemLogWindows = c.takeWindows()
+	posture.CollectionStats = c.collectionStats(startStats)
+	posture.CollectionFinishedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if c.recorder != nil {
+		c.status(fmt.Sprintf("Saving fixture to %s...", c.config.FixturePath))
+		if err := c.recorder.Save(c.config.FixturePath); err != nil {
+			return nil, err
+		}
+	}
+
+	c.status("Collection complete")
+
+	return posture, nil
+}
+
+// userMetricsCollector holds intermediate user collection state.
+type userMetricsCollector struct {
+	totalUsers             int
+	mfaEnrolledCount       int
+	phishingResistantCount int
+	passwordlessCount      int
+	passwordExpiredCount   int
+	lockedOutCount         int
+	inactiveCount          int
+	mfaEnrolled            *int // Nil when factors can't be read
+	mfaPhishingResistant   *int
+	passwordlessEligible   *int
+	passwordExpired        int
+	lockedOut              int
+	inactive               int
+	userIDs                []string // IDs of counted users for the factor pass
+	factorsDenied          bool     // Okta denied the factor pass
+
+	// Most recent System Log sign-in by user ID (enrichment only)
+	lastSeen map[string]time.Time
+
+	// Detail mode only
+	evidence *Evidence
+	userRefs map[string]UserRef // Evidence entries by user ID for the factor pass
+
+	entities map[string]*UserEntity // Entity mode only
+
+	mfaByUser map[string]userMFA // Factor results by user ID; only with mfa_groups
+}
+
+func (c *Collector) collectUserMetrics(ctx context.Context) (*userMetricsCollector, error) {
+	metrics := &userMetricsCollector{}
+	if c.config.Detail {
+		metrics.evidence = newEvidence()
+		metrics.userRefs = make(map[string]UserRef)
+	}
+	if c.config.Entities {
+		metrics.entities = make(map[string]*UserEntity)
+	}
+	if len(c.config.MFAGroups) > 0 {
+		metrics.mfaByUser = make(map[string]userMFA)
+	}
+	inactiveThreshold := time.Now().AddDate(0, 0, -c.defs.InactiveDays)
+
+	// Best-effort: without System Log access, activity falls back to lastLogin
+	if c.config.SystemLogLookbackDays > 0 {
+		c.status("Reading sign-in activity from System Log...")
+		lastSeen, err := c.collectSignInActivity(ctx)
+		if err != nil {
+			c.status(fmt.Sprintf("Warning: System Log enrichment unavailable: %v", err))
+		} else {
+			c.status(fmt.Sprintf("Found sign-in activity for %d users", len(lastSeen)))
+			metrics.lastSeen = lastSeen
+		}
+	}
+
+	// First pass: stream users, recording status metrics and keeping only IDs.
+	// Each pass runs under a pprof label so CPU profiles of large orgs
+	// attribute time to it (go tool pprof -tagfocus=phase=users).
+	userCount := 0
+	var err error
+	pprof.Do(ctx, pprof.Labels("phase", "users"), func(ctx context.Context) {
+		err = c.fetchUsers(ctx, func(user okta.User) error {
+			c.processUser(user, inactiveThreshold, metrics)
+			userCount++
+			if userCount%StatusReportInterval == 0 {
+				c.status(fmt.Sprintf("Found %d users...", userCount))
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	c.status(fmt.Sprintf("Found %d users", userCount))
+
+	// Second pass: check MFA factors for each user. A denial applies to
+	// every user, so the pass stops at the first one.
+	total := int64(len(metrics.userIDs))
+	pprof.Do(ctx, pprof.Labels("phase", "user_factors"), func(ctx context.Context) {
+		for i, userID := range metrics.userIDs {
+			c.progress(int64(i+1), total, fmt.Sprintf("Checking MFA for user %d of %d", i+1, len(metrics.userIDs)))
+			if !c.processUserFactors(ctx, userID, metrics) {
+				metrics.factorsDenied = true
+				c.status("Warning: user factors unavailable; MFA coverage omitted")
+				break
+			}
+		}
+	})
+	metrics.userIDs = nil
+	metrics.userRefs = nil
+	metrics.lastSeen = nil
+
+	if !metrics.factorsDenied {
+		mfaEnrolled := percent(metrics.mfaEnrolledCount, metrics.totalUsers)
+		mfaPhishingResistant := percent(metrics.phishingResistantCount, metrics.totalUsers)
+		passwordlessEligible := percent(metrics.passwordlessCount, metrics.totalUsers)
+		metrics.mfaEnrolled = &mfaEnrolled
+		metrics.mfaPhishingResistant = &mfaPhishingResistant
+		metrics.passwordlessEligible = &passwordlessEligible
+	}
+	metrics.passwordExpired = percent(metrics.passwordExpiredCount, metrics.totalUsers)
+	metrics.lockedOut = percent(metrics.lockedOutCount, metrics.totalUsers)
+	metrics.inactive = percent(metrics.inactiveCount, metrics.totalUsers)
+
+	return metrics, nil
+}
+
+// fetchUsers streams the users in scope: every user in the org, the users
+// matching UserFilter, or the members of the GroupsInclude groups with each
+// user reported once.
+func (c *Collector) fetchUsers(ctx context.Context, callback func(okta.User) error) error {
+	if c.config.UserFilter != "" {
+		return c.client.SearchUsers(ctx, c.config.UserFilter, callback)
+	}
+	if len(c.config.GroupsInclude) == 0 {
+		return c.client.FetchUsers(ctx, callback)
+	}
+
+	seen := make(map[string]struct{})
+	for _, groupID := range c.config.GroupsInclude {
+		err := c.client.FetchGroupMembers(ctx, groupID, func(user okta.User) error {
+			if _, ok := seen[user.ID]; ok {
+				return nil
+			}
+			seen[user.ID] = struct{}{}
+			return callback(user)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processUser processes a single user's status and updates metrics.
+// Only the user ID is retained for the factor pass, plus the evidence
+// entry in detail mode.
+func (c *Collector) processUser(user okta.User, inactiveThreshold time.Time, metrics *userMetricsCollector) {
+	if c.defs.isExcluded(user.Status) {
+		return
+	}
+
+	metrics.totalUsers++
+
+	var ref UserRef
+	if metrics.evidence != nil || metrics.entities != nil {
+		ref = c.userRef(user)
+	}
+	if metrics.evidence != nil {
+		metrics.userRefs[user.ID] = ref
+	}
+
+	active := lastActivity(user, metrics.lastSeen)
+	inactive := active.IsZero() || active.Before(inactiveThreshold)
+	if inactive {
+		metrics.inactiveCount++
+		if metrics.evidence != nil {
+			metrics.evidence.InactiveUsers = append(metrics.evidence.InactiveUsers, ref)
+		}
+	}
+	if metrics.entities != nil {
+		metrics.entities[user.ID] = &UserEntity{ID: user.ID, Login: ref.Login, Email: ref.Email, Status: user.Status, Inactive: inactive}
+	}
+
+	switch user.Status {
+	case StatusPasswordExpired:
+		metrics.passwordExpiredCount++
+		if metrics.evidence != nil {
+			metrics.evidence.PasswordExpiredUsers = append(metrics.evidence.PasswordExpiredUsers, ref)
+		}
+	case StatusLockedOut:
+		metrics.lockedOutCount++
+		if metrics.evidence != nil {
+			metrics.evidence.LockedOutUsers = append(metrics.evidence.LockedOutUsers, ref)
+		}
+	}
+
+	metrics.userIDs = append(metrics.userIDs, user.ID)
+}
+
+// processUserFactors checks MFA factors for a user. It reports false if
+// Okta denied the request, recording the skip.
+func (c *Collector) processUserFactors(ctx context.Context, userID string, metrics *userMetricsCollector) bool {
+	factors, err := c.client.FetchUserFactors(ctx, userID)
+	if err != nil {
+		return !c.skip("mfa", ScopeUsersRead, err)
+	}
+
+	mfa := c.evaluateFactors(factors)
+	if metrics.mfaByUser != nil {
+		metrics.mfaByUser[userID] = mfa
+	}
+
+	if entity := metrics.entities[userID]; entity != nil {
+		entity.MFAEnrolled = &mfa.enrolled
+		entity.MFAPhishingResistant = &mfa.phishingResistant
+		entity.PasswordlessEligible = &mfa.passwordless
+	}
+
+	if mfa.enrolled {
+		metrics.mfaEnrolledCount++
+	} else if metrics.evidence != nil {
+		metrics.evidence.UsersWithoutMFA = append(metrics.evidence.UsersWithoutMFA, metrics.userRefs[userID])
+	}
+	if mfa.phishingResistant {
+		metrics.phishingResistantCount++
+	}
+	if mfa.passwordless {
+		metrics.passwordlessCount++
+	}
+	return true
+}
+
+// userMFA is what a user's active factors provide.
+type userMFA struct {
+	enrolled          bool // Any active factor
+	phishingResistant bool
+	passwordless      bool
+}
+
+// evaluateFactors classifies a user's active factors.
+func (c *Collector) evaluateFactors(factors []okta.Factor) userMFA {
+	var mfa userMFA
+	for _, factor := range factors {
+		if factor.Status != StatusActive {
+			continue
+		}
+
+		mfa.enrolled = true
+
+		factorType := strings.ToLower(factor.FactorType)
+		if c.defs.isPhishingResistant(factorType) {
+			mfa.phishingResistant = true
+		}
+		if c.defs.isPasswordless(factorType) {
+			mfa.passwordless = true
+		}
+	}
+	return mfa
+}
+
+// appMetricsCollector holds intermediate app collection state.
+type appMetricsCollector struct {
+	totalApps             int
+	ssoApps               int
+	provisioningCount     int
+	deprovisioningCount   int
+	ssoCoverage           int
+	signOnModes           map[string]int // Apps per sign-on mode
+	signOnClasses         SignOnClasses
+	provisioningEnabled   int
+	deprovisioningEnabled int
+	provisioningFailing   *int // Set when System Log enrichment is enabled
+	adminConsole          *okta.Application
+	crownJewels           []crownJewelMatch // Apps matching config.CrownJewelApps
+	assignedToEveryone    int
+	everyoneApps          []AppRef // Apps assigned to the Everyone group
+	privilegedAccessApps  int      // Active Privileged Access or Advanced Server Access apps
+
+	// Visibility of active apps, excluding Okta's own
+	activeApps        int
+	hiddenCount       int
+	autoSubmitCount   int
+	hiddenFromUsers   int
+	autoSubmitToolbar int
+
+	assignableApps []AppRef    // Active apps whose group assignments are checked
+	policyApps     []AppPolicy // Apps with an authentication policy; detail mode only
+	entities       []AppEntity // Entity mode only
+
+	provisioningApps map[string]appProvisioning // Apps with either provisioning feature, by ID
+}
+
+func (c *Collector) collectAppMetrics(ctx context.Context) (*appMetricsCollector, error) {
+	metrics := &appMetricsCollector{
+		signOnModes:      make(map[string]int),
+		provisioningApps: make(map[string]appProvisioning),
+	}
+
+	appCount := 0
+	err := c.client.FetchApplications(ctx, func(app okta.Application) error {
+		c.processApp(app, metrics)
+		appCount++
+		if appCount%StatusReportInterval == 0 {
+			c.status(fmt.Sprintf("Found %d applications...", appCount))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	c.status(fmt.Sprintf("Found %d applications", appCount))
+
+	c.status("Checking app assignments to Everyone...")
+	c.collectEveryoneAssignments(ctx, metrics)
+
+	// Provisioning that fails doesn't count toward coverage
+	if c.config.SystemLogLookbackDays > 0 && len(metrics.provisioningApps) > 0 {
+		c.status("Checking provisioning failures...")
+		failing, err := c.collectProvisioningFailures(ctx)
+		if err != nil {
+			c.status(fmt.Sprintf("Warning: provisioning health unavailable: %v", err))
+		} else {
+			metrics.applyProvisioningFailures(failing)
+		}
+	}
+
+	metrics.ssoCoverage = percent(metrics.ssoApps, metrics.totalApps)
+	metrics.signOnClasses = c.defs.signOnClasses(metrics.signOnModes, metrics.totalApps)
+	metrics.provisioningEnabled = percent(metrics.provisioningCount, metrics.totalApps)
+	metrics.deprovisioningEnabled = percent(metrics.deprovisioningCount, metrics.totalApps)
+	metrics.assignedToEveryone = percent(len(metrics.everyoneApps), metrics.totalApps)
+	metrics.hiddenFromUsers = percent(metrics.hiddenCount, metrics.activeApps)
+	metrics.autoSubmitToolbar = percent(metrics.autoSubmitCount, metrics.activeApps)
+
+	return metrics, nil
+}
+
+// isOktaApp reports whether an app is one of Okta's own: the Admin
+// Console, Dashboard, or Browser Plugin.
+func isOktaApp(name string) bool {
+	return name == AppNameAdminConsole || name == AppNameDashboard || name == AppNameBrowserPlugin
+}
+
+// processApp processes a single application and updates metrics.
+func (c *Collector) processApp(app okta.Application, metrics *appMetricsCollector) {
+	metrics.totalApps++
+
+	if app.Name == AppNameAdminConsole {
+		metrics.adminConsole = &app
+	}
+	if policyID := accessPolicyID(app); policyID != "" && c.config.Detail {
+		metrics.policyApps = append(metrics.policyApps, AppPolicy{ID: app.ID, Label: app.Label, PolicyID: policyID})
+	}
+	if app.Status == StatusActive && isPrivilegedAccessApp(app) {
+		metrics.privilegedAccessApps++
+	}
+	if app.Status == StatusActive && app.Name != AppNameDashboard && app.Name != AppNameBrowserPlugin {
+		metrics.assignableApps = append(metrics.assignableApps, AppRef{ID: app.ID, Label: app.Label})
+	}
+	for _, match := range c.config.CrownJewelApps {
+		if app.ID == match || strings.EqualFold(app.Label, match) {
+			metrics.crownJewels = append(metrics.crownJewels, crownJewelMatch{match: match, app: app})
+		}
+	}
+
+	if app.Status == StatusActive && !isOktaApp(app.Name) {
+		metrics.activeApps++
+		if app.Visibility.Hide.Web && app.Visibility.Hide.IOS {
+			metrics.hiddenCount++
+		}
+		if app.Visibility.AutoSubmitToolbar {
+			metrics.autoSubmitCount++
+		}
+	}
+
+	if c.defs.isSSO(app.SignOnMode) {
+		metrics.ssoApps++
+	}
+	if app.SignOnMode != "" {
+		metrics.signOnModes[app.SignOnMode]++
+	}
+
+	hasProvisioning, hasDeprovisioning := c.defs.provisioningFeatures(app.Features)
+	if hasProvisioning {
+		metrics.provisioningCount++
+	}
+	if hasDeprovisioning {
+		metrics.deprovisioningCount++
+	}
+	if hasProvisioning || hasDeprovisioning {
+		metrics.provisioningApps[app.ID] = appProvisioning{hasProvisioning, hasDeprovisioning}
+	}
+
+	if c.config.Entities {
+		metrics.entities = append(metrics.entities, AppEntity{
+			ID:             app.ID,
+			Label:          app.Label,
+			Name:           app.Name,
+			Status:         app.Status,
+			SignOnMode:     app.SignOnMode,
+			SSO:            c.defs.isSSO(app.SignOnMode),
+			Provisioning:   hasProvisioning,
+			Deprovisioning: hasDeprovisioning,
+			AccessPolicyID: accessPolicyID(app),
+		})
+	}
+}
+
+// policyMetricsCollector holds intermediate policy collection state.
+type policyMetricsCollector struct {
+	denied             bool // Okta denied sign-on or authentication policies
+	policyCount        int
+	mfaRequiredCount   int
+	sessionLifetimeMin *int
+	sessionLifetimeMax *int
+	idleTimeoutMin     *int
+	idleTimeoutMax     *int
+	activeRules        int // Active sign-on and authentication policy rules
+	riskBasedRules     int
+	networkRules       int
+	zoneDenyRules      int
+	denyZones          map[string]bool // Zone IDs that active DENY rules apply to
+	denyRules          int
+	catchAllWithoutMFA bool                           // Some sign-on policy's catch-all rule allows without MFA
+	mfaWorstCase       int                            // Sign-on policies where every ALLOW rule requires MFA
+	mfaBestCase        int                            // Sign-on policies where some ALLOW rule requires MFA
+	enrollment         *MFAEnrollment                 // Nil if enrollment policies can't be read
+	accessPolicies     map[string]accessPolicySummary // Authentication policies by ID; detail mode only
+
+	passwordOptionalPolicies int // Enrollment policies where the password is optional or not allowed
+	anyFactorPrimaryRules    int // Sign-on rules accepting any factor instead of a password
+
+	classicFactors map[string]bool // Classic factor type -> enrollable under some policy; nil on Identity Engine
+
+	entities []PolicyEntity // Sign-on and authentication policies; entity mode only
+}
+
+func (c *Collector) collectPolicyMetrics(ctx context.Context) (*policyMetricsCollector, error) {
+	metrics := &policyMetricsCollector{denyZones: make(map[string]bool)}
+
+	c.status("Checking sign-on policies...")
+	c.collectSignOnPolicies(ctx, metrics)
+
+	c.status("Checking MFA enrollment policies...")
+	c.collectMFAEnrollPolicies(ctx, metrics)
+
+	c.status("Checking authentication policies...")
+	c.collectAccessPolicies(ctx, metrics)
+
+	return metrics, nil
+}
+
+// collectSignOnPolicies collects sign-on policy metrics.
+func (c *Collector) collectSignOnPolicies(ctx context.Context, metrics *policyMetricsCollector) {
+	policies, err := c.client.FetchPolicies(ctx, PolicyTypeSignOn)
+	if err != nil {
+		metrics.denied = c.skip("policy", ScopePoliciesRead, err) || metrics.denied
+		return
+	}
+
+	for _, policy := range policies {
+		entity := c.policyEntity(policy, metrics)
+		if policy.Status != StatusActive {
+			continue
+		}
+
+		rules, err := c.client.FetchPolicyRules(ctx, policy.ID)
+		if err != nil {
+			metrics.denied = c.skip("policy", ScopePoliciesRead, err) || metrics.denied
+			continue
+		}
+
+		sortByPriority(rules)
+		active, mfaRequired := c.processSignOnRules(rules, metrics)
+		if active {
+			metrics.policyCount++
+		}
+		if entity != nil {
+			entity.MFARequired = &mfaRequired
+		}
+		processRuleConditions(rules, metrics)
+		if catchAllAllowsWithoutMFA(rules) {
+			metrics.catchAllWithoutMFA = true
+		}
+	}
+}
+
+// processSignOnRules processes every active rule of a sign-on policy and
+// reports whether the policy has active rules and whether it requires MFA.
+// Rules after the first can relax its requirements for the sign-ins they
+// match, so the policy counts as requiring MFA only if every ALLOW rule
+// does (worst case).
+func (c *Collector) processSignOnRules(rules []okta.PolicyRule, metrics *policyMetricsCollector) (active, mfaRequired bool) {
+	activeRules, allowRules, mfaRules := 0, 0, 0
+	for _, rule := range rules {
+		if rule.Status != StatusActive || rule.Actions.Signon == nil {
+			continue
+		}
+		activeRules++
+
+		signon := rule.Actions.Signon
+		if signon.Access == AccessDeny {
+			// Okta returns default session settings on DENY rules, but
+			// they never create a session
+			continue
+		}
+		allowRules++
+
+		updateMinMax(&metrics.sessionLifetimeMin, &metrics.sessionLifetimeMax, signon.Session.MaxSessionLifetimeMinutes)
+		updateMinMax(&metrics.idleTimeoutMin, &metrics.idleTimeoutMax, signon.Session.MaxSessionIdleMinutes)
+		if signon.RequireFactor {
+			mfaRules++
+		}
+		if signon.PrimaryFactor == PrimaryFactorAny {
+			metrics.anyFactorPrimaryRules++
+		}
+	}
+	if activeRules == 0 {
+		return false, false
+	}
+
+	// A policy without ALLOW rules admits nobody, so MFA is moot
+	if mfaRules == allowRules {
+		metrics.mfaRequiredCount++
+		metrics.mfaWorstCase++
+	}
+	if mfaRules > 0 {
+		metrics.mfaBestCase++
+	}
+	return true, mfaRules == allowRules
+}
+
+// policyEntity records a policy in entity mode and returns the record so
+// its rule evaluation can be filled in. It returns nil otherwise.
+func (c *Collector) policyEntity(policy okta.Policy, metrics *policyMetricsCollector) *PolicyEntity {
+	if !c.config.Entities {
+		return nil
+	}
+	metrics.entities = append(metrics.entities, PolicyEntity{
+		ID:       policy.ID,
+		Name:     policy.Name,
+		Type:     policy.Type,
+		Status:   policy.Status,
+		Priority: policy.Priority,
+	})
+	return &metrics.entities[len(metrics.entities)-1]
+}
+
+// collectAccessPolicies collects authentication policy (Identity Engine)
+// metrics. Classic Engine orgs have none.
+func (c *Collector) collectAccessPolicies(ctx context.Context, metrics *policyMetricsCollector) {
+	policies, err := c.client.FetchPolicies(ctx, PolicyTypeAccess)
+	if err != nil {
+		metrics.denied = c.skip("policy", ScopePoliciesRead, err) || metrics.denied
+		return
+	}
+	if c.config.Detail {
+		metrics.accessPolicies = make(map[string]accessPolicySummary)
+	}
+
+	for _, policy := range policies {
+		if c.config.Detail {
+			metrics.accessPolicies[policy.ID] = accessPolicySummary{policy: policy}
+		}
+		entity := c.policyEntity(policy, metrics)
+		if policy.Status != StatusActive {
+			continue
+		}
+
+		rules, err := c.client.FetchPolicyRules(ctx, policy.ID)
+		if err != nil {
+			metrics.denied = c.skip("policy", ScopePoliciesRead, err) || metrics.denied
+			continue
+		}
+
+		processRuleConditions(rules, metrics)
+		if entity != nil {
+			mfaRequired := evaluateAccessRules(policy.ID, rules).MFARequired
+			entity.MFARequired = &mfaRequired
+		}
+		if c.config.Detail {
+			metrics.accessPolicies[policy.ID] = accessPolicySummary{policy: policy, evaluation: evaluateAccessRules(policy.ID, rules)}
+		}
+	}
+}
+
+// processRuleConditions collects metrics from the conditions of every active
+// rule, not just the first, since any of them may match a sign-in.
+func processRuleConditions(rules []okta.PolicyRule, metrics *policyMetricsCollector) {
+	for _, rule := range rules {
+		if rule.Status != StatusActive {
+			continue
+		}
+		metrics.activeRules++
+
+		conditions := rule.Conditions
+		riskScore := conditions.RiskScore != nil && conditions.RiskScore.Level != "" && conditions.RiskScore.Level != RiskLevelAny
+		behaviors := conditions.Risk != nil && len(conditions.Risk.Behaviors) > 0
+		if riskScore || behaviors {
+			metrics.riskBasedRules++
+		}
+
+		if ruleAccess(rule) == AccessDeny {
+			metrics.denyRules++
+		}
+		if hasNetworkCondition(conditions) {
+			metrics.networkRules++
+			if ruleAccess(rule) == AccessDeny {
+				metrics.zoneDenyRules++
+				for _, zoneID := range conditions.Network.Include {
+					metrics.denyZones[zoneID] = true
+				}
+			}
+		}
+	}
+}
+
+// sortByPriority orders rules as Okta evaluates them, highest priority
+// (lowest number) first.
+func sortByPriority(rules []okta.PolicyRule) {
+	slices.SortStableFunc(rules, func(a, b okta.PolicyRule) int {
+		return a.Priority - b.Priority
+	})
+}
+
+// catchAllAllowsWithoutMFA reports whether the last active rule of a
+// priority-sorted sign-on policy, which applies to every sign-in no other
+// rule matched, allows access without MFA.
+func catchAllAllowsWithoutMFA(rules []okta.PolicyRule) bool {
+	for i := len(rules) - 1; i >= 0; i-- {
+		rule := rules[i]
+		if rule.Status != StatusActive || rule.Actions.Signon == nil {
+			continue
+		}
+		return rule.Actions.Signon.Access == AccessAllow && !rule.Actions.Signon.RequireFactor
+	}
+	return false
+}
+
+// hasNetworkCondition reports whether a rule only applies to some networks.
+func hasNetworkCondition(conditions okta.PolicyRuleConditions) bool {
+	network := conditions.Network
+	return network != nil && network.Connection != "" && network.Connection != NetworkAnywhere
+}
+
+// ruleAccess returns the access decision of a sign-on or authentication
+// policy rule.
+func ruleAccess(rule okta.PolicyRule) string {
+	switch {
+	case rule.Actions.Signon != nil:
+		return rule.Actions.Signon.Access
+	case rule.Actions.AppSignOn != nil:
+		return rule.Actions.AppSignOn.Access
+	}
+	return ""
+}
+
+// collectMFAEnrollPolicies collects MFA enrollment policy metrics.
+func (c *Collector) collectMFAEnrollPolicies(ctx context.Context, metrics *policyMetricsCollector) {
+	policies, err := c.client.FetchPolicies(ctx, PolicyTypeMFAEnroll)
+	if err != nil {
+		c.skip("mfa_enrollment", ScopePoliciesRead, err)
+		return
+	}
+
+	metrics.enrollment = newMFAEnrollment()
+	defer metrics.enrollment.rollup()
+
+	for _, policy := range policies {
+		if policy.Status != StatusActive {
+			continue
+		}
+		metrics.enrollment.addPolicy(policy)
+		for factor, setting := range policy.Settings.Factors {
+			if metrics.classicFactors == nil {
+				metrics.classicFactors = map[string]bool{}
+			}
+			metrics.classicFactors[factor] = metrics.classicFactors[factor] || setting.Enroll.Self != EnrollNotAllowed
+		}
+		if isPasswordOptional(policy) {
+			metrics.passwordOptionalPolicies++
+		}
+
+		rules, err := c.client.FetchPolicyRules(ctx, policy.ID)
+		if err != nil {
+			c.skip("mfa_enrollment", ScopePoliciesRead, err)
+			continue
+		}
+
+		c.processMFAEnrollRules(rules, metrics)
+	}
+}
+
+// isPasswordOptional reports whether an authenticator enrollment policy lets
+// users go without a password.
+func isPasswordOptional(policy okta.Policy) bool {
+	for _, authenticator := range policy.Settings.Authenticators {
+		if authenticator.Key == AuthenticatorPassword {
+			return authenticator.Enroll.Self != EnrollRequired
+		}
+	}
+	return false
+}
+
+// processMFAEnrollRules processes MFA enrollment policy rules.
+func (c *Collector) processMFAEnrollRules(rules []okta.PolicyRule, metrics *policyMetricsCollector) {
+	for _, rule := range rules {
+		if rule.Status != StatusActive || rule.Actions.Enroll == nil {
+			continue
+		}
+
+		enrollAction := strings.ToUpper(rule.Actions.Enroll.Self)
+		if enrollAction == MFAActionChallenge || enrollAction == MFAActionLogin {
+			metrics.mfaRequiredCount++
+		}
+		return // Use first active rule per policy
+	}
+}
+
+// updateMinMax updates min/max pointers with a new value if applicable.
+func updateMinMax(min, max **int, value int) {
+	if value <= 0 {
+		return
+	}
+	if *min == nil || value < **min {
+		*min = &value
+	}
+	if *max == nil || value > **max {
+		*max = &value
+	}
+}
+
+// userAgent builds the User-Agent sent to Okta so support can identify our traffic.
+func userAgent(version, runID string) string {
+	if version == "" {
+		version = "dev"
+	}
+	return fmt.Sprintf("%s/%s (run %s)", okta.DefaultUserAgent, version, runID)
+}
+
+// nextRunID gives each collection its own run ID, when the ID was generated
+// rather than configured, so snapshots of a long-running process don't
+// share one. The User-Agent follows so Okta support sees the same ID.
+func (c *Collector) nextRunID() {
+	if !c.generatedRunID {
+		return
+	}
+	c.config.RunID = newRunID()
+	if client, ok := c.client.(interface{ SetUserAgent(string) }); ok {
+		client.SetUserAgent(userAgent(c.config.Version, c.config.RunID))
+	}
+}
+
+// newRunID generates a random RFC 4122 version 4 UUID for run correlation.
+func newRunID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// idempotencyKey returns Config.IdempotencyKey or, if that is unset, a key
+// derived from the org and the schedule slot a run started in, so a retry
+// of a run within its slot produces documents with the same key.
+func (c *Collector) idempotencyKey(started time.Time) string {
+	if c.config.IdempotencyKey != "" {
+		return c.config.IdempotencyKey
+	}
+	interval := c.config.ScheduleInterval
+	if interval <= 0 {
+		interval = DefaultScheduleInterval
+	}
+	slot := started.UTC().Truncate(interval)
+	sum := sha256.Sum256([]byte(strings.ToLower(c.config.OrgDomain) + "\n" + slot.Format(time.RFC3339)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// percent calculates the percentage of count over total, returning 0 if total is 0.
+func percent(count, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return (count * MaxPercentage) / total
+}
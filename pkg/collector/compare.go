@@ -0,0 +1,190 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Comparison ranks several orgs' posture documents against each other,
+// for example the tenants an acquisition brings in.
+type Comparison struct {
+	ComparedAt string             `json:"compared_at"`
+	Orgs       []ComparedOrg      `json:"orgs"`    // Weakest first: most metrics where the org is weakest
+	Metrics    []MetricComparison `json:"metrics"` // In the order of comparisonMetrics
+}
+
+// ComparedOrg is one org in a comparison.
+type ComparedOrg struct {
+	OrgDomain     string `json:"org_domain"`
+	CollectedAt   string `json:"collected_at"`
+	SchemaVersion string `json:"schema_version"`
+	WeakestIn     int    `json:"weakest_in"` // Metrics where this org has the worst value
+}
+
+// MetricComparison is one metric across the compared orgs.
+type MetricComparison struct {
+	Metric         string              `json:"metric"`           // Metric name as in the CSV and Prometheus outputs
+	HigherIsBetter bool                `json:"higher_is_better"` // Direction in which the metric improves
+	Values         map[string]*float64 `json:"values"`           // By org domain; null when the org doesn't report it
+	Weakest        []string            `json:"weakest"`          // Orgs with the worst value; empty when all orgs tie
+}
+
+// comparisonMetric is a metric ranked in comparisons and the direction in
+// which it improves.
+type comparisonMetric struct {
+	name           string
+	higherIsBetter bool
+}
+
+// comparisonMetrics are the metrics a comparison ranks orgs by. Counts and
+// metrics that only describe an org's size or setup are left out, since a
+// larger value is neither better nor worse.
+var comparisonMetrics = []comparisonMetric{
+	{"posture_mfa_coverage", true},
+	{"posture_mfa_phishing_resistant", true},
+	{"posture_passwordless_eligible", true},
+	{"posture_sso_coverage", true},
+	{"posture_sms_factor_enabled", false},
+	{"posture_voice_factor_enabled", false},
+	{"posture_email_factor_as_mfa_enabled", false},
+	{"posture_security_question_enabled", false},
+	{"users_password_expired", false},
+	{"users_locked_out", false},
+	{"users_inactive", false},
+	{"apps_provisioning_enabled", true},
+	{"apps_deprovisioning_enabled", true},
+	{"apps_assigned_to_everyone", false},
+	{"apps_auto_submit_toolbar", false},
+	{"policy_mfa_required_all", true},
+	{"policy_catch_all_allow_without_mfa", false},
+	{"policy_network_restricted", true},
+	{"policy_session_lifetime_max_minutes", false},
+	{"policy_idle_timeout_max_minutes", false},
+}
+
+// ParsePostureDocument reads a v1 or v2 posture document, compressed or
+// not, as written by the collector.
+func ParsePostureDocument(data []byte) (*OrgPosture, error) {
+	var probe struct {
+		Encoding string `json:"encoding"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	var posture OrgPosture
+	if probe.Encoding != "" {
+		var doc CompressedDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		if err := doc.Decode(&posture); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &posture); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(SupportedSchemaVersions, posture.SchemaVersion) && posture.SchemaVersion != InitialSchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %q (supported: %v)", posture.SchemaVersion, SupportedSchemaVersions)
+	}
+	if posture.OrgDomain == "" {
+		return nil, fmt.Errorf("document has no org_domain")
+	}
+	return &posture, nil
+}
+
+// Compare ranks the postures of two or more distinct orgs. For each metric
+// the orgs with the worst value are marked weakest; orgs that don't report
+// a metric are not ranked on it.
+func Compare(postures []*OrgPosture) (*Comparison, error) {
+	if len(postures) < 2 {
+		return nil, fmt.Errorf("comparison needs at least 2 posture documents, got %d", len(postures))
+	}
+
+	result := &Comparison{
+		ComparedAt: time.Now().UTC().Format(time.RFC3339),
+		Orgs:       make([]ComparedOrg, 0, len(postures)),
+		Metrics:    make([]MetricComparison, 0, len(comparisonMetrics)),
+	}
+	values := make(map[string]map[string]float64, len(postures)) // Org domain to metric values
+	for _, posture := range postures {
+		if _, ok := values[posture.OrgDomain]; ok {
+			return nil, fmt.Errorf("org %s appears more than once", posture.OrgDomain)
+		}
+		metrics := make(map[string]float64)
+		for _, metric := range posture.Metrics() {
+			metrics[metric.Name] = metric.Value
+		}
+		values[posture.OrgDomain] = metrics
+		result.Orgs = append(result.Orgs, ComparedOrg{
+			OrgDomain:     posture.OrgDomain,
+			CollectedAt:   posture.CollectedAt,
+			SchemaVersion: posture.SchemaVersion,
+		})
+	}
+
+	weakestIn := make(map[string]int)
+	for _, metric := range comparisonMetrics {
+		comparison := MetricComparison{
+			Metric:         metric.name,
+			HigherIsBetter: metric.higherIsBetter,
+			Values:         make(map[string]*float64, len(postures)),
+			Weakest:        []string{},
+		}
+		var reported []string
+		for _, org := range result.Orgs {
+			value, ok := values[org.OrgDomain][metric.name]
+			if !ok {
+				comparison.Values[org.OrgDomain] = nil
+				continue
+			}
+			comparison.Values[org.OrgDomain] = &value
+			reported = append(reported, org.OrgDomain)
+		}
+
+		worst, best := worstAndBest(reported, values, metric)
+		if len(reported) >= 2 && worst != best {
+			for _, org := range reported {
+				if values[org][metric.name] == worst {
+					comparison.Weakest = append(comparison.Weakest, org)
+					weakestIn[org]++
+				}
+			}
+			slices.Sort(comparison.Weakest)
+		}
+		result.Metrics = append(result.Metrics, comparison)
+	}
+
+	for i := range result.Orgs {
+		result.Orgs[i].WeakestIn = weakestIn[result.Orgs[i].OrgDomain]
+	}
+	slices.SortStableFunc(result.Orgs, func(a, b ComparedOrg) int {
+		if a.WeakestIn != b.WeakestIn {
+			return b.WeakestIn - a.WeakestIn
+		}
+		return strings.Compare(a.OrgDomain, b.OrgDomain)
+	})
+	return result, nil
+}
+
+// worstAndBest returns the worst and best value of a metric among the
+// orgs that report it.
+func worstAndBest(orgs []string, values map[string]map[string]float64, metric comparisonMetric) (worst, best float64) {
+	for i, org := range orgs {
+		value := values[org][metric.name]
+		if i == 0 {
+			worst, best = value, value
+			continue
+		}
+		if metric.higherIsBetter {
+			worst, best = min(worst, value), max(best, value)
+		} else {
+			worst, best = max(worst, value), min(best, value)
+		}
+	}
+	return worst, best
+}
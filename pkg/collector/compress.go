@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// CompressedDocument carries a JSON document as gzip-compressed, base64
+// encoded bytes. The epack collector protocol only carries JSON, so this
+// wrapper is how a large document fits under the runner's output limit.
+type CompressedDocument struct {
+	Encoding  string `json:"encoding"`   // Always "gzip"
+	MediaType string `json:"media_type"` // Type of the decompressed content, "application/json"
+	Size      int    `json:"size"`       // Length of the decompressed document in bytes
+	SHA256    string `json:"sha256"`     // Hex SHA-256 of the decompressed document
+	Data      []byte `json:"data"`       // Compressed document; base64 in JSON
+}
+
+// CompressDocument encodes doc as JSON and compresses it. It returns nil
+// when the encoded document is smaller than minBytes, so small documents
+// are emitted as they are.
+func CompressDocument(doc any, minBytes int) (*CompressedDocument, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < minBytes {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return &CompressedDocument{
+		Encoding:  CompressionGzip,
+		MediaType: "application/json",
+		Size:      len(data),
+		SHA256:    hex.EncodeToString(sum[:]),
+		Data:      buf.Bytes(),
+	}, nil
+}
+
+// Decode decompresses the document, checks it against the recorded size
+// and digest, and unmarshals it into v.
+func (d *CompressedDocument) Decode(v any) error {
+	if d.Encoding != CompressionGzip {
+		return fmt.Errorf("unsupported encoding %q", d.Encoding)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(d.Data))
+	if err != nil {
+		return fmt.Errorf("decompressing document: %w", err)
+	}
+	// Read one byte past the recorded size to catch a longer document
+	data, err := io.ReadAll(io.LimitReader(zr, int64(d.Size)+1))
+	if err != nil {
+		return fmt.Errorf("decompressing document: %w", err)
+	}
+	if len(data) != d.Size {
+		return fmt.Errorf("decompressed document is not %d bytes", d.Size)
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != d.SHA256 {
+		return fmt.Errorf("decompressed document does not match sha256 %s", d.SHA256)
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,259 @@
+package collector
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// configSchemaJSON is the JSON Schema for the collector configuration.
+//
+//go:embed config.schema.json
+var configSchemaJSON []byte
+
+// ConfigSchema returns the JSON Schema describing the collector configuration.
+func ConfigSchema() []byte {
+	return configSchemaJSON
+}
+
+// configSchema is the subset of JSON Schema used by config.schema.json.
+type configSchema struct {
+	Required             []string                  `json:"required"`
+	AdditionalProperties bool                      `json:"additionalProperties"`
+	Properties           map[string]schemaProperty `json:"properties"`
+}
+
+// schemaProperty describes a single configuration key. Object properties
+// list their keys in Properties and reject any others.
+type schemaProperty struct {
+	Type       string                    `json:"type"`
+	Enum       []any                     `json:"enum"`
+	Minimum    *float64                  `json:"minimum"`
+	Maximum    *float64                  `json:"maximum"`
+	MinLength  *int                      `json:"minLength"`
+	Items      *schemaProperty           `json:"items"`
+	Properties map[string]schemaProperty `json:"properties"`
+	Required   []string                  `json:"required"` // Keys an object property must have
+}
+
+// ValidateConfig validates a raw configuration map against the config schema.
+// Errors name the offending key and the expected type.
+func ValidateConfig(cfg map[string]any) error {
+	var schema configSchema
+	if err := json.Unmarshal(configSchemaJSON, &schema); err != nil {
+		return fmt.Errorf("invalid embedded config schema: %w", err)
+	}
+
+	// Check unknown keys in sorted order for stable error messages
+	keys := make([]string, 0, len(cfg))
+	for key := range cfg {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		prop, ok := schema.Properties[key]
+		if !ok {
+			if schema.AdditionalProperties {
+				continue
+			}
+			if suggestion := closestKey(key, schema.Properties); suggestion != "" {
+				return fmt.Errorf("unknown config key %q (did you mean %q?)", key, suggestion)
+			}
+			return fmt.Errorf("unknown config key %q", key)
+		}
+		if err := validateValue(key, prop, cfg[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range schema.Required {
+		if _, ok := cfg[key]; !ok {
+			return fmt.Errorf("%s is required", key)
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks a single value against its schema property.
+func validateValue(key string, prop schemaProperty, value any) error {
+	switch prop.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return typeError(key, prop.Type, value)
+		}
+		if prop.MinLength != nil && len(s) < *prop.MinLength {
+			return fmt.Errorf("%s must not be empty", key)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeError(key, prop.Type, value)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return typeError(key, prop.Type, value)
+		}
+		if err := checkRange(key, prop, n); err != nil {
+			return err
+		}
+	case "number":
+		n, ok := value.(float64)
+		if !ok {
+			return typeError(key, prop.Type, value)
+		}
+		if err := checkRange(key, prop, n); err != nil {
+			return err
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return typeError(key, prop.Type, value)
+		}
+		if prop.Items != nil {
+			for i, item := range items {
+				if err := validateValue(fmt.Sprintf("%s[%d]", key, i), *prop.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return typeError(key, prop.Type, value)
+		}
+		if prop.Properties != nil {
+			if err := validateObject(key, prop.Properties, obj); err != nil {
+				return err
+			}
+		}
+		for _, field := range prop.Required {
+			if _, ok := obj[field]; !ok {
+				return fmt.Errorf("%s.%s is required", key, field)
+			}
+		}
+	}
+
+	if len(prop.Enum) > 0 {
+		for _, allowed := range prop.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %v, got %v", key, prop.Enum, value)
+	}
+
+	return nil
+}
+
+// validateObject checks the keys of a nested object, named key.field in
+// errors, in sorted order for stable error messages.
+func validateObject(key string, properties map[string]schemaProperty, obj map[string]any) error {
+	fields := make([]string, 0, len(obj))
+	for field := range obj {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		prop, ok := properties[field]
+		if !ok {
+			if suggestion := closestKey(field, properties); suggestion != "" {
+				return fmt.Errorf("unknown config key %q (did you mean %q?)", key+"."+field, key+"."+suggestion)
+			}
+			return fmt.Errorf("unknown config key %q", key+"."+field)
+		}
+		if err := validateValue(key+"."+field, prop, obj[field]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRange enforces the minimum and maximum of a numeric property.
+func checkRange(key string, prop schemaProperty, n float64) error {
+	if prop.Minimum != nil && n < *prop.Minimum {
+		return fmt.Errorf("%s must be at least %v, got %v", key, *prop.Minimum, n)
+	}
+	if prop.Maximum != nil && n > *prop.Maximum {
+		return fmt.Errorf("%s must be at most %v, got %v", key, *prop.Maximum, n)
+	}
+	return nil
+}
+
+// typeError reports a value of the wrong JSON type.
+func typeError(key, expected string, value any) error {
+	return fmt.Errorf("%s must be %s %s, got %s", key, article(expected), expected, jsonType(value))
+}
+
+// article returns the indefinite article for a JSON type name.
+func article(typeName string) string {
+	switch typeName {
+	case "array", "integer", "object":
+		return "an"
+	}
+	return "a"
+}
+
+// jsonType returns the JSON type name of a decoded value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// closestKey returns the known key closest to key, if it is a likely typo.
+func closestKey(key string, properties map[string]schemaProperty) string {
+	best := ""
+	bestDistance := maxSuggestionDistance + 1
+	for candidate := range properties {
+		d := levenshtein(key, candidate)
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	if bestDistance > maxSuggestionDistance {
+		return ""
+	}
+	return best
+}
+
+// maxSuggestionDistance is the largest edit distance offered as a suggestion.
+const maxSuggestionDistance = 3
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
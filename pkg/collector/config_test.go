@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: `{"org_domain": "company.okta.com", "client_id": "0oa123"}`,
+		},
+		{
+			name:    "missing org_domain",
+			config:  `{}`,
+			wantErr: "org_domain is required",
+		},
+		{
+			name:    "misspelled key",
+			config:  `{"org_domian": "company.okta.com"}`,
+			wantErr: `unknown config key "org_domian" (did you mean "org_domain"?)`,
+		},
+		{
+			name:    "unrelated unknown key",
+			config:  `{"org_domain": "company.okta.com", "verbosity_level": 3}`,
+			wantErr: `unknown config key "verbosity_level"`,
+		},
+		{
+			name:    "wrong type",
+			config:  `{"org_domain": 42}`,
+			wantErr: "org_domain must be a string, got number",
+		},
+		{
+			name:    "above maximum",
+			config:  `{"org_domain": "company.okta.com", "system_log_lookback_days": 120}`,
+			wantErr: "system_log_lookback_days must be at most 90, got 120",
+		},
+		{
+			name:    "empty org_domain",
+			config:  `{"org_domain": ""}`,
+			wantErr: "org_domain must not be empty",
+		},
+		{
+			name:   "valid definitions",
+			config: `{"org_domain": "company.okta.com", "definitions": {"phishing_resistant_factors": ["webauthn"], "inactive_days": 30}}`,
+		},
+		{
+			name:    "misspelled definitions key",
+			config:  `{"org_domain": "company.okta.com", "definitions": {"inactive_dayz": 30}}`,
+			wantErr: `unknown config key "definitions.inactive_dayz" (did you mean "definitions.inactive_days"?)`,
+		},
+		{
+			name:    "invalid definitions value",
+			config:  `{"org_domain": "company.okta.com", "definitions": {"inactive_days": 0}}`,
+			wantErr: "definitions.inactive_days must be at least 1, got 0",
+		},
+		{
+			name:   "valid exemptions",
+			config: `{"org_domain": "company.okta.com", "exemptions": [{"finding": "idle-timeout", "object_id": "company.okta.com", "expires": "2026-12-31", "justification": "Kiosk sign-in"}]}`,
+		},
+		{
+			name:    "exemption missing justification",
+			config:  `{"org_domain": "company.okta.com", "exemptions": [{"finding": "idle-timeout", "object_id": "company.okta.com", "expires": "2026-12-31"}]}`,
+			wantErr: "exemptions[0].justification is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg map[string]any
+			if err := json.Unmarshal([]byte(tt.config), &cfg); err != nil {
+				t.Fatalf("invalid test config: %v", err)
+			}
+
+			err := ValidateConfig(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigSchemaProperties(t *testing.T) {
+	var schema configSchema
+	if err := json.Unmarshal(ConfigSchema(), &schema); err != nil {
+		t.Fatalf("config schema is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"org_domain", "client_id"} {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("config schema missing property %q", key)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"org_domain", "org_domain", 0},
+		{"org_domian", "org_domain", 2},
+		{"orgdomain", "org_domain", 1},
+		{"abc", "", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package collector
+
+import "time"
+
+// User status values.
+const (
+	StatusActive          = "ACTIVE"
+	StatusDeprovisioned   = "DEPROVISIONED"
+	StatusPasswordExpired = "PASSWORD_EXPIRED"
+	StatusLockedOut       = "LOCKED_OUT"
+)
+
+// Group rule status values.
+const (
+	GroupRuleStatusInactive = "INACTIVE"
+	GroupRuleStatusInvalid  = "INVALID"
+)
+
+// Policy types.
+const (
+	PolicyTypeSignOn    = "OKTA_SIGN_ON"
+	PolicyTypeMFAEnroll = "MFA_ENROLL"
+	PolicyTypePassword  = "PASSWORD"
+
+	PolicyTypeAccess        = "ACCESS_POLICY"  // Authentication policies (Identity Engine)
+	PolicyTypeUserLifecycle = "USER_LIFECYCLE" // Lifecycle automations
+)
+
+// Authentication policy rule values.
+const (
+	AccessAllow        = "ALLOW"
+	AccessDeny         = "DENY"
+	FactorMode2FA      = "2FA"
+	ConstraintRequired = "REQUIRED"
+	NetworkAnywhere    = "ANYWHERE"
+	NetworkZone        = "ZONE"
+	NetworkOnNetwork   = "ON_NETWORK"
+)
+
+// RiskLevelAny is the risk score condition that matches every sign-in.
+const RiskLevelAny = "ANY"
+
+// Okta's own apps. The Dashboard and Browser Plugin are assigned to
+// Everyone by design.
+const (
+	AppNameAdminConsole  = "saasure"
+	AppNameDashboard     = "okta_enduser"
+	AppNameBrowserPlugin = "okta_browser_plugin"
+)
+
+// Default catalog labels of the Okta Privileged Access app and of Advanced
+// Server Access, which it replaces.
+const (
+	AppLabelPrivilegedAccess     = "Okta Privileged Access"
+	AppLabelAdvancedServerAccess = "Okta Advanced Server Access"
+)
+
+// The built-in group containing every user.
+const (
+	GroupTypeBuiltIn  = "BUILT_IN"
+	GroupNameEveryone = "Everyone"
+)
+
+// Sign-on modes (SSO protocols).
+const (
+	SignOnModeSAML20       = "SAML_2_0"
+	SignOnModeSAML11       = "SAML_1_1"
+	SignOnModeOIDC         = "OPENID_CONNECT"
+	SignOnModeWSFederation = "WS_FEDERATION"
+)
+
+// Sign-on modes that don't federate: Okta replays stored credentials, or
+// only links to the app.
+const (
+	SignOnModeAutoLogin           = "AUTO_LOGIN"
+	SignOnModeBrowserPlugin       = "BROWSER_PLUGIN"
+	SignOnModeSecurePasswordStore = "SECURE_PASSWORD_STORE"
+	SignOnModeBasicAuth           = "BASIC_AUTH"
+	SignOnModeBookmark            = "BOOKMARK"
+)
+
+// Application provisioning features.
+const (
+	FeaturePushNewUsers        = "PUSH_NEW_USERS"
+	FeatureImportNewUsers      = "IMPORT_NEW_USERS"
+	FeaturePushUserDeactivation = "PUSH_USER_DEACTIVATION"
+)
+
+// Phishing-resistant factor types.
+const (
+	FactorTypeWebAuthn = "webauthn"
+	FactorTypeU2F      = "u2f"
+)
+
+// FactorTypeSignedNonce is Okta FastPass, which can sign in without a password.
+const FactorTypeSignedNonce = "signed_nonce"
+
+// AuthenticatorPassword is the password authenticator key.
+const AuthenticatorPassword = "okta_password"
+
+// PrimaryFactorAny lets any authenticator satisfy the first factor.
+const PrimaryFactorAny = "PASSWORD_IDP_ANY_FACTOR"
+
+// MFA enrollment actions.
+const (
+	MFAActionChallenge = "CHALLENGE"
+	MFAActionLogin     = "LOGIN"
+)
+
+// Factor enrollment requirements in MFA enrollment policies.
+const (
+	EnrollRequired   = "REQUIRED"
+	EnrollOptional   = "OPTIONAL"
+	EnrollNotAllowed = "NOT_ALLOWED"
+)
+
+// Fixture modes for offline development.
+const (
+	FixtureModeRecord = "record"
+	FixtureModeReplay = "replay"
+)
+
+// OAuth scopes that sections need, as reported in skipped entries.
+const (
+	ScopeUsersRead           = "okta.users.read"
+	ScopePoliciesRead        = "okta.policies.read"
+	ScopeGroupsRead          = "okta.groups.read"
+	ScopeLogsRead            = "okta.logs.read"
+	ScopeAgentPoolsRead      = "okta.agentPools.read"
+	ScopeOrgsRead            = "okta.orgs.read"
+	ScopeCaptchasRead        = "okta.captchas.read"
+	ScopeLogStreamsRead      = "okta.logStreams.read"
+	ScopeRolesRead           = "okta.roles.read"
+	ScopeAuthenticatorsRead  = "okta.authenticators.read"
+	ScopeFeaturesRead        = "okta.features.read"
+	ScopeThreatInsightsRead  = "okta.threatInsights.read"
+	ScopeNetworkZonesRead    = "okta.networkZones.read"
+	ScopeCertificationsRead  = "okta.governance.accessCertifications.read"
+	ScopeEntitlementsRead    = "okta.governance.entitlements.read"
+	ScopeSchemasRead         = "okta.schemas.read"
+	ScopeProfileMappingsRead = "okta.profileMappings.read"
+	ScopeBrandsRead          = "okta.brands.read"
+)
+
+// Identity Governance access certification campaign statuses.
+const (
+	CampaignStatusReady     = "READY"
+	CampaignStatusScheduled = "SCHEDULED"
+	CampaignStatusLaunching = "LAUNCHING"
+	CampaignStatusActive    = "ACTIVE"
+	CampaignStatusCompleted = "COMPLETED"
+	CampaignStatusDeleted   = "DELETED"
+)
+
+// AssignmentTypeGroup marks an admin role received through a group.
+const AssignmentTypeGroup = "GROUP"
+
+// Custom admin role permissions.
+const (
+	PermissionUsersManage  = "okta.users.manage"
+	PermissionGroupsManage = "okta.groups.manage"
+	PermissionAppsManage   = "okta.apps.manage"
+)
+
+// SettingEnabled is the status of an enabled org setting or feature.
+const SettingEnabled = "ENABLED"
+
+// Org pipelines and the engine names reported for them.
+const (
+	PipelineIdentityEngine = "idx"
+	PipelineClassic        = "v1"
+
+	EngineIdentity = "identity_engine"
+	EngineClassic  = "classic"
+)
+
+// System Log event types, actor types, and outcomes.
+const (
+	EventTypeSessionStart = "user.session.start"
+	EventTypeAuthSSO      = "user.authentication.sso"
+	EventTypeAuthViaIDP   = "user.authentication.auth_via_IDP"
+	EventTypeUserSuspend  = "user.lifecycle.suspend"
+	EventTypeSessionEnd   = "user.session.end"
+	EventTypeSessionClear = "user.session.clear"
+	EventTypeAdminAccess  = "user.session.access_admin_app"
+	EventTypeAuthViaMFA   = "user.authentication.auth_via_mfa"
+	EventTypePushDenied   = "user.mfa.okta_verify.deny_push"
+	EventTypeAccountLock  = "user.account.lock"
+
+	EventTypePrefixProvision = "application.provision."
+	EventTypePrefixRateLimit = "system.org.rate_limit."
+
+	EventTypeRateLimitWarning   = "system.org.rate_limit.warning"
+	EventTypeRateLimitViolation = "system.org.rate_limit.violation"
+	EventTypeRateLimitBurst     = "system.org.rate_limit.burst"
+
+	LogActorUser         = "User"
+	LogTargetAppInstance = "AppInstance"
+	OutcomeSuccess       = "SUCCESS"
+	OutcomeFailure       = "FAILURE"
+)
+
+// MaxConcurrentRequests bounds the Okta API requests awaiting a response
+// at once while sections are collected concurrently.
+const MaxConcurrentRequests = 4
+
+// ProvisioningFailureWindowDays is how far back provisioning failures count.
+const ProvisioningFailureWindowDays = 7
+
+// NewCountryWindowDays is the recent period whose sign-in countries are
+// compared against the rest of the System Log lookback window.
+const NewCountryWindowDays = 7
+
+// ThreatInsight actions and network zone address types.
+const (
+	ThreatInsightBlock = "block"
+	ZoneUsageBlocklist = "BLOCKLIST"
+	ZoneAddressCIDR    = "CIDR"
+	ZoneAddressRange   = "RANGE"
+)
+
+// Exempt zone entries at least as broad as an IPv4 /8 or an IPv6 /32
+// count as broad.
+const (
+	BroadIPv4PrefixBits = 8
+	BroadIPv6PrefixBits = 32
+)
+
+// DormantAdminDays is how long an admin can go without signing in to the
+// Admin Console before counting as dormant.
+const DormantAdminDays = 90
+
+// logStatusInterval is how many System Log events pass between status updates.
+const logStatusInterval = 5000
+
+// DeprovisionedWindowDays is the window for recently deprovisioned users.
+const DeprovisionedWindowDays = 30
+
+// oktaTimeFormat is the timestamp format used in Okta search expressions.
+const oktaTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// MaxSystemLogLookbackDays is the System Log retention period.
+const MaxSystemLogLookbackDays = 90
+
+// Agent pool types and agent operational statuses.
+const (
+	AgentPoolAD   = "AD"
+	AgentPoolLDAP = "LDAP"
+	AgentPoolIWA  = "IWA"
+
+	AgentStatusOperational = "OPERATIONAL"
+	AgentStatusDegraded    = "DEGRADED"
+	AgentStatusDisrupted   = "DISRUPTED"
+	AgentStatusInactive    = "INACTIVE"
+)
+
+// User schema permission principal and action that let users edit their
+// own profile attribute.
+const (
+	SchemaPrincipalSelf       = "SELF"
+	SchemaPermissionReadWrite = "READ_WRITE"
+)
+
+// Profile mapping source and target types.
+const (
+	MappingTypeUser    = "user"    // An Okta user type
+	MappingTypeAppUser = "appuser" // An app's user profile
+)
+
+// Versions of a brand's sign-in page.
+const (
+	SignInPageDefault    = "default"
+	SignInPageCustomized = "customized"
+)
+
+// Pages that can be protected by CAPTCHA.
+const (
+	CaptchaPageSignIn        = "SIGN_IN"
+	CaptchaPageRegistration  = "SSR"
+	CaptchaPagePasswordReset = "SSPR"
+)
+
+// Okta Verify authenticator key and push number challenge modes.
+const (
+	AuthenticatorOktaVerify = "okta_verify"
+
+	NumberChallengeAlways   = "ALWAYS"
+	NumberChallengeHighRisk = "HIGH_RISK_ONLY"
+	NumberChallengeNever    = "NEVER"
+)
+
+// Weak authenticators and factors. Identity Engine authenticator keys and
+// methods are listed first, then the Classic Engine factor types.
+const (
+	AuthenticatorPhone            = "phone_number"
+	AuthenticatorEmail            = "okta_email"
+	AuthenticatorSecurityQuestion = "security_question"
+	MethodSMS                     = "sms"
+	MethodVoice                   = "voice"
+	AllowedForAny                 = "any"
+
+	FactorKeySMS      = "okta_sms"
+	FactorKeyCall     = "okta_call"
+	FactorKeyEmail    = "okta_email"
+	FactorKeyQuestion = "okta_question"
+)
+
+// Compression of large emitted documents.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+
+	// DefaultCompressionMinBytes is the encoded size from which documents
+	// are compressed when compression is on.
+	DefaultCompressionMinBytes = 1 << 20
+)
+
+// Output formats of the posture documents.
+const (
+	OutputFormatJSON  = "json"
+	OutputFormatProto = "proto"
+)
+
+// DefaultScheduleInterval is the schedule slot that idempotency keys are
+// derived from when neither schedule_interval nor a daemon interval is set.
+const DefaultScheduleInterval = 24 * time.Hour
+
+// PII policies for user identifiers in detail output.
+const (
+	PIIPolicyNone   = "none"
+	PIIPolicyHash   = "hash"
+	PIIPolicyRedact = "redact"
+)
+
+// Built-in benchmark profiles, checks, results, and exemption states.
+const (
+	BenchmarkCIS12          = "cis-1.2"
+	BenchmarkInternalStrict = "internal-strict"
+
+	BenchmarkAtLeast = "at_least"
+	BenchmarkAtMost  = "at_most"
+
+	BenchmarkPass         = "pass"
+	BenchmarkFail         = "fail"
+	BenchmarkRiskAccepted = "risk_accepted"
+	BenchmarkNotEvaluated = "not_evaluated"
+
+	ExemptionApplied = "applied"
+	ExemptionExpired = "expired"
+	ExemptionUnused  = "unused"
+)
+
+// Percentage constants.
+const MaxPercentage = 100
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// SignInCountries compares the countries users signed in from over the
+// last NewCountryWindowDays with the rest of the System Log window. Sign-ins
+// from a country the org has not seen before are a lightweight signal of
+// stolen credentials in use or impossible travel.
+type SignInCountries struct {
+	WindowDays        int            `json:"window_days"`          // Recent period compared
+	BaselineDays      int            `json:"baseline_days"`        // Prior period establishing known countries
+	SignIns           map[string]int `json:"sign_ins"`             // Sign-ins in the recent period by country
+	NewCountries      []string       `json:"new_countries"`        // Countries in the recent period but not the baseline
+	NewCountrySignIns int            `json:"new_country_sign_ins"` // Sign-ins in the recent period from new countries
+}
+
+// collectSignInCountries reads successful session starts over the System
+// Log lookback window and splits them into the recent period and the
+// baseline before it. Events without a resolved country are skipped.
+func (c *Collector) collectSignInCountries(ctx context.Context) (*SignInCountries, error) {
+	days := min(c.config.SystemLogLookbackDays, MaxSystemLogLookbackDays)
+	since, until := c.logWindow("sign_in_countries", days)
+	recent := until.AddDate(0, 0, -NewCountryWindowDays)
+
+	filter := fmt.Sprintf("eventType eq %q and outcome.result eq %q", EventTypeSessionStart, OutcomeSuccess)
+
+	result := &SignInCountries{
+		WindowDays:   NewCountryWindowDays,
+		BaselineDays: days - NewCountryWindowDays,
+		SignIns:      make(map[string]int),
+		NewCountries: []string{},
+	}
+	baseline := make(map[string]bool)
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		country := event.Client.GeographicalContext.Country
+		if event.EventType != EventTypeSessionStart || event.Outcome == nil || event.Outcome.Result != OutcomeSuccess || country == "" {
+			return nil
+		}
+		if event.Published.Before(recent) {
+			baseline[country] = true
+		} else {
+			result.SignIns[country]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, country := range slices.Sorted(maps.Keys(result.SignIns)) {
+		if baseline[country] {
+			continue
+		}
+		result.NewCountries = append(result.NewCountries, country)
+		result.NewCountrySignIns += result.SignIns[country]
+	}
+	return result, nil
+}
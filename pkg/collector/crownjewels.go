@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// CrownJewelApp is the posture of one app named in crown_jewel_apps.
+// Configured entries that match no app are reported with Found false.
+type CrownJewelApp struct {
+	Match                 string `json:"match"` // Configured ID or label
+	Found                 bool   `json:"found"`
+	ID                    string `json:"id,omitempty"`
+	Label                 string `json:"label,omitempty"`
+	SignOnMode            string `json:"sign_on_mode,omitempty"`
+	SSO                   bool   `json:"sso"`                    // Signs in through SAML, OIDC, or WS-Fed
+	MFARequired           *bool  `json:"mfa_required"`           // Every allow rule of the authentication policy requires two factors; null without one
+	DeprovisioningEnabled bool   `json:"deprovisioning_enabled"` // Deactivating the Okta user deactivates the app account
+}
+
+// crownJewelMatch is an app that matched a crown_jewel_apps entry.
+type crownJewelMatch struct {
+	match string
+	app   okta.Application
+}
+
+// collectCrownJewels reports each configured crown jewel app, in config
+// order. A label can match several apps; each is reported.
+func (c *Collector) collectCrownJewels(ctx context.Context, matches []crownJewelMatch) []CrownJewelApp {
+	results := make([]CrownJewelApp, 0, len(c.config.CrownJewelApps))
+	for _, match := range c.config.CrownJewelApps {
+		found := false
+		for _, candidate := range matches {
+			if candidate.match != match {
+				continue
+			}
+			found = true
+			results = append(results, c.crownJewelPosture(ctx, candidate))
+		}
+		if !found {
+			results = append(results, CrownJewelApp{Match: match})
+		}
+	}
+	return results
+}
+
+// crownJewelPosture evaluates a matched crown jewel app.
+func (c *Collector) crownJewelPosture(ctx context.Context, match crownJewelMatch) CrownJewelApp {
+	app := match.app
+	_, deprovisioning := c.defs.provisioningFeatures(app.Features)
+	result := CrownJewelApp{
+		Match:                 match.match,
+		Found:                 true,
+		ID:                    app.ID,
+		Label:                 app.Label,
+		SignOnMode:            app.SignOnMode,
+		SSO:                   c.defs.isSSO(app.SignOnMode),
+		DeprovisioningEnabled: deprovisioning,
+	}
+	if policy := c.evaluateAppPolicy(ctx, &app); policy != nil {
+		result.MFARequired = &policy.MFARequired
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// csvIdentityColumns lead every CSV row and identify the snapshot.
+var csvIdentityColumns = []string{"collected_at", "run_id", "org_domain", "cell"}
+
+// CSVHeader returns the CSV columns: the snapshot identity followed by every
+// metric the posture can report, sorted by name. The columns depend only on
+// the collector version, not on which optional sections a run produced, so
+// rows from successive runs line up in one file.
+func CSVHeader() []string {
+	var names []string
+	t := reflect.TypeOf(OrgPosture{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+		names = appendMetricNames(names, name, field.Type, true)
+	}
+	names = appendMetricNames(names, "counts", reflect.TypeOf(Counts{}), true)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	return append(append([]string{}, csvIdentityColumns...), names...)
+}
+
+// CSVRecord returns the posture as one CSV row matching CSVHeader. Booleans
+// are 0 or 1, as in Metrics, and metrics of omitted sections are empty.
+func (o *OrgPosture) CSVRecord() []string {
+	values := make(map[string]string)
+	for _, m := range o.Metrics() {
+		values[m.Name] = strconv.FormatFloat(m.Value, 'f', -1, 64)
+	}
+
+	header := CSVHeader()
+	record := []string{o.CollectedAt, o.RunID, o.OrgDomain, o.Cell}
+	for _, name := range header[len(csvIdentityColumns):] {
+		record = append(record, values[name])
+	}
+	return record
+}
+
+// WriteCSV writes the posture as a CSV row, preceded by the header row if
+// header is set.
+func (o *OrgPosture) WriteCSV(w io.Writer, header bool) error {
+	cw := csv.NewWriter(w)
+	if header {
+		if err := cw.Write(CSVHeader()); err != nil {
+			return err
+		}
+	}
+	if err := cw.Write(o.CSVRecord()); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// appendMetricNames adds the names of the metrics a value of type t can
+// report under the given name, mirroring appendMetrics.
+func appendMetricNames(names []string, name string, t reflect.Type, topLevel bool) []string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Anonymous && field.Tag.Get("json") == "" {
+				names = appendMetricNames(names, name, field.Type, false)
+				continue
+			}
+			fieldName, _, skip := parseJSONTag(field)
+			if skip {
+				continue
+			}
+			names = appendMetricNames(names, name+"_"+fieldName, field.Type, false)
+		}
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if !topLevel {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"slices"
+	"strings"
+)
+
+// Definitions are the classifications behind the derived metrics. Frameworks
+// differ on, for example, whether SAML 1.1 counts as SSO or U2F as
+// phishing-resistant, and Okta adds sign-on modes and app features over
+// time, so each can be overridden in the config. The definitions in effect
+// are echoed into every document.
+type Definitions struct {
+	SSOSignOnModes           []string `json:"sso_sign_on_modes"`          // App sign-on modes counted as SSO
+	ProvisioningFeatures     []string `json:"provisioning_features"`      // App features counted as provisioning
+	DeprovisioningFeatures   []string `json:"deprovisioning_features"`    // App features counted as deprovisioning
+	PhishingResistantFactors []string `json:"phishing_resistant_factors"` // Factor types counted as phishing-resistant
+	PasswordlessFactors      []string `json:"passwordless_factors"`       // Factor types that make a user passwordless-eligible
+	ExcludedUserStatuses     []string `json:"excluded_user_statuses"`     // User statuses left out of user metrics
+	InactiveDays             int      `json:"inactive_days"`              // Days without activity after which a user is inactive
+}
+
+// DefaultDefinitions returns the definitions used when none are configured.
+func DefaultDefinitions() Definitions {
+	return Definitions{
+		SSOSignOnModes:           []string{SignOnModeSAML20, SignOnModeSAML11, SignOnModeOIDC, SignOnModeWSFederation},
+		ProvisioningFeatures:     []string{FeaturePushNewUsers, FeatureImportNewUsers},
+		DeprovisioningFeatures:   []string{FeaturePushUserDeactivation},
+		PhishingResistantFactors: []string{FactorTypeWebAuthn, FactorTypeU2F},
+		PasswordlessFactors:      []string{FactorTypeWebAuthn, FactorTypeSignedNonce},
+		ExcludedUserStatuses:     []string{StatusDeprovisioned},
+		InactiveDays:             InactiveDaysThreshold,
+	}
+}
+
+// withDefaults returns the definitions with every unset field taken from
+// DefaultDefinitions. Okta reports sign-on modes, app features, and
+// statuses in upper case and factor types in lower case, so configured
+// values are normalized to match.
+func (d Definitions) withDefaults() Definitions {
+	defaults := DefaultDefinitions()
+	resolve := func(values, fallback []string, normalize func(string) string) []string {
+		if values == nil {
+			return fallback
+		}
+		resolved := make([]string, 0, len(values))
+		for _, v := range values {
+			resolved = append(resolved, normalize(strings.TrimSpace(v)))
+		}
+		return resolved
+	}
+	d.SSOSignOnModes = resolve(d.SSOSignOnModes, defaults.SSOSignOnModes, strings.ToUpper)
+	d.ProvisioningFeatures = resolve(d.ProvisioningFeatures, defaults.ProvisioningFeatures, strings.ToUpper)
+	d.DeprovisioningFeatures = resolve(d.DeprovisioningFeatures, defaults.DeprovisioningFeatures, strings.ToUpper)
+	d.PhishingResistantFactors = resolve(d.PhishingResistantFactors, defaults.PhishingResistantFactors, strings.ToLower)
+	d.PasswordlessFactors = resolve(d.PasswordlessFactors, defaults.PasswordlessFactors, strings.ToLower)
+	d.ExcludedUserStatuses = resolve(d.ExcludedUserStatuses, defaults.ExcludedUserStatuses, strings.ToUpper)
+	if d.InactiveDays <= 0 {
+		d.InactiveDays = defaults.InactiveDays
+	}
+	return d
+}
+
+// isSSO reports whether an app sign-on mode counts as SSO.
+func (d Definitions) isSSO(mode string) bool {
+	return slices.Contains(d.SSOSignOnModes, mode)
+}
+
+// provisioningFeatures reports whether an app's features include
+// provisioning and deprovisioning.
+func (d Definitions) provisioningFeatures(features []string) (provisioning, deprovisioning bool) {
+	for _, feature := range features {
+		if slices.Contains(d.ProvisioningFeatures, feature) {
+			provisioning = true
+		}
+		if slices.Contains(d.DeprovisioningFeatures, feature) {
+			deprovisioning = true
+		}
+	}
+	return
+}
+
+// isPhishingResistant reports whether a lower-cased factor type counts as
+// phishing-resistant.
+func (d Definitions) isPhishingResistant(factorType string) bool {
+	return slices.Contains(d.PhishingResistantFactors, factorType)
+}
+
+// isPasswordless reports whether a lower-cased factor type makes a user
+// passwordless-eligible.
+func (d Definitions) isPasswordless(factorType string) bool {
+	return slices.Contains(d.PasswordlessFactors, factorType)
+}
+
+// isExcluded reports whether users with a status are left out of user metrics.
+func (d Definitions) isExcluded(status string) bool {
+	return slices.Contains(d.ExcludedUserStatuses, status)
+}
@@ -0,0 +1,71 @@
+package collector
+
+import "slices"
+
+// EffectiveConfig is the configuration a document was collected with,
+// echoed so consumers know which settings produced the numbers. It holds
+// only settings that change what is collected or how it is counted; the
+// metric definitions, inactive_days among them, are echoed separately in
+// Definitions. Credentials, key IDs, and paths are never included.
+type EffectiveConfig struct {
+	GroupsInclude         []string `json:"groups_include"`           // Groups user metrics were limited to
+	UserFilter            string   `json:"user_filter"`              // Okta search expression user metrics were limited to; follows pii_policy
+	SystemLogLookbackDays int      `json:"system_log_lookback_days"` // System Log window for activity enrichment; 0 when off
+	OAuthScopes           []string `json:"oauth_scopes"`             // Additional scopes, which enable optional sections
+	Detail                bool     `json:"detail"`                   // Evidence lists were collected
+	Entities              bool     `json:"entities"`                 // Entity documents were emitted
+	PIIPolicy             string   `json:"pii_policy"`               // none, hash, or redact
+	PrimaryEmailDomains   []string `json:"primary_email_domains"`    // Domains that make an admin internal
+	CrownJewelApps        []string `json:"crown_jewel_apps"`         // Apps reported individually
+	MFAGroups             []string `json:"mfa_groups"`               // Groups with MFA coverage reported individually
+	SensitiveAttributes   []string `json:"sensitive_attributes"`     // Attributes looked for in outbound profile mappings
+	PAMTeam               string   `json:"pam_team"`                 // Privileged Access team counted; empty when none
+	Benchmark             string   `json:"benchmark"`                // Benchmark profile checked; empty when none
+}
+
+// effectiveConfig returns the sanitized echo of a configuration. Lists are
+// sorted, and unset ones are empty rather than null, so the section has
+// the same shape in every document.
+func (config Config) effectiveConfig() EffectiveConfig {
+	sorted := func(values []string) []string {
+		values = slices.Clone(values)
+		if values == nil {
+			values = []string{}
+		}
+		slices.Sort(values)
+		return values
+	}
+	pii := config.PIIPolicy
+	if pii == "" {
+		pii = PIIPolicyNone
+	}
+	return EffectiveConfig{
+		GroupsInclude:         sorted(config.GroupsInclude),
+		UserFilter:            config.echoedUserFilter(),
+		SystemLogLookbackDays: config.SystemLogLookbackDays,
+		OAuthScopes:           sorted(config.OAuthScopes),
+		Detail:                config.Detail,
+		Entities:              config.Entities,
+		PIIPolicy:             pii,
+		PrimaryEmailDomains:   sorted(config.PrimaryEmailDomains),
+		CrownJewelApps:        sorted(config.CrownJewelApps),
+		MFAGroups:             sorted(config.MFAGroups),
+		SensitiveAttributes:   sorted(config.SensitiveAttributes),
+		PAMTeam:               config.PAMTeam,
+		Benchmark:             config.Benchmark,
+	}
+}
+
+// echoedUserFilter returns user_filter as echoed in documents. Filters often
+// name users, e.g. profile.email eq "alice@company.com", so the expression
+// follows the PII policy: hashed whole under hash, and left out under
+// redact or an unknown policy.
+func (config Config) echoedUserFilter() string {
+	switch config.PIIPolicy {
+	case "", PIIPolicyNone:
+		return config.UserFilter
+	case PIIPolicyHash:
+		return hashIdentifier(config.UserFilter)
+	}
+	return ""
+}
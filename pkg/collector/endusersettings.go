@@ -0,0 +1,26 @@
+package collector
+
+import "context"
+
+// EndUserSettings reports what users can change on their own. Each is a
+// path an attacker who has talked a user into acting, or who holds a
+// session, can take without an admin.
+type EndUserSettings struct {
+	SelfServiceFactorReset bool `json:"self_service_factor_reset"` // Users can reset their own enrolled factors
+	ProfileEdits           bool `json:"profile_edits"`             // Users can edit their profile from Settings
+	PersonalApps           bool `json:"personal_apps"`             // Users can add personal apps to their dashboard
+}
+
+// collectEndUserSettings fetches the end-user settings. It returns nil if
+// they can't be read.
+func (c *Collector) collectEndUserSettings(ctx context.Context) *EndUserSettings {
+	settings, err := c.client.FetchEndUserSettings(ctx)
+	if err != nil || settings == nil {
+		return nil
+	}
+	return &EndUserSettings{
+		SelfServiceFactorReset: settings.SelfServiceFactorResetEnabled,
+		ProfileEdits:           settings.ProfileEditEnabled,
+		PersonalApps:           settings.PersonalAppsEnabled,
+	}
+}
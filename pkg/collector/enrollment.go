@@ -0,0 +1,103 @@
+package collector
+
+import (
+	"slices"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// MFAEnrollment breaks down factor enrollment requirements across active
+// MFA enrollment policies. Factors are Classic Engine factor types or
+// Identity Engine authenticator keys.
+type MFAEnrollment struct {
+	Required []string           `json:"required"` // Factors required by at least one policy
+	Optional []string           `json:"optional"` // Factors optional in at least one policy
+	Disabled []string           `json:"disabled"` // Factors not allowed by any policy
+	Policies []EnrollmentPolicy `json:"policies"` // Per-policy breakdown
+}
+
+// EnrollmentPolicy is the factor breakdown of one MFA enrollment policy.
+type EnrollmentPolicy struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Required []string `json:"required"`
+	Optional []string `json:"optional"`
+	Disabled []string `json:"disabled"`
+}
+
+// newMFAEnrollment returns an MFAEnrollment with empty (non-nil) lists.
+func newMFAEnrollment() *MFAEnrollment {
+	return &MFAEnrollment{
+		Required: []string{},
+		Optional: []string{},
+		Disabled: []string{},
+		Policies: []EnrollmentPolicy{},
+	}
+}
+
+// addPolicy adds an MFA enrollment policy's factor settings.
+func (e *MFAEnrollment) addPolicy(policy okta.Policy) {
+	breakdown := EnrollmentPolicy{
+		ID:       policy.ID,
+		Name:     policy.Name,
+		Required: []string{},
+		Optional: []string{},
+		Disabled: []string{},
+	}
+	add := func(factor, requirement string) {
+		switch requirement {
+		case EnrollRequired:
+			breakdown.Required = append(breakdown.Required, factor)
+		case EnrollOptional:
+			breakdown.Optional = append(breakdown.Optional, factor)
+		case EnrollNotAllowed:
+			breakdown.Disabled = append(breakdown.Disabled, factor)
+		}
+	}
+	for factor, setting := range policy.Settings.Factors {
+		add(factor, setting.Enroll.Self)
+	}
+	for _, authenticator := range policy.Settings.Authenticators {
+		add(authenticator.Key, authenticator.Enroll.Self)
+	}
+	slices.Sort(breakdown.Required)
+	slices.Sort(breakdown.Optional)
+	slices.Sort(breakdown.Disabled)
+
+	e.Policies = append(e.Policies, breakdown)
+}
+
+// rollup computes the org-wide lists from the per-policy breakdowns. A
+// factor is disabled org-wide only if no policy allows enrolling it.
+func (e *MFAEnrollment) rollup() {
+	required, optional, disabled := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, policy := range e.Policies {
+		for _, factor := range policy.Required {
+			required[factor] = true
+		}
+		for _, factor := range policy.Optional {
+			optional[factor] = true
+		}
+		for _, factor := range policy.Disabled {
+			disabled[factor] = true
+		}
+	}
+	e.Required = sortedKeys(required)
+	e.Optional = sortedKeys(optional)
+	e.Disabled = []string{}
+	for _, factor := range sortedKeys(disabled) {
+		if !required[factor] && !optional[factor] {
+			e.Disabled = append(e.Disabled, factor)
+		}
+	}
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Entity document kinds.
+const (
+	EntityKindUser   = "user"
+	EntityKindApp    = "app"
+	EntityKindPolicy = "policy"
+)
+
+// Entities holds per-entity records behind the aggregate metrics. It is
+// collected only when Config.Entities is set.
+type Entities struct {
+	Users    []UserEntity
+	Apps     []AppEntity
+	Policies []PolicyEntity
+}
+
+// UserEntity is the per-user record. Login and email follow the PII policy.
+type UserEntity struct {
+	ID                   string `json:"id"`
+	Login                string `json:"login,omitempty"`
+	Email                string `json:"email,omitempty"`
+	Status               string `json:"status"`
+	MFAEnrolled          *bool  `json:"mfa_enrolled"`           // Any active factor; null when factors can't be read
+	MFAPhishingResistant *bool  `json:"mfa_phishing_resistant"` // An active WebAuthn/FIDO2 factor
+	PasswordlessEligible *bool  `json:"passwordless_eligible"`  // An active FastPass or WebAuthn factor
+	Inactive             bool   `json:"inactive"`               // No activity for 90+ days
+}
+
+// AppEntity is the per-app record.
+type AppEntity struct {
+	ID             string `json:"id"`
+	Label          string `json:"label"`
+	Name           string `json:"name"` // App integration name, e.g. "okta_org2org"
+	Status         string `json:"status"`
+	SignOnMode     string `json:"sign_on_mode"`
+	SSO            bool   `json:"sso"`                        // SAML, OIDC, or WS-Federation
+	Provisioning   bool   `json:"provisioning"`               // Pushes or imports new users
+	Deprovisioning bool   `json:"deprovisioning"`             // Pushes user deactivation
+	AccessPolicyID string `json:"access_policy_id,omitempty"` // Identity Engine authentication policy
+}
+
+// PolicyEntity is the per-policy record for sign-on (Classic Engine) and
+// authentication (Identity Engine) policies.
+type PolicyEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"` // OKTA_SIGN_ON or ACCESS_POLICY
+	Status      string `json:"status"`
+	Priority    int    `json:"priority"`
+	MFARequired *bool  `json:"mfa_required"` // Every allow rule requires MFA; null if inactive or the rules can't be read
+}
+
+// EntityDocument wraps one entity as its own output document, carrying the
+// snapshot identity so each can be indexed on its own.
+type EntityDocument struct {
+	SchemaVersion  string `json:"schema_version"`
+	Kind           string `json:"kind"` // user, app, or policy
+	ID             string `json:"id"`
+	OrgDomain      string `json:"org_domain"`
+	CollectedAt    string `json:"collected_at"`
+	RunID          string `json:"run_id,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	Entity         any    `json:"entity"`
+}
+
+// EntityDocuments returns one document per user, app, and policy, in that
+// order and sorted by ID within each kind. It returns nil unless the
+// posture was collected with Config.Entities.
+func (o *OrgPosture) EntityDocuments() []EntityDocument {
+	if o.entities == nil {
+		return nil
+	}
+	e := o.entities
+	docs := make([]EntityDocument, 0, len(e.Users)+len(e.Apps)+len(e.Policies))
+	wrap := func(kind, id string, entity any) {
+		docs = append(docs, EntityDocument{
+			SchemaVersion:  SchemaVersion,
+			Kind:           kind,
+			ID:             id,
+			OrgDomain:      o.OrgDomain,
+			CollectedAt:    o.CollectedAt,
+			RunID:          o.RunID,
+			IdempotencyKey: o.IdempotencyKey,
+			Entity:         entity,
+		})
+	}
+	for _, user := range e.Users {
+		wrap(EntityKindUser, user.ID, user)
+	}
+	for _, app := range e.Apps {
+		wrap(EntityKindApp, app.ID, app)
+	}
+	for _, policy := range e.Policies {
+		wrap(EntityKindPolicy, policy.ID, policy)
+	}
+	return docs
+}
+
+// userEntities returns the user records sorted by ID.
+func userEntities(byID map[string]*UserEntity) []UserEntity {
+	users := make([]UserEntity, 0, len(byID))
+	for _, user := range byID {
+		users = append(users, *user)
+	}
+	slices.SortFunc(users, func(a, b UserEntity) int { return cmp.Compare(a.ID, b.ID) })
+	return users
+}
+
+// sortEntities orders app and policy records by ID.
+func sortEntities(e *Entities) {
+	slices.SortFunc(e.Apps, func(a, b AppEntity) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(e.Policies, func(a, b PolicyEntity) int { return cmp.Compare(a.ID, b.ID) })
+}
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// ErrorCategory classifies a collection failure so callers can decide
+// whether it is worth retrying and how to report it.
+type ErrorCategory string
+
+// Error categories.
+const (
+	ErrorCategoryPermission ErrorCategory = "permission" // Missing scope or admin role (403)
+	ErrorCategoryAuth       ErrorCategory = "auth"       // Invalid or expired credentials (401)
+	ErrorCategoryRateLimit  ErrorCategory = "rate_limit" // Rate limit retries exhausted (429)
+	ErrorCategoryNetwork    ErrorCategory = "network"    // Transport failures, timeouts, 5xx
+	ErrorCategoryParse      ErrorCategory = "parse"      // Malformed API response
+	ErrorCategoryUnknown    ErrorCategory = "unknown"
+)
+
+// ClassifyError returns the category of an error returned by Collect.
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryUnknown
+	}
+
+	var apiErr *okta.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized:
+			return ErrorCategoryAuth
+		case apiErr.StatusCode == http.StatusForbidden:
+			return ErrorCategoryPermission
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorCategoryRateLimit
+		case apiErr.StatusCode >= http.StatusInternalServerError:
+			return ErrorCategoryNetwork
+		}
+		return ErrorCategoryUnknown
+	}
+
+	if errors.Is(err, okta.ErrRateLimited) {
+		return ErrorCategoryRateLimit
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorCategoryParse
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, okta.ErrTimeout) {
+		return ErrorCategoryNetwork
+	}
+
+	return ErrorCategoryUnknown
+}
@@ -0,0 +1,274 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Estimate is the planned cost of a full collection, measured by a dry run
+// that counts what the collection would walk instead of walking it. It is
+// a capacity planning aid, not a guarantee: retries, System Log volume, and
+// optional sections are approximated or left out.
+type Estimate struct {
+	OrgDomain       string             `json:"org_domain"`
+	EstimatedAt     string             `json:"estimated_at"`
+	RunID           string             `json:"run_id,omitempty"`
+	Users           int                `json:"users"`            // Users in scope whose factors would be read
+	Apps            int                `json:"apps"`             // Apps in the org
+	ActiveApps      int                `json:"active_apps"`      // Apps whose group assignments would be read
+	Policies        int                `json:"policies"`         // Active policies whose rules would be read
+	Admins          int                `json:"admins"`           // Users holding admin roles; 0 if they can't be read
+	APICalls        int                `json:"api_calls"`        // Estimated requests for a full collection
+	DurationSeconds int                `json:"duration_seconds"` // Estimated wall-clock time
+	Endpoints       []EndpointEstimate `json:"endpoints"`        // Planned load per rate limit bucket
+	SystemLog       bool               `json:"system_log"`       // System Log reads are enabled; their volume is not estimated
+	DryRunCalls     int64              `json:"dry_run_calls"`    // Requests the dry run itself made
+}
+
+// EndpointEstimate is the planned load on one Okta rate limit bucket.
+type EndpointEstimate struct {
+	Endpoint       string `json:"endpoint"`         // Rate limit bucket, e.g. /api/v1/users*, which user factors and roles share
+	Calls          int    `json:"calls"`            // Estimated requests
+	LimitPerMinute *int   `json:"limit_per_minute"` // Okta's X-Rate-Limit-Limit for the bucket; null if not observed
+	MinutesAtLimit *int   `json:"minutes_at_limit"` // Minutes of the bucket's full rate limit the calls consume; null if the limit is unknown
+}
+
+// Estimation constants.
+const (
+	// fixedAPICalls approximates the single reads of org settings,
+	// features, authenticators, and the other best-effort sections.
+	fixedAPICalls = 25
+
+	// defaultRequestLatency is assumed when the dry run made no requests,
+	// e.g. with a client that bypasses HTTP.
+	defaultRequestLatency = 250 * time.Millisecond
+
+	// listPageSize is the page size okta.Client requests from list endpoints.
+	listPageSize = 200
+
+	// everyoneGroupSearch finds the built-in group holding every user.
+	everyoneGroupSearch = `type eq "BUILT_IN" and profile.name eq "Everyone"`
+)
+
+// estimatedPolicyTypes are the policy types a collection lists.
+var estimatedPolicyTypes = []string{PolicyTypeSignOn, PolicyTypeMFAEnroll, PolicyTypeAccess, PolicyTypePassword, PolicyTypeUserLifecycle}
+
+// plannedCalls are the requests a collection would make to one endpoint.
+type plannedCalls struct {
+	endpoint string // Path pattern, e.g. /api/v1/users/{id}/factors
+	calls    int
+}
+
+// Estimate counts the users, apps, policies, and admins a collection would
+// walk, makes one sample request to each per-entity endpoint to learn its
+// rate limit, and returns the planned API volume. Users are counted from
+// group member counts rather than listed, except with a user_filter,
+// which no count covers.
+func (c *Collector) Estimate(ctx context.Context) (*Estimate, error) {
+	if c.config.OrgDomain == "" {
+		return nil, fmt.Errorf("org_domain is required")
+	}
+	if c.config.UserFilter != "" && len(c.config.GroupsInclude) > 0 {
+		return nil, fmt.Errorf("user_filter and groups_include cannot be combined")
+	}
+	start := c.Stats().Requests
+	began := time.Now()
+
+	est := &Estimate{
+		OrgDomain:   c.config.OrgDomain,
+		EstimatedAt: time.Now().UTC().Format(time.RFC3339),
+		RunID:       c.config.RunID,
+		SystemLog:   c.config.SystemLogLookbackDays > 0,
+	}
+
+	c.status("Counting users...")
+	users, userPages, err := c.countUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	est.Users = users
+
+	c.status("Counting applications...")
+	var sampleApp string
+	err = c.client.FetchApplications(ctx, func(app okta.Application) error {
+		est.Apps++
+		if app.Status == StatusActive {
+			est.ActiveApps++
+			if sampleApp == "" {
+				sampleApp = app.ID
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count apps: %w", err)
+	}
+
+	c.status("Counting policies...")
+	var samplePolicy string
+	for _, policyType := range estimatedPolicyTypes {
+		policies, err := c.client.FetchPolicies(ctx, policyType)
+		if err != nil {
+			continue
+		}
+		for _, policy := range policies {
+			if policy.Status != StatusActive {
+				continue
+			}
+			est.Policies++
+			if samplePolicy == "" {
+				samplePolicy = policy.ID
+			}
+		}
+	}
+
+	c.status("Counting admins...")
+	var sampleAdmin string
+	if assignees, err := c.client.FetchRoleAssignees(ctx); err == nil {
+		est.Admins = len(assignees)
+		if len(assignees) > 0 {
+			sampleAdmin = assignees[0].ID
+		}
+	}
+
+	// Best-effort: one request per bucket reveals its rate limit. Admin
+	// roles share the /api/v1/users* bucket with user factors.
+	c.status("Sampling rate limits...")
+	if sampleApp != "" {
+		_, _ = c.client.FetchAppGroupAssignments(ctx, sampleApp)
+	}
+	if samplePolicy != "" {
+		_, _ = c.client.FetchPolicyRules(ctx, samplePolicy)
+	}
+	if sampleAdmin != "" {
+		_, _ = c.client.FetchUserRoles(ctx, sampleAdmin)
+	}
+
+	est.Endpoints = c.bucketEstimates([]plannedCalls{
+		{c.usersEndpoint(), userPages},
+		{"/api/v1/users/{id}/factors", est.Users},
+		{"/api/v1/apps", pages(est.Apps)},
+		{"/api/v1/apps/{id}/groups", est.ActiveApps},
+		{"/api/v1/policies", len(estimatedPolicyTypes)},
+		{"/api/v1/policies/{id}/rules", est.Policies},
+		{"/api/v1/users/{id}/roles", est.Admins},
+	})
+	est.APICalls = fixedAPICalls
+	for _, endpoint := range est.Endpoints {
+		est.APICalls += endpoint.Calls
+	}
+	est.DryRunCalls = c.Stats().Requests - start
+	est.DurationSeconds = estimateDuration(est, requestLatency(time.Since(began), est.DryRunCalls))
+	return est, nil
+}
+
+// countUsers returns the users a collection would walk and the list pages
+// it would read them in. Okta keeps member counts on groups, so the
+// Everyone group, or each included group, is read instead of listing its
+// members; the counts include users whose status is excluded, and members
+// of several included groups are counted once per group.
+func (c *Collector) countUsers(ctx context.Context) (users, listPages int, err error) {
+	if c.config.UserFilter != "" {
+		listed := 0
+		err := c.fetchUsers(ctx, func(user okta.User) error {
+			listed++
+			if !c.defs.isExcluded(user.Status) {
+				users++
+			}
+			return nil
+		})
+		return users, pages(listed), err
+	}
+
+	groupIDs := c.config.GroupsInclude
+	if len(groupIDs) == 0 {
+		everyone, err := c.client.SearchGroups(ctx, everyoneGroupSearch)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(everyone) == 0 {
+			return 0, 0, fmt.Errorf("the Everyone group was not found")
+		}
+		groupIDs = []string{everyone[0].ID}
+	}
+	for _, groupID := range groupIDs {
+		stats, err := c.client.FetchGroupStats(ctx, groupID)
+		if err != nil {
+			return 0, 0, err
+		}
+		users += stats.UsersCount
+		listPages += pages(stats.UsersCount)
+	}
+	return users, listPages, nil
+}
+
+// usersEndpoint returns the path pattern users are listed from.
+func (c *Collector) usersEndpoint() string {
+	if len(c.config.GroupsInclude) > 0 {
+		return "/api/v1/groups/{id}/users"
+	}
+	return "/api/v1/users"
+}
+
+// pages returns the number of list pages needed for n items.
+func pages(n int) int {
+	return max(1, (n+listPageSize-1)/listPageSize)
+}
+
+// bucketEstimates sums planned calls per Okta rate limit bucket, in the
+// order the buckets are first planned, and attaches the limit the client
+// last saw for each.
+func (c *Collector) bucketEstimates(planned []plannedCalls) []EndpointEstimate {
+	limits := make(map[string]int)
+	if client, ok := c.client.(interface{ RateLimits() []okta.RateLimitBucket }); ok {
+		for _, bucket := range client.RateLimits() {
+			limits[bucket.Bucket] = bucket.Limit
+		}
+	}
+
+	var estimates []EndpointEstimate
+	index := make(map[string]int)
+	for _, p := range planned {
+		bucket := okta.RateLimitBucketFor(p.endpoint)
+		i, ok := index[bucket]
+		if !ok {
+			i = len(estimates)
+			index[bucket] = i
+			estimates = append(estimates, EndpointEstimate{Endpoint: bucket})
+		}
+		estimates[i].Calls += p.calls
+	}
+	for i := range estimates {
+		if limit, ok := limits[estimates[i].Endpoint]; ok {
+			minutes := (estimates[i].Calls + limit - 1) / limit
+			estimates[i].LimitPerMinute = &limit
+			estimates[i].MinutesAtLimit = &minutes
+		}
+	}
+	return estimates
+}
+
+// requestLatency returns the mean time the dry run spent per request, or
+// defaultRequestLatency if it made none.
+func requestLatency(elapsed time.Duration, requests int64) time.Duration {
+	if requests <= 0 {
+		return defaultRequestLatency
+	}
+	return elapsed / time.Duration(requests)
+}
+
+// estimateDuration returns the longer of the time the calls take at the
+// collector's concurrency and the time the busiest bucket's rate limit
+// allows them in.
+func estimateDuration(est *Estimate, latency time.Duration) int {
+	seconds := int((time.Duration(est.APICalls) * latency / MaxConcurrentRequests).Seconds())
+	for _, endpoint := range est.Endpoints {
+		if endpoint.MinutesAtLimit != nil {
+			seconds = max(seconds, *endpoint.MinutesAtLimit*60)
+		}
+	}
+	return seconds
+}
@@ -0,0 +1,29 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+)
+
+// collectEveryoneAssignments finds the active apps assigned to the built-in
+// Everyone group, which grants them to every current and future user. Apps
+// whose assignments can't be read are not counted.
+func (c *Collector) collectEveryoneAssignments(ctx context.Context, metrics *appMetricsCollector) {
+	metrics.everyoneApps = []AppRef{}
+	total := int64(len(metrics.assignableApps))
+	for i, app := range metrics.assignableApps {
+		c.progress(int64(i+1), total, fmt.Sprintf("Checking assignments for app %d of %d", i+1, total))
+		assignments, err := c.client.FetchAppGroupAssignments(ctx, app.ID)
+		if err != nil {
+			continue
+		}
+		for _, assignment := range assignments {
+			group := assignment.Embedded.Group
+			if group != nil && group.Type == GroupTypeBuiltIn && group.Profile.Name == GroupNameEveryone {
+				metrics.everyoneApps = append(metrics.everyoneApps, app)
+				break
+			}
+		}
+	}
+	metrics.assignableApps = nil
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Evidence lists the users behind the aggregate user metrics.
+// It is emitted only in detail mode.
+type Evidence struct {
+	UsersWithoutMFA      []UserRef `json:"users_without_mfa" proto:"1"`      // Users with no active factor
+	PasswordExpiredUsers []UserRef `json:"password_expired_users" proto:"2"` // Users with expired passwords
+	LockedOutUsers       []UserRef `json:"locked_out_users" proto:"3"`       // Users currently locked out
+	InactiveUsers        []UserRef `json:"inactive_users" proto:"4"`         // Users inactive for 90+ days
+
+	AdminGroups    []AdminGroup `json:"admin_groups,omitempty" proto:"5"`    // Groups conferring admin roles; omitted if unreadable
+	ExternalAdmins []UserRef    `json:"external_admins,omitempty" proto:"6"` // Admins outside the primary email domains
+	DormantAdmins  []UserRef    `json:"dormant_admins,omitempty" proto:"7"`  // Admins with no recent Admin Console access
+
+	EveryoneApps []AppRef    `json:"everyone_apps" proto:"8"`          // Apps assigned to the Everyone group
+	AppPolicies  []AppPolicy `json:"app_policies,omitempty" proto:"9"` // Authentication policy of each app; Identity Engine only
+}
+
+// AppRef identifies an app in evidence output.
+type AppRef struct {
+	ID    string `json:"id" proto:"1"`
+	Label string `json:"label" proto:"2"`
+}
+
+// UserRef identifies a user in evidence output. Login and email are
+// subject to the configured PII policy.
+type UserRef struct {
+	ID    string `json:"id" proto:"1"`
+	Login string `json:"login,omitempty" proto:"2"`
+	Email string `json:"email,omitempty" proto:"3"`
+}
+
+// newEvidence returns an Evidence with empty (non-nil) lists so every list
+// is present in the output.
+func newEvidence() *Evidence {
+	return &Evidence{
+		UsersWithoutMFA:      []UserRef{},
+		PasswordExpiredUsers: []UserRef{},
+		LockedOutUsers:       []UserRef{},
+		InactiveUsers:        []UserRef{},
+		EveryoneApps:         []AppRef{},
+	}
+}
+
+// sort orders every list so snapshots of an unchanged org are identical
+// regardless of the order Okta returned users and apps in. Users are
+// sorted by ID, since logins may be hashed or redacted; apps, groups, and
+// policies by label or name, then ID.
+func (e *Evidence) sort() {
+	for _, users := range [][]UserRef{e.UsersWithoutMFA, e.PasswordExpiredUsers, e.LockedOutUsers, e.InactiveUsers, e.ExternalAdmins, e.DormantAdmins} {
+		slices.SortFunc(users, func(a, b UserRef) int { return cmp.Compare(a.ID, b.ID) })
+	}
+	for _, group := range e.AdminGroups {
+		slices.Sort(group.Roles)
+	}
+	slices.SortFunc(e.AdminGroups, func(a, b AdminGroup) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	slices.SortFunc(e.EveryoneApps, func(a, b AppRef) int {
+		return cmp.Or(cmp.Compare(a.Label, b.Label), cmp.Compare(a.ID, b.ID))
+	})
+	slices.SortFunc(e.AppPolicies, func(a, b AppPolicy) int {
+		return cmp.Or(cmp.Compare(a.Label, b.Label), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// userRef builds the evidence entry for a user, applying the PII policy.
+// Unknown policies fail closed and redact.
+func (c *Collector) userRef(user okta.User) UserRef {
+	ref := UserRef{ID: user.ID}
+	switch c.config.PIIPolicy {
+	case "", PIIPolicyNone:
+		ref.Login = user.Profile.Login
+		ref.Email = user.Profile.Email
+	case PIIPolicyHash:
+		ref.Login = hashIdentifier(user.Profile.Login)
+		ref.Email = hashIdentifier(user.Profile.Email)
+	}
+	return ref
+}
+
+// hashIdentifier returns "sha256:" followed by the hex SHA-256 of the
+// trimmed, lowercased value, so hashed identifiers can be joined against
+// other systems that hash the same way.
+func hashIdentifier(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,129 @@
+package collector
+
+// EvidenceChunk is one part of an evidence section split across several
+// documents. Its evidence has the shape of the full section, holding a
+// consecutive run of its entries; lists with no entries in the chunk are
+// empty.
+type EvidenceChunk struct {
+	SchemaVersion  string    `json:"schema_version"`
+	OrgDomain      string    `json:"org_domain"`
+	CollectedAt    string    `json:"collected_at"`
+	RunID          string    `json:"run_id,omitempty"`
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	Sequence       int       `json:"sequence"` // Position of the chunk, from 1
+	Total          int       `json:"total"`    // Number of chunks in the evidence
+	Entries        int       `json:"entries"`  // Entries in this chunk across all lists
+	Evidence       *Evidence `json:"evidence"`
+}
+
+// EvidenceManifest lists the chunks of a split evidence section, so a
+// consumer can tell when it has all of them.
+type EvidenceManifest struct {
+	SchemaVersion  string               `json:"schema_version"`
+	OrgDomain      string               `json:"org_domain"`
+	CollectedAt    string               `json:"collected_at"`
+	RunID          string               `json:"run_id,omitempty"`
+	IdempotencyKey string               `json:"idempotency_key,omitempty"`
+	ChunkSize      int                  `json:"chunk_size"` // Maximum entries per chunk
+	Entries        int                  `json:"entries"`    // Entries across all chunks
+	Chunks         []EvidenceChunkEntry `json:"chunks"`     // In sequence order
+}
+
+// EvidenceChunkEntry describes one chunk in an EvidenceManifest.
+type EvidenceChunkEntry struct {
+	Sequence int    `json:"sequence"`
+	Path     string `json:"path"` // Artifact path of the chunk
+	Entries  int    `json:"entries"`
+}
+
+// EvidenceChunks splits the evidence section into chunks of at most size
+// entries, taking the lists in the order they appear in the section. It
+// returns nil when the posture has no evidence or the evidence fits in a
+// single chunk. The manifest's chunk paths are left for the caller to set.
+func (o *OrgPosture) EvidenceChunks(size int) (*EvidenceManifest, []EvidenceChunk) {
+	e := o.Evidence
+	if e == nil || size <= 0 {
+		return nil, nil
+	}
+
+	// Each list appends entries [from, to) to a chunk's evidence
+	lists := []struct {
+		n    int
+		take func(dst *Evidence, from, to int)
+	}{
+		{len(e.UsersWithoutMFA), func(dst *Evidence, from, to int) {
+			dst.UsersWithoutMFA = append(dst.UsersWithoutMFA, e.UsersWithoutMFA[from:to]...)
+		}},
+		{len(e.PasswordExpiredUsers), func(dst *Evidence, from, to int) {
+			dst.PasswordExpiredUsers = append(dst.PasswordExpiredUsers, e.PasswordExpiredUsers[from:to]...)
+		}},
+		{len(e.LockedOutUsers), func(dst *Evidence, from, to int) {
+			dst.LockedOutUsers = append(dst.LockedOutUsers, e.LockedOutUsers[from:to]...)
+		}},
+		{len(e.InactiveUsers), func(dst *Evidence, from, to int) {
+			dst.InactiveUsers = append(dst.InactiveUsers, e.InactiveUsers[from:to]...)
+		}},
+		{len(e.AdminGroups), func(dst *Evidence, from, to int) {
+			dst.AdminGroups = append(dst.AdminGroups, e.AdminGroups[from:to]...)
+		}},
+		{len(e.ExternalAdmins), func(dst *Evidence, from, to int) {
+			dst.ExternalAdmins = append(dst.ExternalAdmins, e.ExternalAdmins[from:to]...)
+		}},
+		{len(e.DormantAdmins), func(dst *Evidence, from, to int) {
+			dst.DormantAdmins = append(dst.DormantAdmins, e.DormantAdmins[from:to]...)
+		}},
+		{len(e.EveryoneApps), func(dst *Evidence, from, to int) {
+			dst.EveryoneApps = append(dst.EveryoneApps, e.EveryoneApps[from:to]...)
+		}},
+		{len(e.AppPolicies), func(dst *Evidence, from, to int) {
+			dst.AppPolicies = append(dst.AppPolicies, e.AppPolicies[from:to]...)
+		}},
+	}
+	total := 0
+	for _, list := range lists {
+		total += list.n
+	}
+	if total <= size {
+		return nil, nil
+	}
+
+	var chunks []EvidenceChunk
+	chunk := EvidenceChunk{Evidence: newEvidence()}
+	for _, list := range lists {
+		for from := 0; from < list.n; {
+			to := min(list.n, from+size-chunk.Entries)
+			list.take(chunk.Evidence, from, to)
+			chunk.Entries += to - from
+			from = to
+			if chunk.Entries == size {
+				chunks = append(chunks, chunk)
+				chunk = EvidenceChunk{Evidence: newEvidence()}
+			}
+		}
+	}
+	if chunk.Entries > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	manifest := &EvidenceManifest{
+		SchemaVersion:  SchemaVersion,
+		OrgDomain:      o.OrgDomain,
+		CollectedAt:    o.CollectedAt,
+		RunID:          o.RunID,
+		IdempotencyKey: o.IdempotencyKey,
+		ChunkSize:      size,
+		Entries:        total,
+		Chunks:         make([]EvidenceChunkEntry, len(chunks)),
+	}
+	for i := range chunks {
+		chunks[i].SchemaVersion = SchemaVersion
+		chunks[i].OrgDomain = o.OrgDomain
+		chunks[i].CollectedAt = o.CollectedAt
+		chunks[i].RunID = o.RunID
+		chunks[i].IdempotencyKey = o.IdempotencyKey
+		chunks[i].Sequence = i + 1
+		chunks[i].Total = len(chunks)
+		manifest.Chunks[i] = EvidenceChunkEntry{Sequence: i + 1, Entries: chunks[i].Entries}
+	}
+	return manifest, chunks
+}
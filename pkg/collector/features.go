@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"context"
+	"slices"
+)
+
+// OrgFeatures describes the engine and enabled features of the org, the
+// context for metrics that only apply to some orgs.
+type OrgFeatures struct {
+	Engine  *string  `json:"engine"`  // identity_engine or classic; null if unknown
+	Enabled []string `json:"enabled"` // Enabled self-service features; null if unreadable
+}
+
+// collectOrgFeatures fetches the org engine and enabled self-service
+// features. It returns nil if neither can be read.
+func (c *Collector) collectOrgFeatures(ctx context.Context) *OrgFeatures {
+	result := &OrgFeatures{}
+	if metadata, err := c.client.FetchOrgMetadata(ctx); err == nil {
+		var engine string
+		switch metadata.Pipeline {
+		case PipelineIdentityEngine:
+			engine = EngineIdentity
+		case PipelineClassic:
+			engine = EngineClassic
+		}
+		if engine != "" {
+			result.Engine = &engine
+		}
+	}
+
+	features, err := c.client.FetchFeatures(ctx)
+	if err != nil {
+		c.skip("features", ScopeFeaturesRead, err)
+	} else {
+		result.Enabled = []string{}
+		for _, feature := range features {
+			if feature.Status == SettingEnabled {
+				result.Enabled = append(result.Enabled, feature.Name)
+			}
+		}
+		slices.Sort(result.Enabled)
+	}
+
+	if result.Engine == nil && result.Enabled == nil {
+		return nil
+	}
+	return result
+}
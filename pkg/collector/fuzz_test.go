@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// FuzzPolicyRules decodes policy rules as they come back from Okta and runs
+// them through every rule evaluation, which must tolerate any rule Okta
+// could send: missing actions, unknown values, and odd durations. To fuzz:
+//
+//	go test ./pkg/collector -run '^$' -fuzz FuzzPolicyRules -fuzztime 30s
+func FuzzPolicyRules(f *testing.F) {
+	for _, seed := range []string{
+		`[]`,
+		`[{"status":"ACTIVE","priority":1,"actions":{"signon":{"access":"ALLOW","requireFactor":true,"session":{"maxSessionIdleMinutes":120,"maxSessionLifetimeMinutes":720}}}}]`,
+		`[{"status":"ACTIVE","actions":{"signon":{"access":"DENY"}},"conditions":{"network":{"connection":"ZONE","include":["nz1"]}}}]`,
+		`[{"status":"ACTIVE","actions":{"appSignOn":{"access":"ALLOW","verificationMethod":{"factorMode":"2FA","reauthenticateIn":"PT2H","constraints":[{"possession":{"phishingResistant":"REQUIRED"}}]}}}}]`,
+		`[{"status":"ACTIVE","actions":{"appSignOn":{"access":"ALLOW","verificationMethod":{"reauthenticateIn":"P9999999999999999DT1H"}}}}]`,
+		`[{"status":"ACTIVE","actions":{"enroll":{"self":"challenge"}},"conditions":{"riskScore":{"level":"HIGH"},"risk":{"behaviors":["New Device"]}}}]`,
+		`[{"status":"ACTIVE","actions":{"signon":null,"appSignOn":null}},{"status":"INACTIVE"}]`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var rules []okta.PolicyRule
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return
+		}
+
+		c := NewWithClient(Config{}, nil)
+		metrics := &policyMetricsCollector{denyZones: make(map[string]bool)}
+		sortByPriority(rules)
+		c.processSignOnRules(rules, metrics)
+		processRuleConditions(rules, metrics)
+		catchAllAllowsWithoutMFA(rules)
+		c.processMFAEnrollRules(rules, metrics)
+
+		result := evaluateAccessRules("rst1", rules)
+		if m := result.SessionLifetimeMaxMinutes; m != nil && *m < 0 {
+			t.Fatalf("negative session lifetime %d from %s", *m, data)
+		}
+		for _, v := range []*int{metrics.sessionLifetimeMin, metrics.sessionLifetimeMax, metrics.idleTimeoutMin, metrics.idleTimeoutMax} {
+			if v != nil && *v <= 0 {
+				t.Fatalf("non-positive session bound %d from %s", *v, data)
+			}
+		}
+	})
+}
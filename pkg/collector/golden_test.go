@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/locktivity/epack-collector-okta/internal/oktafake"
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Golden flags. After an intended change to metric derivation, regenerate
+// the golden files and review the diff:
+//
+//	go test ./pkg/collector -run TestGolden -args -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files from the fixture orgs")
+
+// goldenCollectedAt replaces the collection time so golden files are stable.
+const goldenCollectedAt = "2026-01-01T00:00:00Z"
+
+func TestGolden(t *testing.T) {
+	orgs := []struct {
+		name string
+		desc string
+	}{
+		{"small", "a handful of users and apps, with a token lacking admin scopes"},
+		{"medium", "every user status and sign-on mode, paged three items at a time"},
+		{"oie", "Identity Engine with authentication policies and authenticators"},
+		{"classic", "Classic Engine with factor enrollment policies"},
+	}
+
+	for _, org := range orgs {
+		t.Run(org.name, func(t *testing.T) {
+			fake, err := oktafake.Load(filepath.Join("testdata", "orgs", org.name+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			client := okta.NewClientWithHTTP(server.Client(), server.URL)
+			client.SetToken("golden-token")
+
+			c := NewWithClient(Config{OrgDomain: org.name + ".okta.com", RunID: "golden", IdempotencyKey: "golden"}, client)
+			posture, err := c.Collect(context.Background())
+			if err != nil {
+				t.Fatalf("collection against %s org (%s) failed: %v", org.name, org.desc, err)
+			}
+			if misses := fake.Misses(); len(misses) > 0 {
+				t.Errorf("fixture has no response for %v; add them to testdata/orgs/%s.json", misses, org.name)
+			}
+
+			// Relative fixture times resolve to absolute ones in a few
+			// fields; the metrics derived from them are what is compared.
+			posture.CollectedAt = goldenCollectedAt
+			posture.CollectionStartedAt = goldenCollectedAt
+			posture.CollectionFinishedAt = goldenCollectedAt
+			posture.CollectionStats = nil
+			if s := posture.SupportAccess; s != nil && s.ExpiresAt != nil {
+				expires := goldenCollectedAt
+				s.ExpiresAt = &expires
+			}
+			got, err := json.MarshalIndent(posture.ToV2(), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "golden", org.name+".json")
+			if *updateGolden {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading golden file (run with -args -update to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("posture for %s org differs from %s; if the change is intended, run with -args -update and review the diff\n%s",
+					org.name, path, lineDiff(string(want), string(got)))
+			}
+		})
+	}
+}
+
+// lineDiff returns the first differing lines of two documents.
+func lineDiff(want, got string) string {
+	wantLines := bytes.Split([]byte(want), []byte("\n"))
+	gotLines := bytes.Split([]byte(got), []byte("\n"))
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g []byte
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if !bytes.Equal(w, g) {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Governance summarizes Okta Identity Governance adoption: access
+// certification campaigns and entitlement bundles.
+type Governance struct {
+	Certifications     *AccessCertifications `json:"access_certifications"` // null when campaigns can't be read
+	EntitlementBundles *int                  `json:"entitlement_bundles"`   // Bundles defined; null when they can't be read
+}
+
+// AccessCertifications counts access certification campaigns and the
+// reviews still open in them.
+type AccessCertifications struct {
+	Campaigns          int  `json:"campaigns"`           // Campaigns of any status, deleted ones excluded
+	ActiveCampaigns    int  `json:"active_campaigns"`    // Campaigns reviewers are working on
+	ScheduledCampaigns int  `json:"scheduled_campaigns"` // Campaigns ready or scheduled to launch
+	CompletedCampaigns int  `json:"completed_campaigns"` // Closed campaigns
+	OverdueCampaigns   int  `json:"overdue_campaigns"`   // Active campaigns past their end date
+	PendingReviews     *int `json:"pending_reviews"`     // Undecided reviews in active campaigns; null if reviews can't be read
+	OverdueReviews     *int `json:"overdue_reviews"`     // Undecided reviews in overdue campaigns; null if reviews can't be read
+}
+
+// collectGovernance reads access certification campaigns and entitlement
+// bundles. It returns nil if the org has no Identity Governance or neither
+// can be read.
+func (c *Collector) collectGovernance(ctx context.Context) *Governance {
+	governance := &Governance{}
+
+	certifications, campaignsErr := c.collectCertifications(ctx)
+	governance.Certifications = certifications
+
+	bundles, bundlesErr := c.client.FetchEntitlementBundles(ctx)
+	if bundlesErr == nil {
+		count := len(bundles)
+		governance.EntitlementBundles = &count
+	}
+
+	if governance.Certifications == nil && governance.EntitlementBundles == nil {
+		// Only denials are recorded; orgs without a license answer 404
+		c.skip("governance", ScopeCertificationsRead, campaignsErr)
+		c.skip("governance", ScopeEntitlementsRead, bundlesErr)
+		return nil
+	}
+	return governance
+}
+
+// collectCertifications counts campaigns by status and the undecided
+// reviews of active campaigns.
+func (c *Collector) collectCertifications(ctx context.Context) (*AccessCertifications, error) {
+	campaigns, err := c.client.FetchCampaigns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &AccessCertifications{}
+	pending, overdue := 0, 0
+	reviewsRead := true
+	for _, campaign := range campaigns {
+		switch campaign.Status {
+		case CampaignStatusDeleted:
+			continue
+		case CampaignStatusActive, CampaignStatusLaunching:
+			result.ActiveCampaigns++
+		case CampaignStatusReady, CampaignStatusScheduled:
+			result.ScheduledCampaigns++
+		case CampaignStatusCompleted:
+			result.CompletedCampaigns++
+		}
+		result.Campaigns++
+		if campaign.Status != CampaignStatusActive {
+			continue
+		}
+
+		late := campaignOverdue(campaign, now)
+		if late {
+			result.OverdueCampaigns++
+		}
+		if !reviewsRead {
+			continue
+		}
+		reviews, err := c.client.FetchPendingReviews(ctx, campaign.ID)
+		if err != nil {
+			reviewsRead = false
+			continue
+		}
+		pending += len(reviews)
+		if late {
+			overdue += len(reviews)
+		}
+	}
+
+	if reviewsRead {
+		result.PendingReviews = &pending
+		result.OverdueReviews = &overdue
+	}
+	return result, nil
+}
+
+// campaignOverdue reports whether an active campaign has run past its
+// scheduled end. Campaigns without a schedule are never overdue.
+func campaignOverdue(campaign okta.Campaign, now time.Time) bool {
+	schedule := campaign.ScheduleSettings
+	if schedule.StartDate.IsZero() || schedule.DurationInDays <= 0 {
+		return false
+	}
+	return now.After(schedule.StartDate.AddDate(0, 0, schedule.DurationInDays))
+}
@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// GroupRules inventories the org's group rules. A rule that references a
+// deleted group or a deactivated user keeps running, silently changing
+// which users land in groups and so which apps they can reach.
+type GroupRules struct {
+	Total         int                 `json:"total"`
+	Active        int                 `json:"active"`
+	Inactive      int                 `json:"inactive"`
+	Invalid       int                 `json:"invalid"`  // Okta marked the rule invalid, e.g. its target group was deleted
+	Orphaned      int                 `json:"orphaned"` // Rules referencing deleted groups or deactivated users
+	OrphanedRules []OrphanedGroupRule `json:"orphaned_rules"`
+}
+
+// OrphanedGroupRule is a group rule referencing groups or users that no
+// longer exist or are deactivated.
+type OrphanedGroupRule struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Status           string   `json:"status"`
+	DeletedGroups    []string `json:"deleted_groups"`    // Group IDs in the expression, exclusions, or targets that no longer exist
+	DeactivatedUsers []string `json:"deactivated_users"` // Excluded user IDs that are deprovisioned or deleted
+}
+
+// groupIDPattern matches Okta group IDs, which group rule expressions embed
+// as string literals, e.g. isMemberOfAnyGroup("00g1abcdEFGH2345ijk6").
+var groupIDPattern = regexp.MustCompile(`\b00g[0-9A-Za-z]{17}\b`)
+
+// collectGroupRules fetches the org's group rules and looks up every group
+// and user they reference. It returns nil if the rules can't be read.
+func (c *Collector) collectGroupRules(ctx context.Context) *GroupRules {
+	rules, err := c.client.FetchGroupRules(ctx)
+	if err != nil {
+		c.skip("group_rules", ScopeGroupsRead, err)
+		return nil
+	}
+
+	result := &GroupRules{
+		Total:         len(rules),
+		OrphanedRules: []OrphanedGroupRule{},
+	}
+	groupExists := make(map[string]bool)
+	userDeactivated := make(map[string]bool)
+	for _, rule := range rules {
+		switch rule.Status {
+		case StatusActive:
+			result.Active++
+		case GroupRuleStatusInactive:
+			result.Inactive++
+		case GroupRuleStatusInvalid:
+			result.Invalid++
+		}
+
+		orphan := OrphanedGroupRule{
+			ID:               rule.ID,
+			Name:             rule.Name,
+			Status:           rule.Status,
+			DeletedGroups:    []string{},
+			DeactivatedUsers: []string{},
+		}
+		for _, groupID := range ruleGroupIDs(rule) {
+			exists, ok := groupExists[groupID]
+			if !ok {
+				exists = c.groupExists(ctx, groupID)
+				groupExists[groupID] = exists
+			}
+			if !exists {
+				orphan.DeletedGroups = append(orphan.DeletedGroups, groupID)
+			}
+		}
+		for _, userID := range rule.Conditions.People.Users.Exclude {
+			deactivated, ok := userDeactivated[userID]
+			if !ok {
+				deactivated = c.userDeactivated(ctx, userID)
+				userDeactivated[userID] = deactivated
+			}
+			if deactivated && !slices.Contains(orphan.DeactivatedUsers, userID) {
+				orphan.DeactivatedUsers = append(orphan.DeactivatedUsers, userID)
+			}
+		}
+		if len(orphan.DeletedGroups) == 0 && len(orphan.DeactivatedUsers) == 0 {
+			continue
+		}
+		slices.Sort(orphan.DeactivatedUsers)
+		result.Orphaned++
+		result.OrphanedRules = append(result.OrphanedRules, orphan)
+	}
+	return result
+}
+
+// ruleGroupIDs returns the sorted, distinct IDs of the groups a rule
+// references in its expression, exclusions, and targets.
+func ruleGroupIDs(rule okta.GroupRule) []string {
+	ids := groupIDPattern.FindAllString(rule.Conditions.Expression.Value, -1)
+	ids = append(ids, rule.Conditions.People.Groups.Exclude...)
+	ids = append(ids, rule.Actions.AssignUserToGroups.GroupIDs...)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// groupExists reports whether a group can still be found. Only a 404 counts
+// as deleted; other errors give the group the benefit of the doubt.
+func (c *Collector) groupExists(ctx context.Context, groupID string) bool {
+	_, err := c.client.FetchGroup(ctx, groupID)
+	var apiErr *okta.APIError
+	return !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound
+}
+
+// userDeactivated reports whether a user is deprovisioned or deleted. As
+// with groups, only a 404 counts as deleted.
+func (c *Collector) userDeactivated(ctx context.Context, userID string) bool {
+	user, err := c.client.FetchUser(ctx, userID)
+	if err != nil {
+		var apiErr *okta.APIError
+		return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+	}
+	return user.Status == StatusDeprovisioned
+}
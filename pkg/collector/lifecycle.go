@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// OffboardingMetrics summarizes recent deprovisioning as joiner-mover-leaver
+// process evidence.
+type OffboardingMetrics struct {
+	DeprovisionedLast30Days            int  `json:"deprovisioned_last_30_days"`             // Users deprovisioned in the last 30 days
+	MedianSuspensionToDeprovisionHours *int `json:"median_suspension_to_deprovision_hours"` // Median hours from suspension to deprovisioning; null without System Log data
+}
+
+// collectOffboarding counts recently deprovisioned users and, when System
+// Log enrichment is enabled, measures how long they stayed suspended first.
+func (c *Collector) collectOffboarding(ctx context.Context) (*OffboardingMetrics, error) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -DeprovisionedWindowDays)
+
+	expression := fmt.Sprintf("status eq %q and statusChanged gt %q", StatusDeprovisioned, since.UTC().Format(oktaTimeFormat))
+	if c.config.UserFilter != "" {
+		expression = fmt.Sprintf("(%s) and %s", c.config.UserFilter, expression)
+	}
+
+	deprovisionedAt := make(map[string]time.Time)
+	err := c.client.SearchUsers(ctx, expression, func(user okta.User) error {
+		deprovisionedAt[user.ID] = user.StatusChanged
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &OffboardingMetrics{DeprovisionedLast30Days: len(deprovisionedAt)}
+	if c.config.SystemLogLookbackDays == 0 || len(deprovisionedAt) == 0 {
+		return metrics, nil
+	}
+
+	// Suspensions may precede the deprovisioning window, so search the
+	// whole lookback window
+	logSince, logUntil := c.logWindow("offboarding", min(c.config.SystemLogLookbackDays, MaxSystemLogLookbackDays))
+	filter := fmt.Sprintf("eventType eq %q and outcome.result eq %q", EventTypeUserSuspend, OutcomeSuccess)
+	suspendedAt := make(map[string]time.Time)
+	err = c.client.FetchLogEvents(ctx, logSince, logUntil, filter, func(event okta.LogEvent) error {
+		for _, target := range event.Target {
+			deprovisioned, ok := deprovisionedAt[target.ID]
+			if target.Type != LogActorUser || !ok || event.Published.After(deprovisioned) {
+				continue
+			}
+			// Keep the suspension closest to deprovisioning
+			if event.Published.After(suspendedAt[target.ID]) {
+				suspendedAt[target.ID] = event.Published
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Best-effort: the count is still valid without the timing
+		c.status(fmt.Sprintf("Warning: suspension events unavailable: %v", err))
+		return metrics, nil
+	}
+
+	hours := make([]float64, 0, len(suspendedAt))
+	for userID, suspended := range suspendedAt {
+		hours = append(hours, deprovisionedAt[userID].Sub(suspended).Hours())
+	}
+	if median, ok := medianOf(hours); ok {
+		rounded := int(math.Round(median))
+		metrics.MedianSuspensionToDeprovisionHours = &rounded
+	}
+
+	return metrics, nil
+}
+
+// medianOf returns the median of values, or false when there are none.
+func medianOf(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid], true
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2, true
+}
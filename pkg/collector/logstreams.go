@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"context"
+	"slices"
+)
+
+// LogStreaming reports whether the System Log is streamed out of Okta.
+type LogStreaming struct {
+	Configured bool     `json:"configured"` // At least one stream exists
+	Streams    int      `json:"streams"`    // Configured streams
+	Active     int      `json:"active"`     // Streams currently delivering events
+	Types      []string `json:"types"`      // Destination types of active streams
+}
+
+// collectLogStreaming fetches the org's log streams. It returns nil if they
+// can't be read.
+func (c *Collector) collectLogStreaming(ctx context.Context) *LogStreaming {
+	streams, err := c.client.FetchLogStreams(ctx)
+	if err != nil {
+		c.skip("log_streaming", ScopeLogStreamsRead, err)
+		return nil
+	}
+
+	result := &LogStreaming{
+		Configured: len(streams) > 0,
+		Streams:    len(streams),
+		Types:      []string{},
+	}
+	for _, stream := range streams {
+		if stream.Status != StatusActive {
+			continue
+		}
+		result.Active++
+		if !slices.Contains(result.Types, stream.Type) {
+			result.Types = append(result.Types, stream.Type)
+		}
+	}
+	slices.Sort(result.Types)
+	return result
+}
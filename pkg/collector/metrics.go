@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Metric is a single numeric posture value, flattened for export to
+// monitoring systems.
+type Metric struct {
+	Name  string // Section and field JSON names joined by "_", e.g. "posture_mfa_coverage"
+	Value float64
+}
+
+// Metrics flattens the numeric and boolean fields of every posture section,
+// including the raw counts, into a list sorted by name. Booleans are
+// reported as 0 or 1 and unset optional values are omitted.
+func (o *OrgPosture) Metrics() []Metric {
+	var metrics []Metric
+
+	v := reflect.ValueOf(*o)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+		metrics = appendMetrics(metrics, name, v.Field(i), true)
+	}
+	metrics = appendMetrics(metrics, "counts", reflect.ValueOf(o.counts), true)
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+// appendMetrics adds the metric values found in v under the given name.
+// Top-level scalars (schema_version, org_domain, ...) are not metrics, so
+// only struct sections are walked at the top level.
+func appendMetrics(metrics []Metric, name string, v reflect.Value, topLevel bool) []Metric {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return metrics
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			// Untagged embedded structs are flattened, as in JSON
+			if field.Anonymous && field.Tag.Get("json") == "" {
+				metrics = appendMetrics(metrics, name, v.Field(i), false)
+				continue
+			}
+			fieldName, _, skip := parseJSONTag(field)
+			if skip {
+				continue
+			}
+			metrics = appendMetrics(metrics, name+"_"+fieldName, v.Field(i), false)
+		}
+	case reflect.Bool:
+		if !topLevel {
+			value := 0.0
+			if v.Bool() {
+				value = 1
+			}
+			metrics = append(metrics, Metric{Name: name, Value: value})
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !topLevel {
+			metrics = append(metrics, Metric{Name: name, Value: float64(v.Int())})
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !topLevel {
+			metrics = append(metrics, Metric{Name: name, Value: float64(v.Uint())})
+		}
+	case reflect.Float32, reflect.Float64:
+		if !topLevel {
+			metrics = append(metrics, Metric{Name: name, Value: v.Float()})
+		}
+	}
+	return metrics
+}
+
+// Stats returns API request counters when the underlying client tracks
+// them, and zero values otherwise.
+func (c *Collector) Stats() okta.RequestStats {
+	stats, _ := c.requestStats()
+	return stats
+}
@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// WriteNDJSON writes the evidence and entity records of a posture as
+// newline-delimited JSON, one EntityDocument per line, so large detail
+// output can be streamed into downstream tools without parsing a single
+// document. Evidence records come first, with the evidence list name
+// (e.g. "users_without_mfa") as their kind, followed by the entity
+// documents. Nothing is written unless the posture was collected with
+// Config.Detail or Config.Entities.
+func (o *OrgPosture) WriteNDJSON(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	for _, doc := range o.evidenceDocuments() {
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	for _, doc := range o.EntityDocuments() {
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// evidenceDocuments returns one document per evidence entry, in the order
+// the lists appear in the evidence section.
+func (o *OrgPosture) evidenceDocuments() []EntityDocument {
+	e := o.Evidence
+	if e == nil {
+		return nil
+	}
+	var docs []EntityDocument
+	wrap := func(kind, id string, record any) {
+		docs = append(docs, EntityDocument{
+			SchemaVersion:  SchemaVersion,
+			Kind:           kind,
+			ID:             id,
+			OrgDomain:      o.OrgDomain,
+			CollectedAt:    o.CollectedAt,
+			RunID:          o.RunID,
+			IdempotencyKey: o.IdempotencyKey,
+			Entity:         record,
+		})
+	}
+	users := []struct {
+		kind string
+		refs []UserRef
+	}{
+		{"users_without_mfa", e.UsersWithoutMFA},
+		{"password_expired_users", e.PasswordExpiredUsers},
+		{"locked_out_users", e.LockedOutUsers},
+		{"inactive_users", e.InactiveUsers},
+	}
+	for _, list := range users {
+		for _, ref := range list.refs {
+			wrap(list.kind, ref.ID, ref)
+		}
+	}
+	for _, group := range e.AdminGroups {
+		wrap("admin_groups", group.ID, group)
+	}
+	for _, ref := range e.ExternalAdmins {
+		wrap("external_admins", ref.ID, ref)
+	}
+	for _, ref := range e.DormantAdmins {
+		wrap("dormant_admins", ref.ID, ref)
+	}
+	for _, app := range e.EveryoneApps {
+		wrap("everyone_apps", app.ID, app)
+	}
+	for _, policy := range e.AppPolicies {
+		wrap("app_policies", policy.ID, policy)
+	}
+	return docs
+}
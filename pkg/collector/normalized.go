@@ -45,8 +45,6 @@ func (o *OrgPosture) ToIDPPosture() *IDPPosture {
 		Provider:      "okta",
 		OrgDomain:     o.OrgDomain,
 		UserSecurity: IDPPostureUserSecurity{
-			MFACoveragePct:          float64(o.Posture.MFACoverage),
-			MFAPhishingResistantPct: float64(o.Posture.MFAPhishingResistant),
 			InactivePct:             float64(o.Users.Inactive),
 			LockedOutPct:            float64(o.Users.LockedOut),
 		},
@@ -54,16 +52,24 @@ func (o *OrgPosture) ToIDPPosture() *IDPPosture {
 			SSOCoveragePct:         float64(o.Posture.SSOCoverage),
 			ProvisioningEnabledPct: float64(o.Apps.ProvisioningEnabled),
 		},
-		Policy: IDPPosturePolicy{
-			MFARequired: o.Policy.MFARequiredAll || o.Policy.MFARequiredAny,
-		},
+	}
+
+	// The normalized schema has no nulls, so metrics Okta denied stay 0
+	if o.Posture.MFACoverage != nil {
+		posture.UserSecurity.MFACoveragePct = float64(*o.Posture.MFACoverage)
+	}
+	if o.Posture.MFAPhishingResistant != nil {
+		posture.UserSecurity.MFAPhishingResistantPct = float64(*o.Posture.MFAPhishingResistant)
+	}
+	if o.Policy != nil {
+		posture.Policy.MFARequired = o.Policy.MFARequiredAll || o.Policy.MFARequiredAny
 	}
 
 	// Use max values for session/idle timeouts (most permissive across policies)
-	if o.Policy.SessionLifetimeMaxMinutes != nil {
+	if o.Policy != nil && o.Policy.SessionLifetimeMaxMinutes != nil {
 		posture.Policy.SessionLifetimeMaxMin = *o.Policy.SessionLifetimeMaxMinutes
 	}
-	if o.Policy.IdleTimeoutMaxMinutes != nil {
+	if o.Policy != nil && o.Policy.IdleTimeoutMaxMinutes != nil {
 		posture.Policy.IdleTimeoutMaxMin = *o.Policy.IdleTimeoutMaxMinutes
 	}
 
@@ -0,0 +1,47 @@
+package collector
+
+import "context"
+
+// SecurityNotifications reports which end-user security notification emails
+// are enabled.
+type SecurityNotifications struct {
+	NewSignOn                bool     `json:"new_sign_on"`                // Email on sign-in from a new device
+	FactorEnrollment         bool     `json:"factor_enrollment"`          // Email when a factor is enrolled
+	FactorReset              bool     `json:"factor_reset"`               // Email when a factor is reset
+	PasswordChanged          bool     `json:"password_changed"`           // Email when the password changes
+	ReportSuspiciousActivity bool     `json:"report_suspicious_activity"` // Emails let users report unrecognized activity
+	Disabled                 []string `json:"disabled"`                   // Names of the disabled notifications above
+}
+
+// collectSecurityNotifications fetches the security notification settings.
+// It returns nil if they can't be read.
+func (c *Collector) collectSecurityNotifications(ctx context.Context) *SecurityNotifications {
+	settings, err := c.client.FetchSecurityNotificationSettings(ctx)
+	if err != nil || settings == nil {
+		return nil
+	}
+
+	result := &SecurityNotifications{
+		NewSignOn:                settings.SendEmailForNewDeviceEnabled,
+		FactorEnrollment:         settings.SendEmailForFactorEnrollmentEnabled,
+		FactorReset:              settings.SendEmailForFactorResetEnabled,
+		PasswordChanged:          settings.SendEmailForPasswordChangedEnabled,
+		ReportSuspiciousActivity: settings.ReportSuspiciousActivityEnabled,
+		Disabled:                 []string{},
+	}
+	for _, n := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"new_sign_on", result.NewSignOn},
+		{"factor_enrollment", result.FactorEnrollment},
+		{"factor_reset", result.FactorReset},
+		{"password_changed", result.PasswordChanged},
+		{"report_suspicious_activity", result.ReportSuspiciousActivity},
+	} {
+		if !n.enabled {
+			result.Disabled = append(result.Disabled, n.name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// PasswordPolicy reports dictionary, breach, and recovery checks across
+// active password policies. A check holds only if every policy enforces it,
+// since each policy governs its own group of users.
+type PasswordPolicy struct {
+	Policies            int  `json:"policies"`              // Active password policies
+	CommonPasswordCheck bool `json:"common_password_check"` // Every policy rejects common passwords
+	BreachedProtection  bool `json:"breached_protection"`   // Every policy acts on breached credentials
+
+	// Self-service password recovery. Factors are Classic Engine factor
+	// types or Identity Engine authenticator methods.
+	RecoveryEnabled     bool     `json:"recovery_enabled"`      // Some policy allows self-service recovery
+	RecoveryFactors     []string `json:"recovery_factors"`      // Factors that can start a recovery
+	RecoveryMFARequired bool     `json:"recovery_mfa_required"` // Every recovery path needs a second factor
+}
+
+// collectPasswordPolicy evaluates the active password policies. It returns
+// nil if they can't be read.
+func (c *Collector) collectPasswordPolicy(ctx context.Context) *PasswordPolicy {
+	policies, err := c.client.FetchPolicies(ctx, PolicyTypePassword)
+	if err != nil {
+		return nil
+	}
+
+	result := &PasswordPolicy{CommonPasswordCheck: true, BreachedProtection: true, RecoveryMFARequired: true}
+	factors := map[string]bool{}
+	for _, policy := range policies {
+		if policy.Status != StatusActive {
+			continue
+		}
+		result.Policies++
+
+		settings := policy.Settings.Password
+		if settings == nil {
+			settings = &okta.PasswordPolicySettings{}
+		}
+		if !excludesCommonPasswords(settings.Complexity) {
+			result.CommonPasswordCheck = false
+		}
+		if !actsOnBreach(settings.BreachedProtection) {
+			result.BreachedProtection = false
+		}
+
+		c.processRecoveryRules(ctx, policy, result, factors)
+	}
+	result.RecoveryFactors = sortedKeys(factors)
+
+	if result.Policies == 0 {
+		result.CommonPasswordCheck = false
+		result.BreachedProtection = false
+	}
+	return result
+}
+
+// excludesCommonPasswords reports whether the common-password dictionary
+// check is on.
+func excludesCommonPasswords(complexity *okta.PasswordComplexity) bool {
+	return complexity != nil && complexity.Dictionary != nil &&
+		complexity.Dictionary.Common != nil && complexity.Dictionary.Common.Exclude
+}
+
+// actsOnBreach reports whether breached-credential protection does
+// anything: expire the password, end sessions, or run a workflow.
+func actsOnBreach(protection *okta.BreachedProtection) bool {
+	if protection == nil {
+		return false
+	}
+	return protection.ExpireAfterDays != nil || protection.LogoutEnabled || protection.DelegatedWorkflowID != ""
+}
+
+// processRecoveryRules adds the recovery paths of a password policy's
+// active rules that allow self-service password reset. If the rules can't
+// be read, recovery is assumed to be allowed under the policy's Classic
+// Engine recovery factors.
+func (c *Collector) processRecoveryRules(ctx context.Context, policy okta.Policy, result *PasswordPolicy, factors map[string]bool) {
+	rules, err := c.client.FetchPolicyRules(ctx, policy.ID)
+	if err != nil {
+		rules = []okta.PolicyRule{{
+			Status:  StatusActive,
+			Actions: okta.PolicyRuleActions{SelfServicePasswordReset: &okta.SSPRActions{Access: AccessAllow}},
+		}}
+	}
+
+	for _, rule := range rules {
+		sspr := rule.Actions.SelfServicePasswordReset
+		if rule.Status != StatusActive || sspr == nil || sspr.Access != AccessAllow {
+			continue
+		}
+		result.RecoveryEnabled = true
+
+		var primary []string
+		var stepUp bool
+		if requirement := sspr.Requirement; requirement != nil {
+			if requirement.Primary != nil {
+				primary = requirement.Primary.Methods
+			}
+			stepUp = requirement.StepUp != nil && requirement.StepUp.Required
+		} else {
+			primary, stepUp = classicRecoveryFactors(policy.Settings.Recovery)
+		}
+
+		for _, factor := range primary {
+			factors[factor] = true
+		}
+		if len(primary) > 0 && !stepUp {
+			result.RecoveryMFARequired = false
+		}
+	}
+}
+
+// classicRecoveryFactors returns the active Classic Engine recovery factors
+// and whether the security question is required as an additional step.
+func classicRecoveryFactors(recovery *okta.RecoverySettings) ([]string, bool) {
+	if recovery == nil || recovery.Factors == nil {
+		return nil, false
+	}
+	recoveryFactors := recovery.Factors
+
+	var factors []string
+	if recoveryFactors.OktaEmail != nil && recoveryFactors.OktaEmail.Status == StatusActive {
+		factors = append(factors, FactorKeyEmail)
+	}
+	if recoveryFactors.OktaSMS != nil && recoveryFactors.OktaSMS.Status == StatusActive {
+		factors = append(factors, FactorKeySMS)
+	}
+	if recoveryFactors.OktaCall != nil && recoveryFactors.OktaCall.Status == StatusActive {
+		factors = append(factors, FactorKeyCall)
+	}
+	question := recoveryFactors.RecoveryQuestion != nil && recoveryFactors.RecoveryQuestion.Status == StatusActive
+	return factors, question
+}
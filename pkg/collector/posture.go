@@ -0,0 +1,317 @@
+// Package collector provides Okta organization posture collection functionality.
+//
+// The package can be embedded in other Go services:
+//
+//	c, err := collector.New(collector.Config{
+//		OrgDomain:  "company.okta.com",
+//		ClientID:   clientID,
+//		PrivateKey: privateKeyPEM,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	posture, err := c.Collect(ctx)
+package collector
+
+import (
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// SchemaVersion is the version of the v1 output schema. Minor versions only
+// add fields, so a reader of an earlier 1.x document can read this one.
+const SchemaVersion = "1.1.0"
+
+// InitialSchemaVersion is the first v1 schema, which SchemaVersion extends.
+// It is still accepted in schema_versions and by compare.
+const InitialSchemaVersion = "1.0.0"
+
+// InactiveDaysThreshold is the number of days after which a user is considered inactive.
+const InactiveDaysThreshold = 90
+
+// StatusReportInterval is how many streamed users or apps pass between status updates.
+const StatusReportInterval = 200
+
+// StatusFunc is called to report indeterminate status updates.
+type StatusFunc func(message string)
+
+// ProgressFunc is called to report determinate progress (current/total).
+type ProgressFunc func(current, total int64, message string)
+
+// Config holds the collector configuration passed via stdin.
+type Config struct {
+	OrgDomain  string `json:"org_domain"`  // e.g., "company.okta.com"
+	ClientID   string `json:"client_id"`   // OAuth 2.0 client ID
+	PrivateKey string `json:"private_key"` // Private key for JWT assertion (PEM)
+	APIToken   string `json:"api_token"`   // SSWS token (legacy, less secure)
+
+	// PrivateKeyID is the kid of PrivateKey's public key in Okta
+	PrivateKeyID string `json:"private_key_id"`
+
+	// NextPrivateKey is tried when Okta rejects PrivateKey, so a key can be
+	// rotated without changing the config at the same moment
+	NextPrivateKey   string `json:"next_private_key"`
+	NextPrivateKeyID string `json:"next_private_key_id"`
+
+	// ClientCertificate and ClientCertificateKey (PEM) are presented for
+	// mTLS on every connection to Okta, token exchanges included
+	ClientCertificate    string `json:"client_certificate"`
+	ClientCertificateKey string `json:"client_certificate_key"`
+
+	// AuthorizationServerID requests OAuth tokens from a custom
+	// authorization server instead of the org authorization server
+	AuthorizationServerID string `json:"authorization_server_id"`
+
+	// TokenCachePath keeps the OAuth access token in this file between
+	// runs, encrypted with the private key, until it nears expiry
+	TokenCachePath string `json:"token_cache_path"`
+
+	// SchemaVersions selects which output documents to emit (default: v1 only)
+	SchemaVersions []string `json:"schema_versions"`
+
+	// Compression of the posture documents: "none" (default) or "gzip".
+	// Documents whose JSON is at least CompressionMinBytes long (default
+	// DefaultCompressionMinBytes) are emitted as a CompressedDocument.
+	Compression         string `json:"compression"`
+	CompressionMinBytes int    `json:"compression_min_bytes"`
+
+	// OutputFormat of the posture documents: "json" (default) or "proto",
+	// which emits each as a ProtoDocument instead. Compression applies to
+	// JSON documents only.
+	OutputFormat string `json:"output_format"`
+
+	// OAuthScopes are additional scopes granted to the service app. They
+	// enable optional sections that need more than the default scopes.
+	OAuthScopes []string `json:"oauth_scopes"`
+
+	// GroupsInclude limits user metrics to members of these group IDs
+	GroupsInclude []string `json:"groups_include"`
+
+	// UserFilter limits user metrics to users matching an Okta search
+	// expression; it cannot be combined with GroupsInclude
+	UserFilter string `json:"user_filter"`
+
+	// SystemLogLookbackDays, when positive, treats successful sign-ins in
+	// the System Log over this many days (max 90) as activity, for users
+	// whose lastLogin is not updated by federated or desktop SSO sign-ins
+	SystemLogLookbackDays int `json:"system_log_lookback_days"`
+
+	// PAMTeam, with PAMKeyID and PAMKeySecret of a service user, adds the
+	// adoption of that Okta Privileged Access team to privileged_access
+	PAMTeam      string `json:"pam_team"`
+	PAMKeyID     string `json:"pam_key_id"`
+	PAMKeySecret string `json:"pam_key_secret"`
+
+	// Entities emits a document per user, app, and policy alongside the
+	// summary
+	Entities bool `json:"entities"`
+
+	// Detail mode lists the users behind the aggregate user metrics
+	Detail    bool   `json:"detail"`
+	PIIPolicy string `json:"pii_policy"` // "none" (default), "hash", or "redact"
+
+	// EvidenceChunkSize, when positive, emits evidence of more than this
+	// many entries as separate chunk documents; see OrgPosture.EvidenceChunks
+	EvidenceChunkSize int `json:"evidence_chunk_size"`
+
+	// PrimaryEmailDomains are the org's own email domains; admins with
+	// another email domain are reported as external
+	PrimaryEmailDomains []string `json:"primary_email_domains"`
+
+	// CrownJewelApps are app IDs or labels reported individually in
+	// addition to the org-wide app percentages
+	CrownJewelApps []string `json:"crown_jewel_apps"`
+
+	// MFAGroups are group IDs or names whose MFA coverage is reported
+	// individually in mfa_by_group
+	MFAGroups []string `json:"mfa_groups"`
+
+	// SensitiveAttributes are Okta user attributes whose outbound profile
+	// mappings are reported in profile_mappings, in addition to the custom
+	// attributes the user schema marks sensitive
+	SensitiveAttributes []string `json:"sensitive_attributes"`
+
+	// Benchmark is a built-in profile, e.g. BenchmarkCIS12, whose thresholds
+	// the posture is checked against; empty turns the benchmark section off
+	Benchmark string `json:"benchmark"`
+
+	// Exemptions accept the risk of failed benchmark checks until they
+	// expire; they are echoed in the benchmark section
+	Exemptions []Exemption `json:"exemptions"`
+
+	// Definitions override how metrics classify apps, factors, and users;
+	// unset fields keep DefaultDefinitions
+	Definitions Definitions `json:"definitions"`
+
+	// ReadTimeoutSeconds is how long a response may stall mid-body before
+	// the request fails (default okta.DefaultReadTimeout)
+	ReadTimeoutSeconds int `json:"read_timeout_seconds"`
+
+	// DryRun estimates the API volume of a collection instead of running
+	// it; see Collector.Estimate
+	DryRun bool `json:"dry_run"`
+
+	// Fixture record/replay for offline development
+	FixtureMode string `json:"fixture_mode"` // "record" or "replay"
+	FixturePath string `json:"fixture_path"` // Fixture file to write or read
+
+	// Build and run identification (set by main)
+	Version   string `json:"-"` // Collector version for the User-Agent
+	Commit    string `json:"-"` // Git commit the collector was built from
+	BuildDate string `json:"-"` // When the collector was built (RFC 3339)
+	RunID     string `json:"-"` // Correlation ID; generated for each collection if empty
+
+	// IdempotencyKey, supplied by the runner, is echoed in every document
+	// of the run. When empty, a key is derived from the org and the
+	// ScheduleInterval slot (default DefaultScheduleInterval) the run
+	// started in, so a retried run repeats the key of the failed attempt.
+	IdempotencyKey   string        `json:"idempotency_key"`
+	ScheduleInterval time.Duration `json:"-"` // Parsed from schedule_interval, or the daemon interval
+
+	// Progress callbacks (optional, set by main to report status)
+	OnStatus   StatusFunc   `json:"-"`
+	OnProgress ProgressFunc `json:"-"`
+
+	// Middleware wraps every Okta API request, outermost first (optional)
+	Middleware []okta.Middleware `json:"-"`
+}
+
+// OrgPosture represents the collected security posture of an Okta organization.
+type OrgPosture struct {
+	SchemaVersion        string                 `json:"schema_version" proto:"1"`
+	CollectedAt          string                 `json:"collected_at" proto:"2"`
+	CollectionStartedAt  string                 `json:"collection_started_at" proto:"13"`  // UTC RFC 3339; the same instant as collected_at
+	CollectionFinishedAt string                 `json:"collection_finished_at" proto:"14"` // UTC RFC 3339
+	RunID                string                 `json:"run_id,omitempty" proto:"8"`
+	IdempotencyKey       string                 `json:"idempotency_key,omitempty" proto:"9"` // Same for retries of a run; see Config.IdempotencyKey
+	Collector            *CollectorBuild        `json:"collector,omitempty"`                 // Build that produced the document; omitted when Config.Version is unset
+	OrgDomain            string                 `json:"org_domain" proto:"3"`
+	Cell                 string                 `json:"cell" proto:"15"`     // commercial, preview, emea, gov, mil, or custom
+	Scope                *Scope                 `json:"scope,omitempty"`     // Set when user collection is scoped
+	Definitions          Definitions            `json:"definitions"`         // Classifications behind the metrics
+	Config               EffectiveConfig        `json:"config"`              // Settings the document was collected with, without secrets
+	Features             *OrgFeatures           `json:"features,omitempty"`  // Omitted when neither the engine nor features can be read
+	APIToken             *APITokenStatus        `json:"api_token,omitempty"` // API token authentication only
+	Posture              Posture                `json:"posture" proto:"4"`
+	Users                UserMetrics            `json:"users" proto:"5"`
+	Apps                 AppMetrics             `json:"apps" proto:"6"`
+	Policy               *PolicyConfig          `json:"policy,omitempty" proto:"7"`             // Omitted when policies can't be read
+	MFAEnrollment        *MFAEnrollment         `json:"mfa_enrollment,omitempty"`               // Omitted when enrollment policies can't be read
+	PasswordPolicy       *PasswordPolicy        `json:"password_policy,omitempty"`              // Omitted when password policies can't be read
+	CrownJewelApps       []CrownJewelApp        `json:"crown_jewel_apps,omitempty"`             // Only when crown_jewel_apps is configured
+	MFAByGroup           []GroupMFA             `json:"mfa_by_group,omitempty"`                 // Only when mfa_groups is configured
+	AdminConsole         *AdminConsolePolicy    `json:"admin_console,omitempty"`                // Omitted when the Admin Console has no authentication policy
+	Notifications        *SecurityNotifications `json:"security_notifications,omitempty"`       // Omitted when the settings can't be read
+	EndUserSettings      *EndUserSettings       `json:"end_user_settings,omitempty"`            // Omitted when the settings can't be read
+	SupportAccess        *SupportAccess         `json:"support_access,omitempty"`               // Omitted when the setting can't be read
+	Captcha              *CaptchaSettings       `json:"captcha,omitempty"`                      // Omitted when the settings can't be read
+	ThreatInsight        *ThreatInsight         `json:"threat_insight,omitempty"`               // Omitted when the setting can't be read
+	BlocklistZones       *BlocklistZones        `json:"blocklist_zones,omitempty"`              // Omitted when network zones can't be read
+	PushProtection       *PushProtection        `json:"push_protection,omitempty"`              // Omitted when authenticators can't be read
+	LogStreaming         *LogStreaming          `json:"log_streaming,omitempty"`                // Omitted when log streams can't be read
+	Automations          *Automations           `json:"automations,omitempty"`                  // Omitted when automations can't be read
+	AdminAssignments     *AdminAssignments      `json:"admin_assignments,omitempty"`            // Omitted when role assignments can't be read
+	CustomAdminRoles     *CustomAdminRoles      `json:"custom_admin_roles,omitempty"`           // Omitted when custom roles can't be read
+	GroupRules           *GroupRules            `json:"group_rules,omitempty"`                  // Omitted when group rules can't be read
+	UserSchema           *UserSchemaAudit       `json:"user_schema,omitempty"`                  // Omitted when the user schema can't be read
+	ProfileMappings      *ProfileMappings       `json:"profile_mappings,omitempty"`             // Omitted when profile mappings can't be read
+	Branding             *Branding              `json:"branding,omitempty"`                     // Omitted when brands can't be read
+	Sessions             *SessionStats          `json:"sessions,omitempty"`                     // System Log enrichment only
+	ThreatSignals        *ThreatSignals         `json:"threat_signals,omitempty"`               // System Log enrichment only
+	SignInCountries      *SignInCountries       `json:"sign_in_countries,omitempty"`            // System Log window longer than 7 days only
+	RateLimits           *RateLimits            `json:"rate_limits,omitempty"`                  // System Log enrichment only
+	Offboarding          *OffboardingMetrics    `json:"offboarding,omitempty"`                  // Omitted when unavailable or group-scoped
+	Agents               *AgentHealth           `json:"agents,omitempty"`                       // Omitted when agent pools are unreadable
+	Governance           *Governance            `json:"governance,omitempty"`                   // Omitted without Identity Governance
+	PrivilegedAccess     *PrivilegedAccess      `json:"privileged_access,omitempty"`            // Adoption counts need pam_team
+	Benchmark            *Benchmark             `json:"benchmark,omitempty"`                    // Only when a benchmark profile is configured
+	Skipped              []SkippedSection       `json:"skipped,omitempty"`                      // Sections left out because Okta denied a request
+	CollectionStats      *CollectionStats       `json:"collection_stats,omitempty"`             // API traffic and rate limit behavior of the run
+	SystemLogWindows     []LogWindow            `json:"system_log_windows,omitempty"`           // System Log enrichment only
+	Evidence             *Evidence              `json:"evidence,omitempty" proto:"11"`          // Detail mode only
+	EvidenceManifest     string                 `json:"evidence_manifest,omitempty" proto:"12"` // Artifact path of the manifest when evidence is emitted in chunks
+
+	counts   Counts    // Raw counts, emitted only in schema v2
+	entities *Entities // Per-entity records, emitted as separate documents
+}
+
+// Scope describes a collection limited to part of the org. User metrics
+// cover only the users selected here; app and policy metrics remain
+// org-wide.
+type Scope struct {
+	Groups     []string `json:"groups,omitempty"`      // Okta group IDs whose members were evaluated
+	UserFilter string   `json:"user_filter,omitempty"` // Okta search expression selecting the users evaluated; follows pii_policy
+}
+
+// Posture contains high-level security posture scores (all percentages 0-100).
+type Posture struct {
+	MFACoverage          *int `json:"mfa_coverage" schema:"percent" proto:"1"`           // % users with any MFA enrolled; null when factors can't be read
+	MFAPhishingResistant *int `json:"mfa_phishing_resistant" schema:"percent" proto:"2"` // % users with WebAuthn/FIDO2; null when factors can't be read
+	SSOCoverage          int  `json:"sso_coverage" schema:"percent" proto:"3"`           // % apps using SSO (SAML/OIDC/WS-Fed)
+	PasswordlessEnabled  bool `json:"passwordless_enabled" proto:"4"`                    // Some policy allows signing in without a password
+	PasswordlessEligible *int `json:"passwordless_eligible" schema:"percent" proto:"5"`  // % users with a passwordless-capable authenticator; null when factors can't be read
+
+	// Weak factors; null when neither authenticators nor Classic
+	// enrollment policies can be read
+	SMSFactorEnabled   *bool `json:"sms_factor_enabled" proto:"6"`
+	VoiceFactorEnabled *bool `json:"voice_factor_enabled" proto:"7"`
+	EmailFactorAsMFA   *bool `json:"email_factor_as_mfa_enabled" proto:"8"`
+	SecurityQuestion   *bool `json:"security_question_enabled" proto:"9"`
+}
+
+// UserMetrics contains user status percentages (all 0-100).
+type UserMetrics struct {
+	PasswordExpired int `json:"password_expired" schema:"percent" proto:"1"` // % users with expired passwords
+	LockedOut       int `json:"locked_out" schema:"percent" proto:"2"`       // % users currently locked out
+	Inactive        int `json:"inactive" schema:"percent" proto:"3"`         // % users inactive for 90+ days
+}
+
+// AppMetrics contains application lifecycle percentages (all 0-100).
+type AppMetrics struct {
+	ProvisioningEnabled   int            `json:"provisioning_enabled" schema:"percent" proto:"1"`           // % apps with auto-provisioning
+	DeprovisioningEnabled int            `json:"deprovisioning_enabled" schema:"percent" proto:"2"`         // % apps with auto-deprovisioning
+	ProvisioningFailing   *int           `json:"provisioning_failing,omitempty" schema:"percent" proto:"3"` // % provisioning apps with recent failures; System Log enrichment only
+	AssignedToEveryone    int            `json:"assigned_to_everyone" schema:"percent" proto:"4"`           // % apps assigned to the Everyone group
+	SignOnClasses         SignOnClasses  `json:"sign_on_classes" proto:"5"`                                 // % apps per sign-on class
+	SignOnModes           map[string]int `json:"sign_on_modes" proto:"6"`                                   // Apps per sign-on mode as reported by Okta
+	HiddenFromUsers       int            `json:"hidden_from_users" schema:"percent" proto:"7"`              // % active apps hidden on every platform
+	AutoSubmitToolbar     int            `json:"auto_submit_toolbar" schema:"percent" proto:"8"`            // % active apps with the auto-submit toolbar
+}
+
+// PolicyConfig contains aggregated policy settings across all active policies.
+type PolicyConfig struct {
+	PolicyCount               int  `json:"policy_count" proto:"1"`                         // Number of active sign-on policies
+	MFARequiredAll            bool `json:"mfa_required_all" proto:"2"`                     // All policies require MFA
+	MFARequiredAny            bool `json:"mfa_required_any" proto:"3"`                     // At least one policy requires MFA
+	SessionLifetimeMinMinutes *int `json:"session_lifetime_min_minutes" proto:"4"`         // Shortest session lifetime across policies
+	SessionLifetimeMaxMinutes *int `json:"session_lifetime_max_minutes" proto:"5"`         // Longest session lifetime across policies
+	IdleTimeoutMinMinutes     *int `json:"idle_timeout_min_minutes" proto:"6"`             // Shortest idle timeout across policies
+	IdleTimeoutMaxMinutes     *int `json:"idle_timeout_max_minutes" proto:"7"`             // Longest idle timeout across policies
+	RiskBasedRules            int  `json:"risk_based_rules" proto:"8"`                     // Active rules conditioned on risk score or behaviors
+	RiskBasedEnforced         bool `json:"risk_based_enforced" proto:"9"`                  // At least one active rule is risk-based
+	NetworkRestricted         int  `json:"network_restricted" schema:"percent" proto:"10"` // % active rules limited to network zones
+	ZoneDenyRules             int  `json:"zone_deny_rules" proto:"11"`                     // Active DENY rules for network zones
+	DenyRules                 int  `json:"deny_rules" proto:"12"`                          // Active DENY rules
+	CatchAllAllowWithoutMFA   bool `json:"catch_all_allow_without_mfa" proto:"13"`         // A sign-on policy's lowest-priority rule allows without MFA
+	MFAWorstCasePolicies      int  `json:"mfa_worst_case_policies" proto:"14"`             // Sign-on policies where every ALLOW rule requires MFA
+	MFABestCasePolicies       int  `json:"mfa_best_case_policies" proto:"15"`              // Sign-on policies where some ALLOW rule requires MFA
+}
+
+// CollectorBuild identifies the collector build that produced a document,
+// so evidence can be traced back to the exact binary.
+type CollectorBuild struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`     // Git commit SHA
+	BuildDate string `json:"build_date,omitempty"` // RFC 3339
+}
+
+// NewOrgPosture creates a new OrgPosture with the current timestamp.
+func NewOrgPosture(orgDomain string) *OrgPosture {
+	return &OrgPosture{
+		SchemaVersion: SchemaVersion,
+		CollectedAt:   time.Now().UTC().Format(time.RFC3339),
+		OrgDomain:     orgDomain,
+		Definitions:   DefaultDefinitions(),
+	}
+}
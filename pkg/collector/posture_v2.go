@@ -0,0 +1,68 @@
+package collector
+
+import "fmt"
+
+// SchemaVersionV2 is the version of the v2 output schema, which adds raw counts.
+const SchemaVersionV2 = "2.0.0"
+
+// SupportedSchemaVersions lists the output schema versions the collector can emit.
+var SupportedSchemaVersions = []string{SchemaVersion, SchemaVersionV2}
+
+// OrgPostureV2 is the v2 output document. It carries every v1 field plus raw
+// counts, so consumers can compute their own ratios and weight orgs by size.
+type OrgPostureV2 struct {
+	*OrgPosture
+	SchemaVersion string `json:"schema_version" proto:"1"` // Shadows the embedded v1 version
+	Counts        Counts `json:"counts" proto:"10"`
+}
+
+// Counts contains the raw numbers behind the v1 percentages.
+type Counts struct {
+	Users                  int  `json:"users" proto:"1"`                      // Non-deprovisioned users evaluated
+	MFAEnrolled            *int `json:"mfa_enrolled" proto:"2"`               // Users with any active factor; null when factors can't be read
+	MFAPhishingResistant   *int `json:"mfa_phishing_resistant" proto:"3"`     // Users with WebAuthn/FIDO2; null when factors can't be read
+	PasswordlessEligible   *int `json:"passwordless_eligible" proto:"12"`     // Users with FastPass or WebAuthn; null when factors can't be read
+	PasswordExpired        int  `json:"password_expired" proto:"4"`           // Users with expired passwords
+	LockedOut              int  `json:"locked_out" proto:"5"`                 // Users currently locked out
+	Inactive               int  `json:"inactive" proto:"6"`                   // Users inactive for 90+ days
+	Apps                   int  `json:"apps" proto:"7"`                       // Applications evaluated
+	SSOApps                int  `json:"sso_apps" proto:"8"`                   // Apps using SAML/OIDC/WS-Fed
+	ProvisioningApps       int  `json:"provisioning_apps" proto:"9"`          // Apps with auto-provisioning
+	DeprovisioningApps     int  `json:"deprovisioning_apps" proto:"10"`       // Apps with auto-deprovisioning
+	EveryoneApps           int  `json:"everyone_apps" proto:"13"`             // Apps assigned to the Everyone group
+	ActiveApps             int  `json:"active_apps" proto:"14"`               // Active apps, excluding Okta's own
+	HiddenApps             int  `json:"hidden_apps" proto:"15"`               // Active apps hidden on every platform
+	AutoSubmitToolbarApps  int  `json:"auto_submit_toolbar_apps" proto:"16"`  // Active apps with the auto-submit toolbar
+	MFARequiredPolicyCount *int `json:"mfa_required_policy_count" proto:"11"` // Active policies requiring MFA; null when policies can't be read
+}
+
+// ToV2 returns the v2 representation of the posture document.
+func (o *OrgPosture) ToV2() *OrgPostureV2 {
+	return &OrgPostureV2{
+		OrgPosture:    o,
+		SchemaVersion: SchemaVersionV2,
+		Counts:        o.counts,
+	}
+}
+
+// Documents returns the output documents for the requested schema versions,
+// keyed by version. An empty list selects the v1 document only, and
+// InitialSchemaVersion selects the current v1 document.
+func (o *OrgPosture) Documents(versions []string) (map[string]any, error) {
+	if len(versions) == 0 {
+		versions = []string{SchemaVersion}
+	}
+
+	docs := make(map[string]any, len(versions))
+	for _, version := range versions {
+		switch version {
+		case SchemaVersion, InitialSchemaVersion:
+			docs[SchemaVersion] = o
+		case SchemaVersionV2:
+			docs[version] = o.ToV2()
+		default:
+			return nil, fmt.Errorf("unsupported schema version %q (supported: %v)", version, SupportedSchemaVersions)
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// PrivilegedAccess reports whether Okta Privileged Access is set up and,
+// when a team is configured, how far it is adopted.
+type PrivilegedAccess struct {
+	AppConfigured  bool `json:"app_configured"`  // An active Privileged Access or Advanced Server Access app is in the org
+	ResourceGroups *int `json:"resource_groups"` // null unless pam_team is set and the team can be read
+	Projects       *int `json:"projects"`        // Projects across all resource groups
+	Servers        *int `json:"servers"`         // Servers enrolled across all projects
+}
+
+// pamClient reads an Okta Privileged Access team; see okta.PAMClient.
+type pamClient interface {
+	FetchResourceGroups(ctx context.Context) ([]okta.PAMResourceGroup, error)
+	FetchProjects(ctx context.Context, resourceGroupID string) ([]okta.PAMProject, error)
+	FetchServers(ctx context.Context, resourceGroupID, projectID string) ([]okta.PAMServer, error)
+}
+
+// isPrivilegedAccessApp reports whether an app is Okta Privileged Access or
+// its predecessor, Advanced Server Access, by the catalog's default label.
+func isPrivilegedAccessApp(app okta.Application) bool {
+	return strings.EqualFold(app.Label, AppLabelPrivilegedAccess) || strings.EqualFold(app.Label, AppLabelAdvancedServerAccess)
+}
+
+// collectPrivilegedAccess reports the Privileged Access app and, with a
+// team configured, counts its resource groups, projects, and servers. The
+// counts are null if any part of the team can't be read.
+func (c *Collector) collectPrivilegedAccess(ctx context.Context, appConfigured bool) *PrivilegedAccess {
+	result := &PrivilegedAccess{AppConfigured: appConfigured}
+	if c.pam == nil {
+		return result
+	}
+
+	groups, projects, servers, err := c.countPAMTeam(ctx)
+	if err != nil {
+		c.status(fmt.Sprintf("Warning: privileged access team unavailable: %v", err))
+		return result
+	}
+	result.ResourceGroups = &groups
+	result.Projects = &projects
+	result.Servers = &servers
+	return result
+}
+
+// countPAMTeam walks the team's resource groups and projects.
+func (c *Collector) countPAMTeam(ctx context.Context) (groups, projects, servers int, err error) {
+	resourceGroups, err := c.pam.FetchResourceGroups(ctx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, group := range resourceGroups {
+		groupProjects, err := c.pam.FetchProjects(ctx, group.ID)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		projects += len(groupProjects)
+		for _, project := range groupProjects {
+			projectServers, err := c.pam.FetchServers(ctx, group.ID, project.ID)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			servers += len(projectServers)
+		}
+	}
+	return len(resourceGroups), projects, servers, nil
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// ProfileMappings inventories attribute flow between Okta and apps. Apps
+// that Okta pushes attributes to receive personal data; apps Okta pulls
+// from are sources of truth for the profile.
+type ProfileMappings struct {
+	Mappings          int                `json:"mappings"`           // Mappings between Okta and an app, either direction
+	PushApps          int                `json:"push_apps"`          // Apps Okta sends profile attributes to
+	PullApps          int                `json:"pull_apps"`          // Apps Okta imports profile attributes from
+	SensitiveOutbound []SensitiveMapping `json:"sensitive_outbound"` // Apps receiving sensitive attributes
+}
+
+// SensitiveMapping is an app that a profile mapping sends sensitive
+// attributes to.
+type SensitiveMapping struct {
+	AppID      string   `json:"app_id"`
+	AppName    string   `json:"app_name"`
+	Attributes []string `json:"attributes"` // Okta user attributes read by the mapping's expressions
+}
+
+// userAttributePattern matches the Okta user attributes a mapping
+// expression reads, e.g. user.dateOfBirth. appuser.* doesn't match.
+var userAttributePattern = regexp.MustCompile(`\buser\.([A-Za-z0-9_]+)`)
+
+// collectProfileMappings lists the org's profile mappings and reads the
+// outbound ones for sensitive attributes: those configured in
+// sensitive_attributes and the custom attributes the user schema marks
+// sensitive. It returns nil if the mappings can't be read.
+func (c *Collector) collectProfileMappings(ctx context.Context, schema *UserSchemaAudit) *ProfileMappings {
+	mappings, err := c.client.FetchProfileMappings(ctx)
+	if err != nil {
+		c.skip("profile_mappings", ScopeProfileMappingsRead, err)
+		return nil
+	}
+
+	sensitive := make(map[string]bool)
+	for _, attribute := range c.config.SensitiveAttributes {
+		sensitive[strings.ToLower(attribute)] = true
+	}
+	if schema != nil {
+		for _, attribute := range schema.SensitiveAttributes {
+			sensitive[strings.ToLower(attribute)] = true
+		}
+	}
+
+	result := &ProfileMappings{SensitiveOutbound: []SensitiveMapping{}}
+	pushApps := make(map[string]bool)
+	pullApps := make(map[string]bool)
+	for _, mapping := range mappings {
+		switch {
+		case mapping.Source.Type == MappingTypeUser && mapping.Target.Type == MappingTypeAppUser:
+			pushApps[mapping.Target.ID] = true
+		case mapping.Source.Type == MappingTypeAppUser && mapping.Target.Type == MappingTypeUser:
+			pullApps[mapping.Source.ID] = true
+			result.Mappings++
+			continue
+		default:
+			continue
+		}
+		result.Mappings++
+		if len(sensitive) == 0 {
+			continue
+		}
+
+		detail, err := c.client.FetchProfileMapping(ctx, mapping.ID)
+		if err != nil {
+			c.skip("profile_mappings", ScopeProfileMappingsRead, err)
+			return nil
+		}
+		if attributes := sensitiveAttributes(*detail, sensitive); len(attributes) > 0 {
+			result.SensitiveOutbound = append(result.SensitiveOutbound, SensitiveMapping{
+				AppID:      mapping.Target.ID,
+				AppName:    mapping.Target.Name,
+				Attributes: attributes,
+			})
+		}
+	}
+	result.PushApps = len(pushApps)
+	result.PullApps = len(pullApps)
+
+	slices.SortFunc(result.SensitiveOutbound, func(a, b SensitiveMapping) int {
+		return strings.Compare(a.AppID, b.AppID)
+	})
+	return result
+}
+
+// sensitiveAttributes returns the sensitive Okta user attributes a
+// mapping's expressions read, sorted and without duplicates. sensitive is
+// keyed by lowercased attribute name.
+func sensitiveAttributes(mapping okta.ProfileMapping, sensitive map[string]bool) []string {
+	var attributes []string
+	for _, property := range mapping.Properties {
+		for _, match := range userAttributePattern.FindAllStringSubmatch(property.Expression, -1) {
+			if sensitive[strings.ToLower(match[1])] {
+				attributes = append(attributes, match[1])
+			}
+		}
+	}
+	slices.Sort(attributes)
+	return slices.Compact(attributes)
+}
@@ -0,0 +1,192 @@
+package collector
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// protoPackage is the package of the messages in docs/schema/okta_posture.proto.
+const protoPackage = "epack.okta.v1"
+
+// Protobuf wire types.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// ProtoDocument carries a posture document as a serialized protobuf message
+// defined in docs/schema/okta_posture.proto. The epack collector protocol
+// only carries JSON, so the message bytes are base64 encoded in a wrapper.
+type ProtoDocument struct {
+	Encoding      string `json:"encoding"`       // Always "protobuf"
+	Message       string `json:"message"`        // Fully qualified message type, e.g. epack.okta.v1.OrgPosture
+	SchemaVersion string `json:"schema_version"` // Version of the encoded document
+	Data          []byte `json:"data"`           // Serialized message; base64 in JSON
+}
+
+// EncodeProto serializes a *OrgPosture or *OrgPostureV2 as its protobuf
+// message. Only fields with a proto struct tag are part of the messages;
+// sections without one are left out.
+func EncodeProto(doc any) (*ProtoDocument, error) {
+	var message, version string
+	switch d := doc.(type) {
+	case *OrgPosture:
+		message, version = "OrgPosture", d.SchemaVersion
+	case *OrgPostureV2:
+		message, version = "OrgPostureV2", d.SchemaVersion
+	default:
+		return nil, fmt.Errorf("no protobuf message for %T", doc)
+	}
+
+	data, err := appendProtoFields(nil, reflect.ValueOf(doc).Elem(), make(map[int]bool))
+	if err != nil {
+		return nil, err
+	}
+	return &ProtoDocument{
+		Encoding:      "protobuf",
+		Message:       protoPackage + "." + message,
+		SchemaVersion: version,
+		Data:          data,
+	}, nil
+}
+
+// appendProtoFields appends the tagged fields of a struct. As with
+// encoding/json, a field of an embedded struct is shadowed by a field of
+// the outer struct with the same number.
+func appendProtoFields(b []byte, v reflect.Value, seen map[int]bool) ([]byte, error) {
+	var embedded []reflect.Value
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if field.Anonymous {
+			if inner := reflect.Indirect(v.Field(i)); inner.IsValid() {
+				embedded = append(embedded, inner)
+			}
+			continue
+		}
+		tag := field.Tag.Get("proto")
+		if tag == "" {
+			continue
+		}
+		num, err := strconv.Atoi(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: invalid proto tag %q", v.Type().Name(), field.Name, tag)
+		}
+		if seen[num] {
+			continue
+		}
+		seen[num] = true
+		if b, err = appendProtoField(b, num, v.Field(i)); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", v.Type().Name(), field.Name, err)
+		}
+	}
+	for _, inner := range embedded {
+		var err error
+		if b, err = appendProtoFields(b, inner, seen); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendProtoField appends one field. Pointers are optional fields or
+// messages and are written whenever set; other scalars are left out at
+// their zero value, as proto3 does.
+func appendProtoField(b []byte, num int, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return b, nil
+		}
+		if v.Elem().Kind() == reflect.Struct {
+			return appendProtoMessage(b, num, v.Elem())
+		}
+		return appendProtoScalar(b, num, v.Elem(), true)
+	case reflect.Struct:
+		return appendProtoMessage(b, num, v)
+	case reflect.Slice:
+		var err error
+		for i := range v.Len() {
+			if elem := v.Index(i); elem.Kind() == reflect.Struct {
+				b, err = appendProtoMessage(b, num, elem)
+			} else {
+				b, err = appendProtoScalar(b, num, elem, true)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case reflect.Map:
+		// Map entries are messages of key (1) and value (2), in key order
+		// so the encoding is deterministic
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) })
+		for _, key := range keys {
+			entry, err := appendProtoScalar(nil, 1, key, true)
+			if err != nil {
+				return nil, err
+			}
+			if entry, err = appendProtoScalar(entry, 2, v.MapIndex(key), true); err != nil {
+				return nil, err
+			}
+			b = appendProtoBytes(b, num, entry)
+		}
+		return b, nil
+	default:
+		return appendProtoScalar(b, num, v, false)
+	}
+}
+
+// appendProtoMessage appends a struct as an embedded message.
+func appendProtoMessage(b []byte, num int, v reflect.Value) ([]byte, error) {
+	msg, err := appendProtoFields(nil, v, make(map[int]bool))
+	if err != nil {
+		return nil, err
+	}
+	return appendProtoBytes(b, num, msg), nil
+}
+
+// appendProtoScalar appends a string, bool, or integer. Zero values are
+// left out unless present is set.
+func appendProtoScalar(b []byte, num int, v reflect.Value, present bool) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" && !present {
+			return b, nil
+		}
+		return appendProtoBytes(b, num, []byte(v.String())), nil
+	case reflect.Bool:
+		if !v.Bool() && !present {
+			return b, nil
+		}
+		var n uint64
+		if v.Bool() {
+			n = 1
+		}
+		return binary.AppendUvarint(appendProtoTag(b, num, wireVarint), n), nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if v.Int() == 0 && !present {
+			return b, nil
+		}
+		// Negative int32 values are sign-extended to 10 bytes
+		return binary.AppendUvarint(appendProtoTag(b, num, wireVarint), uint64(v.Int())), nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+}
+
+// appendProtoBytes appends a length-delimited field.
+func appendProtoBytes(b []byte, num int, data []byte) []byte {
+	b = appendProtoTag(b, num, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// appendProtoTag appends a field's number and wire type.
+func appendProtoTag(b []byte, num int, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(wireType))
+}
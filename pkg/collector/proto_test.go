@@ -0,0 +1,322 @@
+package collector
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// protoField is a field declared in okta_posture.proto.
+type protoField struct {
+	label string // "optional", "repeated", or empty
+	typ   string // e.g. "int32", "Posture", or "map<string, int32>"
+	name  string
+	num   int
+}
+
+// protoFieldLine matches a field declaration.
+var protoFieldLine = regexp.MustCompile(`^(optional |repeated )?(map<\w+, \w+>|\w+) (\w+) = (\d+);`)
+
+// readProtoMessages parses the messages of the published proto file.
+func readProtoMessages(t *testing.T) map[string][]protoField {
+	t.Helper()
+	data, err := os.ReadFile("../../docs/schema/okta_posture.proto")
+	if err != nil {
+		t.Fatalf("reading proto: %v", err)
+	}
+
+	messages := make(map[string][]protoField)
+	var message string
+	for line := range strings.SplitSeq(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "message "):
+			message = strings.Fields(line)[1]
+			messages[message] = nil
+		case line == "}":
+			message = ""
+		case message != "":
+			m := protoFieldLine.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			num, _ := strconv.Atoi(m[4])
+			messages[message] = append(messages[message], protoField{strings.TrimSpace(m[1]), m[2], m[3], num})
+		}
+	}
+	return messages
+}
+
+// goProtoFields returns the fields of a struct with proto tags, keyed by
+// number, with embedded fields shadowed as EncodeProto does.
+func goProtoFields(t reflect.Type) map[int]reflect.StructField {
+	fields := make(map[int]reflect.StructField)
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous {
+			embedded = append(embedded, field.Type.Elem())
+			continue
+		}
+		if num, err := strconv.Atoi(field.Tag.Get("proto")); err == nil {
+			fields[num] = field
+		}
+	}
+	for _, inner := range embedded {
+		for num, field := range goProtoFields(inner) {
+			if _, ok := fields[num]; !ok {
+				fields[num] = field
+			}
+		}
+	}
+	return fields
+}
+
+// TestProto_MatchesStructTags guards against drift between the published
+// proto file and the proto tags EncodeProto writes fields by.
+func TestProto_MatchesStructTags(t *testing.T) {
+	messages := readProtoMessages(t)
+	scalars := map[string]reflect.Kind{"string": reflect.String, "int32": reflect.Int, "bool": reflect.Bool}
+
+	var check func(message string, goType reflect.Type)
+	check = func(message string, goType reflect.Type) {
+		fields, ok := messages[message]
+		if !ok {
+			t.Errorf("%s: message not in proto", message)
+			return
+		}
+		goFields := goProtoFields(goType)
+		if len(fields) != len(goFields) {
+			t.Errorf("%s: proto has %d fields, %s has %d tagged", message, len(fields), goType.Name(), len(goFields))
+		}
+		for _, field := range fields {
+			goField, ok := goFields[field.num]
+			if !ok {
+				t.Errorf("%s.%s: no %s field tagged proto:\"%d\"", message, field.name, goType.Name(), field.num)
+				continue
+			}
+			if name, _, _ := strings.Cut(goField.Tag.Get("json"), ","); name != field.name {
+				t.Errorf("%s.%s: field %d is %s in JSON", message, field.name, field.num, name)
+			}
+
+			typ := goField.Type
+			switch {
+			case field.label == "repeated":
+				if typ.Kind() != reflect.Slice {
+					t.Errorf("%s.%s: repeated but %s is %s", message, field.name, goField.Name, typ)
+					continue
+				}
+				typ = typ.Elem()
+			case strings.HasPrefix(field.typ, "map<"):
+				if typ != reflect.TypeFor[map[string]int]() {
+					t.Errorf("%s.%s: %s but %s is %s", message, field.name, field.typ, goField.Name, typ)
+				}
+				continue
+			case typ.Kind() == reflect.Pointer:
+				typ = typ.Elem()
+				if _, scalar := scalars[field.typ]; scalar && field.label != "optional" {
+					t.Errorf("%s.%s: %s is nullable, so the field must be optional", message, field.name, goField.Name)
+				}
+			case field.label == "optional":
+				t.Errorf("%s.%s: optional but %s is not a pointer", message, field.name, goField.Name)
+			}
+
+			if kind, scalar := scalars[field.typ]; scalar {
+				if typ.Kind() != kind {
+					t.Errorf("%s.%s: %s but %s is %s", message, field.name, field.typ, goField.Name, typ)
+				}
+			} else if typ.Name() != field.typ {
+				t.Errorf("%s.%s: %s but %s is %s", message, field.name, field.typ, goField.Name, typ)
+			} else {
+				check(field.typ, typ)
+			}
+		}
+	}
+	check("OrgPosture", reflect.TypeFor[OrgPosture]())
+	check("OrgPostureV2", reflect.TypeFor[OrgPostureV2]())
+}
+
+// TestProto_MatchesGenerateSchema checks the proto messages against the
+// JSON schema: the top-level messages carry a subset of the document's
+// sections, and every nested message all of its section's properties.
+func TestProto_MatchesGenerateSchema(t *testing.T) {
+	messages := readProtoMessages(t)
+
+	var check func(path, message string, schema map[string]any, complete bool)
+	check = func(path, message string, schema map[string]any, complete bool) {
+		props, _ := schema["properties"].(map[string]any)
+		var names []string
+		for _, field := range messages[message] {
+			names = append(names, field.name)
+			prop, ok := props[field.name].(map[string]any)
+			if !ok {
+				t.Errorf("%s.%s: not in the JSON schema", path, field.name)
+				continue
+			}
+			if _, ok := messages[field.typ]; !ok {
+				continue
+			}
+			if field.label == "repeated" {
+				prop, _ = prop["items"].(map[string]any)
+			}
+			check(path+"."+field.name, field.typ, prop, true)
+		}
+		if !complete {
+			return
+		}
+		wanted := make(map[string]any, len(names))
+		for _, name := range names {
+			wanted[name] = nil
+		}
+		if keys(wanted) != keys(props) {
+			t.Errorf("%s: proto message %s has fields %s, schema properties %s", path, message, keys(wanted), keys(props))
+		}
+	}
+
+	for _, tt := range []struct{ version, message string }{
+		{SchemaVersion, "OrgPosture"},
+		{SchemaVersionV2, "OrgPostureV2"},
+	} {
+		data, err := GenerateSchema(tt.version)
+		if err != nil {
+			t.Fatalf("GenerateSchema() error: %v", err)
+		}
+		var schema map[string]any
+		if err := json.Unmarshal(data, &schema); err != nil {
+			t.Fatalf("generated schema is not valid JSON: %v", err)
+		}
+		check(tt.message, tt.message, schema, false)
+	}
+}
+
+// readProtoWire splits an encoded message into its fields, keyed by
+// number, with varints as uint64 and length-delimited fields as []byte.
+func readProtoWire(t *testing.T, data []byte) map[int][]any {
+	t.Helper()
+	fields := make(map[int][]any)
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("bad tag in %x", data)
+		}
+		data = data[n:]
+		num := int(tag >> 3)
+		switch tag & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				t.Fatalf("bad varint for field %d", num)
+			}
+			fields[num] = append(fields[num], v)
+			data = data[n:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || int(size) > len(data)-n {
+				t.Fatalf("bad length for field %d", num)
+			}
+			fields[num] = append(fields[num], data[n:n+int(size)])
+			data = data[n+int(size):]
+		default:
+			t.Fatalf("unexpected wire type %d for field %d", tag&7, num)
+		}
+	}
+	return fields
+}
+
+func TestEncodeProto(t *testing.T) {
+	posture := NewOrgPosture("test.okta.com")
+	posture.Posture.MFACoverage = intPtr(0)
+	posture.Posture.SSOCoverage = 75
+	posture.Apps.SignOnModes = map[string]int{"SAML_2_0": 3, "BOOKMARK": 1}
+	posture.Evidence = &Evidence{
+		UsersWithoutMFA: []UserRef{{ID: "00u1"}, {ID: "00u2", Login: "bob@example.com"}},
+		AdminGroups:     []AdminGroup{{ID: "00g1", Roles: []string{"SUPER_ADMIN", "ORG_ADMIN"}}},
+	}
+	posture.counts = Counts{Users: 2}
+
+	doc, err := EncodeProto(posture)
+	if err != nil {
+		t.Fatalf("EncodeProto() error: %v", err)
+	}
+	if doc.Encoding != "protobuf" || doc.Message != "epack.okta.v1.OrgPosture" || doc.SchemaVersion != SchemaVersion {
+		t.Errorf("unexpected wrapper %+v", doc)
+	}
+
+	fields := readProtoWire(t, doc.Data)
+	if string(fields[3][0].([]byte)) != "test.okta.com" {
+		t.Errorf("expected org_domain, got %q", fields[3])
+	}
+	if _, ok := fields[10]; ok {
+		t.Error("v1 message should not carry counts")
+	}
+	if _, ok := fields[7]; ok {
+		t.Error("expected an unset policy to be left out")
+	}
+
+	// A zero optional is written, so it reads back as 0 rather than null
+	posture2 := readProtoWire(t, fields[4][0].([]byte))
+	if posture2[1][0] != uint64(0) || posture2[3][0] != uint64(75) {
+		t.Errorf("unexpected posture fields %v", posture2)
+	}
+	if _, ok := posture2[2]; ok {
+		t.Error("expected a null mfa_phishing_resistant to be left out")
+	}
+
+	// Map entries in key order
+	apps := readProtoWire(t, fields[6][0].([]byte))
+	var modes []string
+	for _, entry := range apps[6] {
+		e := readProtoWire(t, entry.([]byte))
+		modes = append(modes, string(e[1][0].([]byte))+"="+strconv.FormatUint(e[2][0].(uint64), 10))
+	}
+	if strings.Join(modes, ",") != "BOOKMARK=1,SAML_2_0=3" {
+		t.Errorf("unexpected sign_on_modes %v", modes)
+	}
+
+	evidence := readProtoWire(t, fields[11][0].([]byte))
+	if len(evidence[1]) != 2 {
+		t.Fatalf("expected 2 users without MFA, got %d", len(evidence[1]))
+	}
+	if user := readProtoWire(t, evidence[1][1].([]byte)); string(user[2][0].([]byte)) != "bob@example.com" {
+		t.Errorf("unexpected user %v", user)
+	}
+	if group := readProtoWire(t, evidence[5][0].([]byte)); len(group[3]) != 2 {
+		t.Errorf("expected 2 roles, got %v", group)
+	}
+
+	// v2 shadows the v1 schema_version and adds counts
+	doc, err = EncodeProto(posture.ToV2())
+	if err != nil {
+		t.Fatalf("EncodeProto() error: %v", err)
+	}
+	fields = readProtoWire(t, doc.Data)
+	if len(fields[1]) != 1 || string(fields[1][0].([]byte)) != SchemaVersionV2 {
+		t.Errorf("expected one schema_version %s, got %q", SchemaVersionV2, fields[1])
+	}
+	if counts := readProtoWire(t, fields[10][0].([]byte)); counts[1][0] != uint64(2) {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	// The wrapper is JSON with the message base64 encoded
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wrapper map[string]any
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(wrapper["data"].(string)); err != nil || string(decoded) != string(doc.Data) {
+		t.Errorf("expected base64 data, got %v", wrapper["data"])
+	}
+
+	if _, err := EncodeProto(&Comparison{}); err == nil {
+		t.Error("expected an error for a document without a message")
+	}
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// collectProvisioningFailures returns the IDs of apps with failed
+// provisioning events in the System Log over the failure window.
+func (c *Collector) collectProvisioningFailures(ctx context.Context) (map[string]bool, error) {
+	since, until := c.logWindow("apps", min(c.config.SystemLogLookbackDays, ProvisioningFailureWindowDays))
+	filter := fmt.Sprintf("eventType sw %q and outcome.result eq %q", EventTypePrefixProvision, OutcomeFailure)
+
+	failing := make(map[string]bool)
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		for _, target := range event.Target {
+			if target.Type == LogTargetAppInstance {
+				failing[target.ID] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return failing, nil
+}
+
+// appProvisioning records which provisioning features an app has enabled.
+type appProvisioning struct {
+	provisioning   bool
+	deprovisioning bool
+}
+
+// applyProvisioningFailures stops counting apps with failing provisioning
+// toward provisioning and deprovisioning coverage, and records the share of
+// provisioning apps that are failing.
+func (metrics *appMetricsCollector) applyProvisioningFailures(failing map[string]bool) {
+	failingApps := 0
+	for id, features := range metrics.provisioningApps {
+		if !failing[id] {
+			continue
+		}
+		failingApps++
+		if features.provisioning {
+			metrics.provisioningCount--
+		}
+		if features.deprovisioning {
+			metrics.deprovisioningCount--
+		}
+	}
+	rate := percent(failingApps, len(metrics.provisioningApps))
+	metrics.provisioningFailing = &rate
+}
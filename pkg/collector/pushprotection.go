@@ -0,0 +1,32 @@
+package collector
+
+import "github.com/locktivity/epack-collector-okta/pkg/okta"
+
+// PushProtection reports the Okta Verify mitigations against push fatigue
+// (MFA bombing), where an attacker with a stolen password sends push
+// requests until the user approves one.
+type PushProtection struct {
+	OktaVerifyActive         bool   `json:"okta_verify_active"`         // Okta Verify is an active authenticator
+	NumberChallenge          string `json:"number_challenge"`           // ALWAYS, HIGH_RISK_ONLY or NEVER
+	NumberChallengeEnforced  bool   `json:"number_challenge_enforced"`  // Every push requires number matching
+	UserVerificationRequired bool   `json:"user_verification_required"` // Approving requires biometrics or a PIN
+}
+
+// collectPushProtection reads the Okta Verify authenticator settings.
+func collectPushProtection(authenticators []okta.Authenticator) *PushProtection {
+	result := &PushProtection{NumberChallenge: NumberChallengeNever}
+	for _, authenticator := range authenticators {
+		if authenticator.Key != AuthenticatorOktaVerify {
+			continue
+		}
+		result.OktaVerifyActive = authenticator.Status == StatusActive
+		settings := authenticator.Settings
+		if binding := settings.ChannelBinding; binding != nil && binding.Required != "" {
+			result.NumberChallenge = binding.Required
+		}
+		result.UserVerificationRequired = settings.UserVerification == ConstraintRequired
+		break
+	}
+	result.NumberChallengeEnforced = result.OktaVerifyActive && result.NumberChallenge == NumberChallengeAlways
+	return result
+}
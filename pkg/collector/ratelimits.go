@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// RateLimits counts the org's API rate limit events. Integrations that hit
+// rate limits, this collector among them, slow down or starve every other
+// client sharing the bucket.
+type RateLimits struct {
+	WindowDays int            `json:"window_days"` // System Log window read
+	Warnings   int            `json:"warnings"`    // A bucket neared its limit
+	Violations int            `json:"violations"`  // A bucket exceeded its limit and requests were rejected
+	Bursts     int            `json:"bursts"`      // A bucket exceeded its limit within the burst allowance
+	Buckets    map[string]int `json:"buckets"`     // Events by rate limit bucket, e.g. "/api/v1/users"
+}
+
+// collectRateLimits reads system.org.rate_limit.* events over the System
+// Log lookback window. Events are attributed to the bucket named by their
+// first target.
+func (c *Collector) collectRateLimits(ctx context.Context) (*RateLimits, error) {
+	days := min(c.config.SystemLogLookbackDays, MaxSystemLogLookbackDays)
+	since, until := c.logWindow("rate_limits", days)
+	filter := fmt.Sprintf("eventType sw %q", EventTypePrefixRateLimit)
+
+	result := &RateLimits{WindowDays: days, Buckets: make(map[string]int)}
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		switch event.EventType {
+		case EventTypeRateLimitWarning:
+			result.Warnings++
+		case EventTypeRateLimitViolation:
+			result.Violations++
+		case EventTypeRateLimitBurst:
+			result.Bursts++
+		default:
+			return nil
+		}
+		result.Buckets[rateLimitBucket(event)]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// rateLimitBucket names the bucket of a rate limit event.
+func rateLimitBucket(event okta.LogEvent) string {
+	if len(event.Target) > 0 {
+		target := event.Target[0]
+		if target.AlternateID != "" {
+			return target.AlternateID
+		}
+		if target.DisplayName != "" {
+			return target.DisplayName
+		}
+	}
+	return "unknown"
+}
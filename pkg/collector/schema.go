@@ -0,0 +1,149 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// percentMaximum is applied to integer fields documented as percentages.
+const percentMaximum = MaxPercentage
+
+// GenerateSchema returns a JSON Schema for the output document of the given
+// schema version. The schema is derived from the struct definitions via
+// reflection so it cannot drift from what the collector actually emits.
+func GenerateSchema(version string) ([]byte, error) {
+	var t reflect.Type
+	switch version {
+	case SchemaVersion:
+		t = reflect.TypeOf(OrgPosture{})
+	case SchemaVersionV2:
+		t = reflect.TypeOf(OrgPostureV2{})
+	default:
+		return nil, fmt.Errorf("unsupported schema version %q (supported: %v)", version, SupportedSchemaVersions)
+	}
+
+	schema := schemaForType(t)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = "https://github.com/locktivity/epack-collector-okta/docs/schema/v" + version + ".json"
+	schema["title"] = "Okta Organization Security Posture"
+	schema["description"] = "Security posture metrics collected from an Okta organization"
+	if props, ok := schema["properties"].(map[string]any); ok {
+		if sv, ok := props["schema_version"].(map[string]any); ok {
+			sv["const"] = version
+		}
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaForType builds a JSON Schema fragment for a Go type.
+func schemaForType(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := schemaForType(t.Elem())
+		if typ, ok := inner["type"].(string); ok {
+			inner["type"] = []string{typ, "null"}
+		}
+		return inner
+	case reflect.Struct:
+		return schemaForStruct(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaForType(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaForType(t.Elem()),
+		}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
+
+// schemaForStruct builds an object schema from a struct's JSON tags.
+// Fields without omitempty are required. Untagged embedded structs are
+// flattened, with the outer struct's fields taking precedence.
+func schemaForStruct(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous || field.Tag.Get("json") != "" {
+			continue
+		}
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if embedded.Kind() != reflect.Struct {
+			continue
+		}
+		inner := schemaForStruct(embedded)
+		for name, prop := range inner["properties"].(map[string]any) {
+			properties[name] = prop
+		}
+		required = append(required, inner["required"].([]string)...)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || (field.Anonymous && field.Tag.Get("json") == "") {
+			continue
+		}
+
+		name, omitEmpty, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+
+		prop := schemaForType(field.Type)
+		if field.Tag.Get("schema") == "percent" {
+			prop["minimum"] = 0
+			prop["maximum"] = percentMaximum
+		}
+		_, seen := properties[name]
+		properties[name] = prop
+
+		if !omitEmpty && !seen {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"required":   required,
+		"properties": properties,
+	}
+}
+
+// parseJSONTag returns the JSON name of a field and whether it is omitempty or skipped.
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
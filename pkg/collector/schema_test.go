@@ -0,0 +1,165 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	data, err := GenerateSchema(SchemaVersion)
+	if err != nil {
+		t.Fatalf("GenerateSchema() error: %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("generated schema is not valid JSON: %v", err)
+	}
+
+	props := schema["properties"].(map[string]any)
+	posture := props["posture"].(map[string]any)["properties"].(map[string]any)
+	sso := posture["sso_coverage"].(map[string]any)
+	if sso["type"] != "integer" || sso["maximum"] != float64(100) {
+		t.Errorf("expected sso_coverage integer with maximum 100, got %v", sso)
+	}
+	mfa := posture["mfa_coverage"].(map[string]any)
+	if types, ok := mfa["type"].([]any); !ok || len(types) != 2 || types[1] != "null" || mfa["maximum"] != float64(100) {
+		t.Errorf("expected nullable mfa_coverage with maximum 100, got %v", mfa)
+	}
+
+	policy := props["policy"].(map[string]any)["properties"].(map[string]any)
+	lifetime := policy["session_lifetime_min_minutes"].(map[string]any)
+	types, ok := lifetime["type"].([]any)
+	if !ok || len(types) != 2 || types[1] != "null" {
+		t.Errorf("expected nullable integer for session_lifetime_min_minutes, got %v", lifetime["type"])
+	}
+}
+
+// TestGenerateSchema_MatchesPublishedSchema guards against drift between the
+// Go structs and the hand-documented schema in docs/schema.
+func TestGenerateSchema_MatchesPublishedSchema(t *testing.T) {
+	for _, version := range SupportedSchemaVersions {
+		t.Run(version, func(t *testing.T) {
+			published, err := os.ReadFile("../../docs/schema/v" + version + ".json")
+			if err != nil {
+				t.Fatalf("reading published schema: %v", err)
+			}
+			generated, err := GenerateSchema(version)
+			if err != nil {
+				t.Fatalf("GenerateSchema() error: %v", err)
+			}
+
+			var want, got map[string]any
+			if err := json.Unmarshal(published, &want); err != nil {
+				t.Fatalf("published schema is not valid JSON: %v", err)
+			}
+			if err := json.Unmarshal(generated, &got); err != nil {
+				t.Fatalf("generated schema is not valid JSON: %v", err)
+			}
+
+			compareSchemaProperties(t, "", want, got)
+		})
+	}
+}
+
+// TestPublishedSchema_V1MinorIsAdditive checks that the current v1 schema
+// keeps every 1.0.0 field with a compatible type.
+func TestPublishedSchema_V1MinorIsAdditive(t *testing.T) {
+	read := func(version string) map[string]any {
+		t.Helper()
+		data, err := os.ReadFile("../../docs/schema/v" + version + ".json")
+		if err != nil {
+			t.Fatalf("reading published schema: %v", err)
+		}
+		var schema map[string]any
+		if err := json.Unmarshal(data, &schema); err != nil {
+			t.Fatalf("published schema %s is not valid JSON: %v", version, err)
+		}
+		return schema
+	}
+	compareSchemaAdditive(t, "", read(InitialSchemaVersion), read(SchemaVersion))
+}
+
+// compareSchemaAdditive reports properties of old missing from new, or
+// whose type changed other than by becoming nullable.
+func compareSchemaAdditive(t *testing.T, path string, old, new map[string]any) {
+	t.Helper()
+
+	oldProps, _ := old["properties"].(map[string]any)
+	newProps, _ := new["properties"].(map[string]any)
+	for name, o := range oldProps {
+		om, _ := o.(map[string]any)
+		nm, ok := newProps[name].(map[string]any)
+		if !ok {
+			t.Errorf("%s.%s: removed", path, name)
+			continue
+		}
+		if !reflect.DeepEqual(om["type"], nm["type"]) && !reflect.DeepEqual([]any{om["type"], "null"}, nm["type"]) {
+			t.Errorf("%s.%s: type changed from %v to %v", path, name, om["type"], nm["type"])
+		}
+		if om["type"] == "object" {
+			compareSchemaAdditive(t, path+"."+name, om, nm)
+		}
+	}
+}
+
+func TestGenerateSchema_V2(t *testing.T) {
+	data, err := GenerateSchema(SchemaVersionV2)
+	if err != nil {
+		t.Fatalf("GenerateSchema() error: %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("generated schema is not valid JSON: %v", err)
+	}
+
+	props := schema["properties"].(map[string]any)
+	if props["schema_version"].(map[string]any)["const"] != SchemaVersionV2 {
+		t.Errorf("expected schema_version const %s, got %v", SchemaVersionV2, props["schema_version"])
+	}
+	for _, field := range []string{"posture", "users", "apps", "policy", "counts"} {
+		if _, ok := props[field]; !ok {
+			t.Errorf("v2 schema missing property %q", field)
+		}
+	}
+
+	if _, err := GenerateSchema("9.9.9"); err == nil {
+		t.Error("expected error for unsupported schema version")
+	}
+}
+
+// compareSchemaProperties recursively compares property names of two schemas.
+func compareSchemaProperties(t *testing.T, path string, want, got map[string]any) {
+	t.Helper()
+
+	wantProps, _ := want["properties"].(map[string]any)
+	gotProps, _ := got["properties"].(map[string]any)
+
+	if keys(wantProps) != keys(gotProps) {
+		t.Errorf("%s: published properties %s, generated %s", path, keys(wantProps), keys(gotProps))
+		return
+	}
+
+	for name, w := range wantProps {
+		wm, _ := w.(map[string]any)
+		gm, _ := gotProps[name].(map[string]any)
+		if wm["type"] == "object" {
+			compareSchemaProperties(t, path+"."+name, wm, gm)
+		}
+	}
+}
+
+// keys returns the sorted keys of a map as a JSON array string.
+func keys(m map[string]any) string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	data, _ := json.Marshal(names)
+	return string(data)
+}
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// sessionEventTypes are the System Log events that start or end an Okta
+// session.
+var sessionEventTypes = []string{EventTypeSessionStart, EventTypeSessionEnd, EventTypeSessionClear}
+
+// SessionStats estimates current Okta sessions from System Log session
+// events. Sessions that expire or time out are not logged, so open
+// sessions include some that have silently ended.
+type SessionStats struct {
+	WindowDays           int  `json:"window_days"`             // System Log window read
+	Started              int  `json:"started"`                 // Sessions started in the window
+	Open                 int  `json:"open"`                    // Started sessions with no logged end
+	OlderThanMaxLifetime *int `json:"older_than_max_lifetime"` // Open sessions older than the longest policy lifetime; null if no policy sets one
+}
+
+// collectSessionStats reads session events over the System Log lookback
+// window and compares open sessions against maxLifetimeMinutes, the longest
+// session lifetime any sign-on policy allows.
+func (c *Collector) collectSessionStats(ctx context.Context, maxLifetimeMinutes *int) (*SessionStats, error) {
+	days := min(c.config.SystemLogLookbackDays, MaxSystemLogLookbackDays)
+	since, until := c.logWindow("sessions", days)
+
+	clauses := make([]string, len(sessionEventTypes))
+	for i, eventType := range sessionEventTypes {
+		clauses[i] = fmt.Sprintf("eventType eq %q", eventType)
+	}
+	filter := strings.Join(clauses, " or ")
+
+	started := make(map[string]time.Time) // Session ID -> start
+	ended := make(map[string]bool)
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		sessionID := event.AuthenticationContext.ExternalSessionID
+		if sessionID == "" {
+			return nil
+		}
+		switch event.EventType {
+		case EventTypeSessionStart:
+			if event.Outcome != nil && event.Outcome.Result == OutcomeSuccess {
+				started[sessionID] = event.Published
+			}
+		case EventTypeSessionEnd, EventTypeSessionClear:
+			ended[sessionID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SessionStats{WindowDays: days, Started: len(started)}
+	older := 0
+	for sessionID, start := range started {
+		if ended[sessionID] {
+			continue
+		}
+		stats.Open++
+		if maxLifetimeMinutes != nil && until.Sub(start) > time.Duration(*maxLifetimeMinutes)*time.Minute {
+			older++
+		}
+	}
+	if maxLifetimeMinutes != nil {
+		stats.OlderThanMaxLifetime = &older
+	}
+	return stats, nil
+}
@@ -0,0 +1,44 @@
+package collector
+
+// SignOnClasses breaks the apps down by how users sign in to them, as
+// percentages of all apps. Every app falls into exactly one class, so the
+// apps behind a low SSO coverage can be prioritized.
+type SignOnClasses struct {
+	SSO             int `json:"sso" schema:"percent" proto:"1"`              // Federated, per definitions.sso_sign_on_modes
+	AutoLogin       int `json:"auto_login" schema:"percent" proto:"2"`       // Custom SWA apps: Okta posts stored credentials to a login form
+	PasswordVaulted int `json:"password_vaulted" schema:"percent" proto:"3"` // Template SWA apps: the browser plugin or Okta fills in stored credentials
+	BasicAuth       int `json:"basic_auth" schema:"percent" proto:"4"`       // Stored credentials sent as HTTP Basic authentication
+	Bookmark        int `json:"bookmark" schema:"percent" proto:"5"`         // Links only; Okta does not sign users in
+	Other           int `json:"other" schema:"percent" proto:"6"`            // Any other sign-on mode
+}
+
+// signOnClasses breaks down total apps by the class of their sign-on
+// modes. A mode listed in SSOSignOnModes counts as SSO even if it would
+// otherwise fall into another class.
+func (d Definitions) signOnClasses(modes map[string]int, total int) SignOnClasses {
+	var sso, autoLogin, passwordVaulted, basicAuth, bookmark, other int
+	for mode, n := range modes {
+		switch {
+		case d.isSSO(mode):
+			sso += n
+		case mode == SignOnModeAutoLogin:
+			autoLogin += n
+		case mode == SignOnModeBrowserPlugin, mode == SignOnModeSecurePasswordStore:
+			passwordVaulted += n
+		case mode == SignOnModeBasicAuth:
+			basicAuth += n
+		case mode == SignOnModeBookmark:
+			bookmark += n
+		default:
+			other += n
+		}
+	}
+	return SignOnClasses{
+		SSO:             percent(sso, total),
+		AutoLogin:       percent(autoLogin, total),
+		PasswordVaulted: percent(passwordVaulted, total),
+		BasicAuth:       percent(basicAuth, total),
+		Bookmark:        percent(bookmark, total),
+		Other:           percent(other, total),
+	}
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"context"
+	"flag"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/locktivity/epack-collector-okta/internal/oktasim"
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// Simulator flags. Defaults keep the test fast; scale up for load testing:
+//
+//	go test ./pkg/collector -run TestSimulatedOrg -v -args -sim.users=100000 -sim.latency=5ms -sim.ratelimit-every=500
+var (
+	simUsers          = flag.Int("sim.users", 2000, "synthetic org user count")
+	simApps           = flag.Int("sim.apps", 250, "synthetic org app count")
+	simPolicies       = flag.Int("sim.policies", 5, "synthetic org sign-on policy count")
+	simMFAPercent     = flag.Int("sim.mfa-percent", 80, "percentage of synthetic users with MFA")
+	simLatency        = flag.Duration("sim.latency", 0, "latency added to every simulated response")
+	simRateLimitEvery = flag.Int("sim.ratelimit-every", 0, "inject a 429 on every Nth request (0 disables)")
+)
+
+func TestSimulatedOrg(t *testing.T) {
+	sim := oktasim.New(oktasim.Options{
+		Users:          *simUsers,
+		Apps:           *simApps,
+		Policies:       *simPolicies,
+		MFAPercent:     *simMFAPercent,
+		Latency:        *simLatency,
+		RateLimitEvery: *simRateLimitEvery,
+	})
+	server := httptest.NewServer(sim)
+	defer server.Close()
+
+	client := okta.NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("sim-token")
+
+	var before runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	c := NewWithClient(Config{OrgDomain: "sim.okta.com"}, client)
+	posture, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("collection against simulator failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	if posture.counts.Users != sim.ExpectedUsers() {
+		t.Errorf("expected %d users, got %d", sim.ExpectedUsers(), posture.counts.Users)
+	}
+	if posture.counts.Apps != *simApps {
+		t.Errorf("expected %d apps, got %d", *simApps, posture.counts.Apps)
+	}
+	if posture.Policy == nil || posture.Policy.PolicyCount != *simPolicies {
+		t.Errorf("expected %d policies, got %+v", *simPolicies, posture.Policy)
+	}
+
+	t.Logf("users=%d apps=%d requests=%d rate_limited=%d duration=%s total_alloc=%dMiB",
+		*simUsers, *simApps, sim.Requests(), sim.RateLimited(), elapsed,
+		(after.TotalAlloc-before.TotalAlloc)>>20)
+}
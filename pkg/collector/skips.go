@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// SkippedSection records an optional section left out because Okta denied
+// a request it needs, so consumers can tell missing permissions apart from
+// an org with nothing to report.
+type SkippedSection struct {
+	Section   string `json:"section"`    // JSON name of the section, e.g. "captcha"
+	Scope     string `json:"scope"`      // OAuth scope the section needs
+	ErrorCode string `json:"error_code"` // Okta error code, e.g. "E0000006"; empty if Okta sent none
+	Endpoint  string `json:"endpoint"`   // Logical endpoint that was denied, e.g. "captchas"
+}
+
+// skip records that a section was left out because Okta denied a request
+// it needs, and reports whether it was a denial. Errors other than a 403
+// are not recorded, and only the first denial of each section is kept.
+func (c *Collector) skip(section, scope string, err error) bool {
+	var apiErr *okta.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	c.skipMu.Lock()
+	defer c.skipMu.Unlock()
+	if slices.ContainsFunc(c.skips, func(s SkippedSection) bool { return s.Section == section }) {
+		return true
+	}
+	c.skips = append(c.skips, SkippedSection{
+		Section:   section,
+		Scope:     scope,
+		ErrorCode: apiErr.ErrorCode,
+		Endpoint:  apiErr.Endpoint,
+	})
+	return true
+}
+
+// takeSkips returns the sections skipped since the last call, sorted by
+// section, and clears them for the next collection.
+func (c *Collector) takeSkips() []SkippedSection {
+	c.skipMu.Lock()
+	defer c.skipMu.Unlock()
+	skips := c.skips
+	c.skips = nil
+	slices.SortFunc(skips, func(a, b SkippedSection) int { return cmp.Compare(a.Section, b.Section) })
+	return skips
+}
@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"context"
+	"time"
+)
+
+// SupportAccess reports standing Okta Support access to the org.
+type SupportAccess struct {
+	Enabled                bool    `json:"enabled"`                  // Okta Support can access the org
+	ExpiresAt              *string `json:"expires_at"`               // When support access ends; null when disabled
+	HoursRemaining         *int    `json:"hours_remaining"`          // Hours until support access ends; null when disabled
+	ImpersonationCases     *int    `json:"impersonation_cases"`      // Support cases with impersonation enabled; null if cases can't be read
+	ImpersonationExpiresAt *string `json:"impersonation_expires_at"` // Latest impersonation expiry; null without grants
+}
+
+// collectSupportAccess fetches the Okta Support access settings. It returns
+// nil if they can't be read.
+func (c *Collector) collectSupportAccess(ctx context.Context) *SupportAccess {
+	settings, err := c.client.FetchOktaSupportSettings(ctx)
+	if err != nil || settings == nil {
+		c.skip("support_access", ScopeOrgsRead, err)
+		return nil
+	}
+
+	now := time.Now()
+	result := &SupportAccess{Enabled: settings.Support == SettingEnabled}
+	if result.Enabled && settings.Expiration != nil {
+		expires := settings.Expiration.UTC().Format(time.RFC3339)
+		hours := max(0, int(settings.Expiration.Sub(now).Hours()))
+		result.ExpiresAt = &expires
+		result.HoursRemaining = &hours
+	}
+
+	// Best-effort: impersonation grants are per support case
+	cases, err := c.client.FetchOktaSupportCases(ctx)
+	if err != nil {
+		return result
+	}
+	granted := 0
+	var latest *time.Time
+	for _, supportCase := range cases {
+		grant := supportCase.Impersonation
+		if grant.Status != SettingEnabled || (grant.Expiration != nil && grant.Expiration.Before(now)) {
+			continue
+		}
+		granted++
+		if grant.Expiration != nil && (latest == nil || grant.Expiration.After(*latest)) {
+			latest = grant.Expiration
+		}
+	}
+	result.ImpersonationCases = &granted
+	if latest != nil {
+		expires := latest.UTC().Format(time.RFC3339)
+		result.ImpersonationExpiresAt = &expires
+	}
+	return result
+}
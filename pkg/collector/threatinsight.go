@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// ThreatInsight reports whether Okta ThreatInsight acts on sign-in attempts
+// from suspicious IPs, and whether its exempt zones hollow that out.
+type ThreatInsight struct {
+	Action          string           `json:"action"`           // none, audit, or block
+	ExemptZones     int              `json:"exempt_zones"`     // Active network zones exempt from ThreatInsight
+	BroadExemptions []BroadExemption `json:"broad_exemptions"` // Exempt zone entries at least as broad as an IPv4 /8 or IPv6 /32
+	BlockBypassed   bool             `json:"block_bypassed"`   // Action is block, but a broad exemption lets most of the internet past it
+}
+
+// BroadExemption is an exempt zone entry covering a broad address range.
+type BroadExemption struct {
+	ZoneID   string `json:"zone_id"`
+	ZoneName string `json:"zone_name"`
+	Address  string `json:"address"` // CIDR or range, e.g. "0.0.0.0/0"
+}
+
+// collectThreatInsight fetches the ThreatInsight setting and its exempt
+// zones. Zones that no longer exist are skipped. It returns nil if the
+// setting or an exempt zone can't be read.
+func (c *Collector) collectThreatInsight(ctx context.Context) *ThreatInsight {
+	config, err := c.client.FetchThreatInsight(ctx)
+	if err != nil {
+		c.skip("threat_insight", ScopeThreatInsightsRead, err)
+		return nil
+	}
+
+	result := &ThreatInsight{Action: config.Action, BroadExemptions: []BroadExemption{}}
+	for _, zoneID := range config.ExcludeZones {
+		zone, err := c.client.FetchNetworkZone(ctx, zoneID)
+		var apiErr *okta.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			c.skip("threat_insight", ScopeNetworkZonesRead, err)
+			return nil
+		}
+		if zone.Status != StatusActive {
+			continue
+		}
+		result.ExemptZones++
+		for _, gateway := range zone.Gateways {
+			if isBroadZoneAddress(gateway) {
+				result.BroadExemptions = append(result.BroadExemptions, BroadExemption{
+					ZoneID:   zone.ID,
+					ZoneName: zone.Name,
+					Address:  gateway.Value,
+				})
+			}
+		}
+	}
+	result.BlockBypassed = strings.EqualFold(result.Action, ThreatInsightBlock) && len(result.BroadExemptions) > 0
+	return result
+}
+
+// isBroadZoneAddress reports whether a zone CIDR or range covers at least
+// as many addresses as an IPv4 /8 or an IPv6 /32. Unparseable entries are
+// not broad.
+func isBroadZoneAddress(address okta.ZoneAddress) bool {
+	switch address.Type {
+	case ZoneAddressCIDR:
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(address.Value))
+		if err != nil {
+			return false
+		}
+		return prefix.Bits() <= broadPrefixBits(prefix.Addr())
+	case ZoneAddressRange:
+		first, last, ok := strings.Cut(address.Value, "-")
+		if !ok {
+			return false
+		}
+		start, err := netip.ParseAddr(strings.TrimSpace(first))
+		if err != nil {
+			return false
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(last))
+		if err != nil || start.Is4() != end.Is4() || end.Less(start) {
+			return false
+		}
+		size := new(big.Int).Sub(new(big.Int).SetBytes(end.AsSlice()), new(big.Int).SetBytes(start.AsSlice()))
+		size.Add(size, big.NewInt(1))
+		broad := new(big.Int).Lsh(big.NewInt(1), uint(start.BitLen()-broadPrefixBits(start)))
+		return size.Cmp(broad) >= 0
+	}
+	return false
+}
+
+// broadPrefixBits returns the prefix length at or below which an address
+// block of addr's family counts as broad.
+func broadPrefixBits(addr netip.Addr) int {
+	if addr.Is4() {
+		return BroadIPv4PrefixBits
+	}
+	return BroadIPv6PrefixBits
+}
@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// ThreatSignals counts System Log events that lead credential attacks:
+// failed MFA challenges, rejected pushes, and lockouts rise during
+// password spraying and MFA fatigue campaigns, before any account is
+// taken over.
+type ThreatSignals struct {
+	WindowDays      int `json:"window_days"`      // System Log window read
+	MFADenials      int `json:"mfa_denials"`      // Failed MFA verifications
+	PushRejections  int `json:"push_rejections"`  // Okta Verify pushes the user rejected
+	AccountLockouts int `json:"account_lockouts"` // Accounts locked after failed sign-ins
+	TargetedUsers   int `json:"targeted_users"`   // Distinct users with any of these events
+}
+
+// collectThreatSignals reads MFA failure, push rejection, and lockout
+// events over the System Log lookback window.
+func (c *Collector) collectThreatSignals(ctx context.Context) (*ThreatSignals, error) {
+	days := min(c.config.SystemLogLookbackDays, MaxSystemLogLookbackDays)
+	since, until := c.logWindow("threat_signals", days)
+
+	filter := fmt.Sprintf("(eventType eq %q and outcome.result eq %q) or eventType eq %q or eventType eq %q",
+		EventTypeAuthViaMFA, OutcomeFailure, EventTypePushDenied, EventTypeAccountLock)
+
+	signals := &ThreatSignals{WindowDays: days}
+	targeted := make(map[string]bool)
+	err := c.client.FetchLogEvents(ctx, since, until, filter, func(event okta.LogEvent) error {
+		switch event.EventType {
+		case EventTypeAuthViaMFA:
+			if event.Outcome == nil || event.Outcome.Result != OutcomeFailure {
+				return nil
+			}
+			signals.MFADenials++
+		case EventTypePushDenied:
+			signals.PushRejections++
+		case EventTypeAccountLock:
+			signals.AccountLockouts++
+		default:
+			return nil
+		}
+		if event.Actor.Type == LogActorUser && event.Actor.ID != "" {
+			targeted[event.Actor.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signals.TargetedUsers = len(targeted)
+	return signals, nil
+}
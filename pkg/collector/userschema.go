@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"context"
+	"slices"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// UserSchemaAudit reviews the custom attributes of the default user
+// profile. Every custom attribute is more personal data held in Okta and
+// sent to the apps it is mapped to; one users can edit themselves is data
+// no one has verified.
+type UserSchemaAudit struct {
+	CustomAttributes       int      `json:"custom_attributes"`        // Attributes the org added to the profile
+	SensitiveAttributes    []string `json:"sensitive_attributes"`     // Custom attributes marked sensitive, by variable name
+	SelfEditableAttributes []string `json:"self_editable_attributes"` // Custom attributes users can change on their own profile
+}
+
+// collectUserSchema fetches the default user schema. It returns nil if the
+// schema can't be read, for example without the okta.schemas.read scope.
+func (c *Collector) collectUserSchema(ctx context.Context) *UserSchemaAudit {
+	schema, err := c.client.FetchUserSchema(ctx)
+	if err != nil {
+		c.skip("user_schema", ScopeSchemasRead, err)
+		return nil
+	}
+
+	custom := schema.Definitions.Custom.Properties
+	result := &UserSchemaAudit{
+		CustomAttributes:       len(custom),
+		SensitiveAttributes:    []string{},
+		SelfEditableAttributes: []string{},
+	}
+	for name, attribute := range custom {
+		if attribute.Sensitive {
+			result.SensitiveAttributes = append(result.SensitiveAttributes, name)
+		}
+		if selfEditable(attribute) {
+			result.SelfEditableAttributes = append(result.SelfEditableAttributes, name)
+		}
+	}
+	slices.Sort(result.SensitiveAttributes)
+	slices.Sort(result.SelfEditableAttributes)
+	return result
+}
+
+// selfEditable reports whether users may write an attribute of their own
+// profile.
+func selfEditable(attribute okta.UserSchemaAttribute) bool {
+	for _, permission := range attribute.Permissions {
+		if permission.Principal == SchemaPrincipalSelf && permission.Action == SchemaPermissionReadWrite {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/locktivity/epack-collector-okta/pkg/okta"
+)
+
+// weakFactors records whether each phishable or guessable factor can be
+// used. A nil value means it could not be determined.
+type weakFactors struct {
+	sms      *bool
+	voice    *bool
+	email    *bool // Email accepted for authentication, not only recovery
+	question *bool
+}
+
+// collectWeakFactors determines which weak factors are enabled. Identity
+// Engine authenticators are authoritative; when they can't be read, Classic
+// Engine MFA enrollment policies stand in, where a factor counts as enabled
+// if some active policy lets users enroll it.
+func (c *Collector) collectWeakFactors(ctx context.Context, authenticators []okta.Authenticator, classicFactors map[string]bool) weakFactors {
+	if authenticators != nil {
+		return c.authenticatorWeakFactors(ctx, authenticators)
+	}
+
+	var result weakFactors
+	if classicFactors == nil {
+		return result
+	}
+	enrollable := func(factor string) *bool {
+		enabled := classicFactors[factor]
+		return &enabled
+	}
+	result.sms = enrollable(FactorKeySMS)
+	result.voice = enrollable(FactorKeyCall)
+	result.email = enrollable(FactorKeyEmail)
+	result.question = enrollable(FactorKeyQuestion)
+	return result
+}
+
+// authenticatorWeakFactors derives the weak factors from Identity Engine
+// authenticators. SMS and voice are methods of the phone authenticator; if
+// its methods can't be read, both follow the authenticator status.
+func (c *Collector) authenticatorWeakFactors(ctx context.Context, authenticators []okta.Authenticator) weakFactors {
+	sms, voice, email, question := false, false, false, false
+	for _, authenticator := range authenticators {
+		if authenticator.Status != StatusActive {
+			continue
+		}
+		switch authenticator.Key {
+		case AuthenticatorPhone:
+			methods, err := c.client.FetchAuthenticatorMethods(ctx, authenticator.ID)
+			if err != nil {
+				sms, voice = true, true
+				continue
+			}
+			for _, method := range methods {
+				switch {
+				case method.Status != StatusActive:
+				case method.Type == MethodSMS:
+					sms = true
+				case method.Type == MethodVoice:
+					voice = true
+				}
+			}
+		case AuthenticatorEmail:
+			email = authenticator.Settings.AllowedFor == AllowedForAny
+		case AuthenticatorSecurityQuestion:
+			question = true
+		}
+	}
+	return weakFactors{sms: &sms, voice: &voice, email: &email, question: &question}
+}
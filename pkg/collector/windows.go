@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// LogWindow is the System Log time range a section was computed from.
+// Each section reads the log with its own query, so windows of one run
+// start and end at slightly different times.
+type LogWindow struct {
+	Section string `json:"section"` // JSON name of the section, e.g. "sessions"
+	Since   string `json:"since"`   // Start of the window (UTC RFC 3339)
+	Until   string `json:"until"`   // End of the window (UTC RFC 3339)
+	Days    int    `json:"days"`    // Length of the window in days
+}
+
+// logWindow returns a System Log window of days ending now and records it
+// for the section.
+func (c *Collector) logWindow(section string, days int) (since, until time.Time) {
+	until = time.Now()
+	since = until.AddDate(0, 0, -days)
+
+	c.windowMu.Lock()
+	defer c.windowMu.Unlock()
+	c.windows = append(c.windows, LogWindow{
+		Section: section,
+		Since:   since.UTC().Format(time.RFC3339),
+		Until:   until.UTC().Format(time.RFC3339),
+		Days:    days,
+	})
+	return since, until
+}
+
+// takeWindows returns the System Log windows read since the last call,
+// sorted by section, and clears them for the next collection.
+func (c *Collector) takeWindows() []LogWindow {
+	c.windowMu.Lock()
+	defer c.windowMu.Unlock()
+	windows := c.windows
+	c.windows = nil
+	slices.SortFunc(windows, func(a, b LogWindow) int { return cmp.Compare(a.Section, b.Section) })
+	return windows
+}
@@ -0,0 +1,377 @@
+package okta
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AuthProvider authorizes API requests. Authorize is called before every
+// attempt, rate limit retries included, and sets the credentials on the
+// request, refreshing them first if they are about to expire. It must be
+// safe for concurrent use.
+//
+// The client supports SSWS API tokens (APITokenAuth) and OAuth 2.0 private
+// key JWT (NewClientWithOAuthConfig); other schemes, or fakes in tests, can
+// be installed with SetAuth.
+type AuthProvider interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// rejectionHandler is implemented by AuthProviders that can recover when
+// Okta rejects the credentials they set. The client calls rejected at most
+// once per request, on a 401, and retries the request if it returns true.
+type rejectionHandler interface {
+	rejected(ctx context.Context, req *http.Request) (bool, error)
+}
+
+// AuthProviderFunc adapts a function to an AuthProvider.
+type AuthProviderFunc func(ctx context.Context, req *http.Request) error
+
+// Authorize calls f(ctx, req).
+func (f AuthProviderFunc) Authorize(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// APITokenAuth authorizes requests with an SSWS API token.
+type APITokenAuth struct {
+	Token string
+}
+
+// Authorize sets the SSWS Authorization header.
+func (a APITokenAuth) Authorize(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "SSWS "+a.Token)
+	return nil
+}
+
+// SetAuth replaces how the client authorizes API requests. Like Use, it is
+// not safe to call concurrently with requests.
+func (c *Client) SetAuth(auth AuthProvider) {
+	c.auth = auth
+}
+
+// oauthAuth authorizes requests with access tokens minted by the OAuth 2.0
+// client credentials grant, refreshing them before they expire so
+// long-running processes keep working.
+type oauthAuth struct {
+	client *Client // Token exchanges use its base URL, User-Agent, and HTTP client
+	creds  *oauthCredentials
+
+	mu        sync.Mutex
+	token     string
+	expiry    time.Time // Zero if Okta didn't say when the token expires
+	activeKey int       // Index into creds.signingKeys() of the key that last minted a token
+	cached    string    // Token read from the token cache, until Okta rejects it
+
+	recoverMu sync.Mutex // Serializes recovery from a rejected cached token
+}
+
+// newOAuthAuth creates an OAuth provider for the client. It holds no token
+// until refresh is called.
+func newOAuthAuth(client *Client, creds *oauthCredentials) *oauthAuth {
+	return &oauthAuth{client: client, creds: creds}
+}
+
+// Authorize sets a Bearer Authorization header with the current token.
+func (a *oauthAuth) Authorize(ctx context.Context, req *http.Request) error {
+	token, err := a.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// currentToken returns the access token, refreshing it first if it is
+// about to expire.
+func (a *oauthAuth) currentToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	needsRefresh := !a.expiry.IsZero() && time.Until(a.expiry) < tokenRefreshMargin
+	a.mu.Unlock()
+
+	if needsRefresh {
+		if err := a.refresh(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token, nil
+}
+
+// setToken installs an access token, e.g. one read from the token cache.
+func (a *oauthAuth) setToken(token string, expiry time.Time) {
+	a.mu.Lock()
+	a.token = token
+	a.expiry = expiry
+	a.mu.Unlock()
+}
+
+// loadCachedToken installs the token from the token cache, if there is a
+// usable one, and reports whether it did.
+func (a *oauthAuth) loadCachedToken() bool {
+	if a.creds.cache == nil {
+		return false
+	}
+	token, expiry, ok := a.creds.cache.load()
+	if !ok {
+		return false
+	}
+	a.setToken(token, expiry)
+	a.mu.Lock()
+	a.cached = token
+	a.mu.Unlock()
+	return true
+}
+
+// rejected handles a 401 for a request it authorized and reports whether
+// the request should be retried. A cached token is trusted until it nears
+// expiry, but Okta rejects it earlier if it was revoked or the app was
+// deactivated, so a rejected cached token is dropped from the cache and a
+// new one minted. Any other rejection is final.
+func (a *oauthAuth) rejected(ctx context.Context, req *http.Request) (bool, error) {
+	sent := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+
+	a.recoverMu.Lock()
+	defer a.recoverMu.Unlock()
+	a.mu.Lock()
+	current, cached := a.token, a.cached
+	a.mu.Unlock()
+	if sent != current {
+		// A concurrent request already replaced the token
+		return true, nil
+	}
+	if sent != cached {
+		return false, nil
+	}
+
+	a.mu.Lock()
+	a.cached = ""
+	a.mu.Unlock()
+	a.creds.cache.clear()
+	if err := a.refresh(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// refresh mints a new access token via the client credentials grant.
+// The caller must not hold mu.
+func (a *oauthAuth) refresh(ctx context.Context) error {
+	accessToken, expiresIn, err := a.mint(ctx)
+	if err != nil {
+		return err
+	}
+
+	var expiry time.Time
+	if expiresIn > 0 {
+		expiry = time.Now().Add(expiresIn)
+	}
+	a.setToken(accessToken, expiry)
+
+	// Best-effort: a token that can't be cached is minted again next run
+	if a.creds.cache != nil && !expiry.IsZero() {
+		_ = a.creds.cache.save(accessToken, expiry)
+	}
+	return nil
+}
+
+// mint exchanges a client assertion for an access token. It signs with the
+// key that last succeeded, the preferred key at first, and if Okta rejects
+// that key as an invalid client, tries the other keys in order. It returns
+// the first key's error if none succeeds.
+func (a *oauthAuth) mint(ctx context.Context) (string, time.Duration, error) {
+	keys := a.creds.signingKeys()
+	a.mu.Lock()
+	start := a.activeKey
+	a.mu.Unlock()
+
+	var firstErr error
+	for i := range keys {
+		index := (start + i) % len(keys)
+
+		// Generate JWT for client credentials grant
+		assertion, err := generateClientAssertionJWT(a.creds.clientID, a.client.baseURL+a.creds.tokenPath(), keys[index])
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to generate JWT: %w", err)
+		}
+
+		// Exchange JWT for access token
+		accessToken, expiresIn, err := a.exchangeJWTForToken(ctx, assertion)
+		if err == nil {
+			a.mu.Lock()
+			a.activeKey = index
+			a.mu.Unlock()
+			return accessToken, expiresIn, nil
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to exchange JWT for token: %w", err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode != oauthErrorInvalidClient {
+			break
+		}
+	}
+	return "", 0, firstErr
+}
+
+// oauthCredentials holds what is needed to mint new access tokens.
+type oauthCredentials struct {
+	clientID     string
+	key          *rsa.PrivateKey // Preferred key; also seals the token cache
+	keyID        string
+	fallbacks    []signingKey // Tried in order when Okta rejects the preferred key
+	scope        string       // Space-separated scopes requested for each token
+	authServerID string       // Custom authorization server; empty for the org authorization server
+	cache        *tokenCache  // Persists tokens between runs; nil disables caching
+}
+
+// validAuthServerID matches authorization server IDs, e.g. "default" or
+// "aus1a2b3c4d5", and the empty ID of the org authorization server.
+var validAuthServerID = regexp.MustCompile(`^[A-Za-z0-9]*$`)
+
+// signingKey is a private key and the kid that identifies it to Okta.
+type signingKey struct {
+	key *rsa.PrivateKey
+	id  string // Empty to omit the kid header
+}
+
+// signingKeys returns the preferred key followed by the fallbacks.
+func (o *oauthCredentials) signingKeys() []signingKey {
+	return append([]signingKey{{key: o.key, id: o.keyID}}, o.fallbacks...)
+}
+
+// tokenPath returns the path of the token endpoint.
+func (o *oauthCredentials) tokenPath() string {
+	if o.authServerID == "" {
+		return "/oauth2/v1/token"
+	}
+	return fmt.Sprintf("/oauth2/%s/v1/token", url.PathEscape(o.authServerID))
+}
+
+// generateClientAssertionJWT creates a JWT for OAuth 2.0 client credentials
+// flow. Its audience is the token endpoint URL.
+func generateClientAssertionJWT(clientID, tokenURL string, key signingKey) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"aud": tokenURL,
+		"iss": clientID,
+		"sub": clientID,
+		"iat": now.Unix(),
+		"exp": now.Add(jwtExpiry).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+	return token.SignedString(key.key)
+}
+
+// exchangeJWTForToken exchanges a client assertion JWT for an access token
+// and returns the token with its lifetime. The exchange goes through the
+// client's HTTP client, so it uses the same proxy and TLS settings as API
+// requests. Network failures, Okta 5xx errors, and rate limits are retried.
+func (a *oauthAuth) exchangeJWTForToken(ctx context.Context, assertion string) (string, time.Duration, error) {
+	for attempt := 1; ; attempt++ {
+		accessToken, expiresIn, err := a.postToken(ctx, assertion)
+		if err == nil {
+			return accessToken, expiresIn, nil
+		}
+		var apiErr *APIError
+		rateLimited := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+		if attempt > maxTokenRetries || !(isTransient(ctx, err) || rateLimited) {
+			return "", 0, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", 0, ctx.Err()
+		case <-time.After(time.Duration(attempt) * tokenRetryBackoff):
+		}
+	}
+}
+
+// tokenRequestKey marks the context of token exchange requests, which
+// carry credentials in both directions.
+type tokenRequestKey struct{}
+
+// isTokenRequest reports whether a request is a token exchange, so
+// middleware such as the fixture recorder can leave it alone.
+func isTokenRequest(req *http.Request) bool {
+	marked, _ := req.Context().Value(tokenRequestKey{}).(bool)
+	return marked
+}
+
+// postToken makes one client credentials request to the token endpoint.
+// Error responses are returned as an APIError carrying the OAuth error.
+func (a *oauthAuth) postToken(ctx context.Context, assertion string) (string, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, tokenRequestKey{}, true), HTTPTimeout)
+	defer cancel()
+
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+	data.Set("scope", a.creds.scope)
+	data.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	data.Set("client_assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.client.baseURL+a.creds.tokenPath(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", a.client.userAgent)
+
+	resp, err := a.client.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{
+			Endpoint:   "token",
+			StatusCode: resp.StatusCode,
+			RequestID:  resp.Header.Get("X-Okta-Request-Id"),
+		}
+		var errResp struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodySize)).Decode(&errResp); err == nil {
+			apiErr.ErrorCode = errResp.Error
+			apiErr.ErrorSummary = errResp.ErrorDescription
+		}
+		return "", 0, fmt.Errorf("token exchange failed: %w", apiErr)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+		Scope       string `json:"scope"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", 0, err
+	}
+
+	if result.AccessToken == "" {
+		return "", 0, fmt.Errorf("token exchange returned empty access token")
+	}
+
+	return result.AccessToken, time.Duration(result.ExpiresIn) * time.Second, nil
+}
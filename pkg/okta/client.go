@@ -0,0 +1,1288 @@
+package okta
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OktaClient defines the interface for Okta API operations.
+// This interface allows for easy mocking in tests.
+type OktaClient interface {
+	// User operations
+	FetchUsers(ctx context.Context, callback func(User) error) error
+	FetchUserFactors(ctx context.Context, userID string) ([]Factor, error)
+	SearchUsers(ctx context.Context, expression string, callback func(User) error) error
+	FetchGroupMembers(ctx context.Context, groupID string, callback func(User) error) error
+
+	// Application operations
+	FetchApplications(ctx context.Context, callback func(Application) error) error
+
+	// Policy operations
+	FetchPolicies(ctx context.Context, policyType string) ([]Policy, error)
+	FetchPolicyRules(ctx context.Context, policyID string) ([]PolicyRule, error)
+
+	// System Log
+	FetchLogEvents(ctx context.Context, since, until time.Time, filter string, callback func(LogEvent) error) error
+
+	// App group assignments, with the groups embedded
+	FetchAppGroupAssignments(ctx context.Context, appID string) ([]ApplicationGroupAssignment, error)
+
+	// Directory agents
+	FetchAgentPools(ctx context.Context, poolType string) ([]AgentPool, error)
+
+	// Org settings
+	FetchOrgSettings(ctx context.Context) (*OrgSettings, error)
+	FetchSecurityNotificationSettings(ctx context.Context) (*SecurityNotificationSettings, error)
+	FetchEndUserSettings(ctx context.Context) (*EndUserSettings, error)
+	FetchOktaSupportSettings(ctx context.Context) (*OktaSupportSettings, error)
+	FetchOktaSupportCases(ctx context.Context) ([]OktaSupportCase, error)
+	FetchOrgMetadata(ctx context.Context) (*OrgMetadata, error)
+	FetchFeatures(ctx context.Context) ([]Feature, error)
+	FetchCurrentAPIToken(ctx context.Context) (*APIToken, error)
+
+	// Log streaming
+	FetchLogStreams(ctx context.Context) ([]LogStream, error)
+
+	// Custom admin roles (IAM)
+	FetchCustomRoles(ctx context.Context) ([]CustomRole, error)
+	FetchRolePermissions(ctx context.Context, roleID string) ([]RolePermission, error)
+	FetchResourceSets(ctx context.Context) ([]ResourceSet, error)
+	FetchResourceSetBindings(ctx context.Context, resourceSetID string) ([]ResourceSetBinding, error)
+	FetchBindingMembers(ctx context.Context, resourceSetID, roleID string) ([]BindingMember, error)
+
+	// Admin role assignments
+	FetchRoleAssignees(ctx context.Context) ([]RoleAssignee, error)
+	FetchUser(ctx context.Context, userID string) (*User, error)
+	FetchUserRoles(ctx context.Context, userID string) ([]RoleAssignment, error)
+	FetchUserGroups(ctx context.Context, userID string) ([]Group, error)
+	FetchGroupRoles(ctx context.Context, groupID string) ([]RoleAssignment, error)
+
+	// ThreatInsight and network zones
+	FetchThreatInsight(ctx context.Context) (*ThreatInsightConfiguration, error)
+	FetchNetworkZone(ctx context.Context, zoneID string) (*NetworkZone, error)
+	FetchNetworkZones(ctx context.Context) ([]NetworkZone, error)
+
+	// Identity Governance
+	FetchCampaigns(ctx context.Context) ([]Campaign, error)
+	FetchPendingReviews(ctx context.Context, campaignID string) ([]Review, error)
+	FetchEntitlementBundles(ctx context.Context) ([]EntitlementBundle, error)
+
+	// Universal Directory
+	FetchUserSchema(ctx context.Context) (*UserSchema, error)
+	FetchProfileMappings(ctx context.Context) ([]ProfileMapping, error)
+	FetchProfileMapping(ctx context.Context, mappingID string) (*ProfileMapping, error)
+
+	// Brands and sign-in pages
+	FetchBrands(ctx context.Context) ([]Brand, error)
+	FetchSignInPage(ctx context.Context, brandID, version string) (*SignInPage, error)
+
+	// Group rules
+	FetchGroupRules(ctx context.Context) ([]GroupRule, error)
+	FetchGroup(ctx context.Context, groupID string) (*Group, error)
+	FetchGroupStats(ctx context.Context, groupID string) (*GroupStats, error)
+	SearchGroups(ctx context.Context, expression string) ([]Group, error)
+
+	// CAPTCHA
+	FetchOrgCaptchaSettings(ctx context.Context) (*OrgCaptchaSettings, error)
+	FetchCaptchas(ctx context.Context) ([]Captcha, error)
+
+	// Authenticators (Identity Engine)
+	FetchAuthenticators(ctx context.Context) ([]Authenticator, error)
+	FetchAuthenticatorMethods(ctx context.Context, authenticatorID string) ([]AuthenticatorMethod, error)
+}
+
+// Client wraps the Okta REST API client.
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	auth        AuthProvider // Nil sends requests without credentials
+	userAgent   string
+	readTimeout time.Duration // Longest wait for more response body data
+
+	// Rate limit buckets, from response headers
+	limits rateLimits
+
+	// Request counters, read via Stats
+	requests    atomic.Int64
+	rateLimited atomic.Int64
+	pageRetries atomic.Int64
+	throttled   atomic.Int64
+	statsMu     sync.Mutex
+	endpoints   map[string]*EndpointStats // By rate limit bucket
+}
+
+// RequestStats summarizes the API traffic a client has made.
+type RequestStats struct {
+	Requests    int64 // HTTP requests sent, including retries
+	RateLimited int64 // Responses with status 429
+	PageRetries int64 // List pages re-fetched after a transient failure
+	Throttled   int64 // Requests held back to keep rate limit headroom
+
+	// Endpoints breaks the traffic down by rate limit bucket, e.g.
+	// "/api/v1/users" or "/api/v1/users*"
+	Endpoints map[string]EndpointStats
+}
+
+// EndpointStats summarizes the traffic to one rate limit bucket.
+type EndpointStats struct {
+	Requests    int64         // HTTP requests sent, including retries
+	RateLimited int64         // Responses with status 429
+	Retries     int64         // Requests re-sent after a 429 or a transient page failure
+	Backoff     time.Duration // Time spent waiting out rate limits and before retries
+}
+
+// Stats returns cumulative request counters for the client.
+func (c *Client) Stats() RequestStats {
+	c.statsMu.Lock()
+	endpoints := make(map[string]EndpointStats, len(c.endpoints))
+	for bucket, stats := range c.endpoints {
+		endpoints[bucket] = *stats
+	}
+	c.statsMu.Unlock()
+
+	return RequestStats{
+		Requests:    c.requests.Load(),
+		RateLimited: c.rateLimited.Load(),
+		PageRetries: c.pageRetries.Load(),
+		Throttled:   c.throttled.Load(),
+		Endpoints:   endpoints,
+	}
+}
+
+// countEndpoint updates the counters of a rate limit bucket.
+func (c *Client) countEndpoint(bucket string, update func(*EndpointStats)) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	if c.endpoints == nil {
+		c.endpoints = make(map[string]*EndpointStats)
+	}
+	stats, ok := c.endpoints[bucket]
+	if !ok {
+		stats = &EndpointStats{}
+		c.endpoints[bucket] = stats
+	}
+	update(stats)
+}
+
+// Ensure Client implements OktaClient.
+var _ OktaClient = (*Client)(nil)
+
+// newHTTPClient creates an HTTP client with a transport tuned for connection
+// reuse across many small sequential requests to a single Okta host. A
+// non-nil cert is presented on every connection, token exchanges included.
+func newHTTPClient(cert *tls.Certificate) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: keepAliveInterval,
+	}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        maxIdleConns,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+	}
+	if cert != nil {
+		transport.TLSClientConfig = &tls.Config{Certificates: []tls.Certificate{*cert}}
+	}
+	// No overall Timeout: doRequest bounds each attempt instead, so large
+	// pages can stream as long as they make progress.
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// NewClient creates a new Okta client with API token (SSWS) authentication.
+func NewClient(orgDomain, apiToken string) *Client {
+	return newAPITokenClient(orgDomain, apiToken, nil)
+}
+
+// NewClientWithCertificate creates an API token client that presents a TLS
+// client certificate, for orgs whose API gateway requires mTLS.
+func NewClientWithCertificate(orgDomain, apiToken string, cert tls.Certificate) *Client {
+	return newAPITokenClient(orgDomain, apiToken, &cert)
+}
+
+// newAPITokenClient creates an API token client with an optional client
+// certificate.
+func newAPITokenClient(orgDomain, apiToken string, cert *tls.Certificate) *Client {
+	return &Client{
+		httpClient:  newHTTPClient(cert),
+		baseURL:     buildBaseURL(orgDomain),
+		auth:        APITokenAuth{Token: apiToken},
+		userAgent:   DefaultUserAgent,
+		readTimeout: DefaultReadTimeout,
+	}
+}
+
+// NewClientWithOAuth creates a client using OAuth 2.0 private key JWT.
+// This is the recommended authentication method.
+// Scopes beyond DefaultScopes can be requested for optional features; each
+// must also be granted to the service app.
+func NewClientWithOAuth(orgDomain, clientID string, privateKey []byte, extraScopes ...string) (*Client, error) {
+	return NewClientWithOAuthConfig(OAuthConfig{
+		OrgDomain:   orgDomain,
+		ClientID:    clientID,
+		PrivateKey:  privateKey,
+		ExtraScopes: extraScopes,
+	})
+}
+
+// OAuthConfig configures OAuth 2.0 private key JWT authentication.
+type OAuthConfig struct {
+	OrgDomain   string
+	ClientID    string
+	PrivateKey  []byte   // PEM-encoded RSA private key
+	KeyID       string   // kid of PrivateKey's public key in Okta (optional)
+	ExtraScopes []string // Scopes beyond DefaultScopes
+
+	// FallbackKeys are tried in order when Okta rejects the preferred key
+	// as an invalid client, so a key can be rotated without a coordinated
+	// config change: register the next key with Okta, add it here, then
+	// remove the old key from Okta
+	FallbackKeys []OAuthKey
+
+	// AuthServerID requests tokens from a custom authorization server,
+	// e.g. "default" or "aus1a2b3c4d5"; empty selects the org
+	// authorization server
+	AuthServerID string
+
+	// TokenCachePath, when set, keeps the access token in this file
+	// between runs, encrypted with a key derived from PrivateKey, and
+	// reuses it until it nears expiry or Okta rejects it
+	TokenCachePath string
+
+	// ClientCertificate, when set, is presented on every TLS connection,
+	// token exchanges included, for API gateways that require mTLS and
+	// for certificate-bound access tokens
+	ClientCertificate *tls.Certificate
+}
+
+// OAuthKey is a private key registered with the service app.
+type OAuthKey struct {
+	PrivateKey []byte // PEM-encoded RSA private key
+	KeyID      string // kid of the public key in Okta (optional)
+}
+
+// NewClientWithOAuthConfig creates a client using OAuth 2.0 private key JWT
+// with the given configuration. Unless a cached token is reused, it
+// exchanges a client assertion for an access token before returning.
+func NewClientWithOAuthConfig(config OAuthConfig) (*Client, error) {
+	if !validAuthServerID.MatchString(config.AuthServerID) {
+		return nil, fmt.Errorf("invalid authorization server ID %q: expected letters and digits only", config.AuthServerID)
+	}
+	baseURL := buildBaseURL(config.OrgDomain)
+
+	// Parse the private keys
+	key, err := parsePrivateKey(config.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	var fallbacks []signingKey
+	for i, fallback := range config.FallbackKeys {
+		fallbackKey, err := parsePrivateKey(fallback.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fallback private key %d: %w", i+1, err)
+		}
+		fallbacks = append(fallbacks, signingKey{key: fallbackKey, id: fallback.KeyID})
+	}
+
+	c := &Client{
+		httpClient:  newHTTPClient(config.ClientCertificate),
+		baseURL:     baseURL,
+		userAgent:   DefaultUserAgent,
+		readTimeout: DefaultReadTimeout,
+	}
+	creds := &oauthCredentials{
+		clientID:     config.ClientID,
+		key:          key,
+		keyID:        config.KeyID,
+		fallbacks:    fallbacks,
+		scope:        strings.Join(append(slices.Clone(DefaultScopes), config.ExtraScopes...), " "),
+		authServerID: config.AuthServerID,
+	}
+	auth := newOAuthAuth(c, creds)
+	c.auth = auth
+	if config.TokenCachePath != "" {
+		creds.cache = newTokenCache(config.TokenCachePath, baseURL, creds)
+		if auth.loadCachedToken() {
+			return c, nil
+		}
+	}
+	if err := auth.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NewClientWithHTTP creates a client with a custom HTTP client and base URL (for testing).
+func NewClientWithHTTP(httpClient *http.Client, baseURL string) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		baseURL:     baseURL,
+		userAgent:   DefaultUserAgent,
+		readTimeout: DefaultReadTimeout,
+	}
+}
+
+// SetToken authorizes requests with an SSWS API token, for testing purposes.
+func (c *Client) SetToken(token string) {
+	c.SetAuth(APITokenAuth{Token: token})
+}
+
+// SetUserAgent sets the User-Agent header sent with every API request.
+func (c *Client) SetUserAgent(userAgent string) {
+	c.userAgent = userAgent
+}
+
+// SetReadTimeout sets how long a response body may stall before the request
+// fails. Zero or negative values restore DefaultReadTimeout.
+func (c *Client) SetReadTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultReadTimeout
+	}
+	c.readTimeout = timeout
+}
+
+// buildBaseURL constructs the Okta API base URL from the org domain.
+// Callers validate the domain first with ParseOrgDomain.
+func buildBaseURL(orgDomain string) string {
+	return fmt.Sprintf("https://%s", normalizeOrgDomain(orgDomain))
+}
+
+// parsePrivateKey parses a PEM-encoded RSA private key.
+func parsePrivateKey(keyData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	// Try PKCS#8 first (more common for OAuth)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key is not an RSA private key")
+		}
+		return rsaKey, nil
+	}
+
+	// Fall back to PKCS#1
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// doRequest performs an HTTP request with authentication and rate limit handling.
+func (c *Client) doRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return c.send(ctx, method, path, false)
+}
+
+// send performs an HTTP request, first waiting if its rate limit bucket is
+// running low. Pagination requests may use a bucket's reserve.
+func (c *Client) send(ctx context.Context, method, path string, pagination bool) (*http.Response, error) {
+	reqURL := fmt.Sprintf("%s%s", c.baseURL, path)
+	bucket := RateLimitBucketFor(path)
+	reauthorized := false
+
+	for attempt := 0; attempt <= maxRateLimitRetries; attempt++ {
+		waitStart := time.Now()
+		throttled, err := c.limits.wait(ctx, bucket, pagination)
+		if throttled {
+			c.throttled.Add(1)
+			waited := time.Since(waitStart)
+			c.countEndpoint(bucket, func(s *EndpointStats) { s.Backoff += waited })
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		if c.auth != nil {
+			if err := c.auth.Authorize(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+
+		// Start the attempt deadline after any token refresh
+		deadline := newAttemptDeadline(ctx, HTTPTimeout)
+		req = req.WithContext(deadline.ctx)
+
+		c.requests.Add(1)
+		c.countEndpoint(bucket, func(s *EndpointStats) {
+			s.Requests++
+			if attempt > 0 {
+				s.Retries++
+			}
+		})
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			deadline.stop()
+			if deadline.expired.Load() && ctx.Err() == nil {
+				return nil, fmt.Errorf("%w: no response within %v", ErrTimeout, HTTPTimeout)
+			}
+			return nil, err
+		}
+		resp.Body = deadline.body(resp.Body, c.readTimeout)
+		c.limits.observe(bucket, resp.Header)
+
+		// Retry once with new credentials if the provider can recover from
+		// the rejection, e.g. of a revoked cached token
+		if handler, ok := c.auth.(rejectionHandler); ok && resp.StatusCode == http.StatusUnauthorized && !reauthorized {
+			retry, err := handler.rejected(ctx, req)
+			if err != nil {
+				_ = resp.Body.Close()
+				return nil, err
+			}
+			if retry {
+				_ = resp.Body.Close()
+				reauthorized = true
+				attempt-- // Not a rate limit retry
+				continue
+			}
+		}
+
+		// Handle rate limiting
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.rateLimited.Add(1)
+			c.countEndpoint(bucket, func(s *EndpointStats) { s.RateLimited++ })
+			_ = resp.Body.Close()
+
+			// Don't retry if we've exhausted attempts
+			if attempt >= maxRateLimitRetries {
+				return nil, fmt.Errorf("%w after %d retries", ErrRateLimited, maxRateLimitRetries)
+			}
+
+			waitDuration := defaultBackoff
+			resetHeader := resp.Header.Get("X-Rate-Limit-Reset")
+			if resetHeader != "" {
+				if resetTime, err := strconv.ParseInt(resetHeader, 10, 64); err == nil {
+					waitDuration = time.Until(time.Unix(resetTime, 0)) + time.Second
+				}
+			}
+
+			// Cap wait duration
+			if waitDuration > maxRateLimitWait {
+				return nil, fmt.Errorf("%w: reset too far in future: %v", ErrRateLimited, waitDuration)
+			}
+			if waitDuration < 0 {
+				waitDuration = defaultBackoff
+			}
+
+			// Wait with context cancellation support
+			c.countEndpoint(bucket, func(s *EndpointStats) { s.Backoff += waitDuration })
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(waitDuration):
+				continue
+			}
+		}
+
+		// Setting Accept-Encoding disables the transport's transparent
+		// decompression, so gzip bodies are unwrapped here.
+		if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				_ = resp.Body.Close()
+				return nil, fmt.Errorf("failed to read gzip response: %w", err)
+			}
+			resp.Body = &gzipBody{Reader: gz, body: resp.Body}
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+			resp.ContentLength = -1
+		}
+
+		return resp, nil
+	}
+
+	return nil, ErrRateLimited
+}
+
+// attemptDeadline bounds a single request attempt. It cancels the attempt's
+// context when the timer fires, and the timer is re-armed on every body read
+// so only a stalled response fails.
+type attemptDeadline struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// newAttemptDeadline starts a deadline that fires after timeout.
+func newAttemptDeadline(ctx context.Context, timeout time.Duration) *attemptDeadline {
+	d := &attemptDeadline{}
+	d.ctx, d.cancel = context.WithCancel(ctx)
+	d.timer = time.AfterFunc(timeout, func() {
+		d.expired.Store(true)
+		d.cancel()
+	})
+	return d
+}
+
+// stop releases the deadline's timer and context.
+func (d *attemptDeadline) stop() {
+	d.timer.Stop()
+	d.cancel()
+}
+
+// body wraps a response body so each read must make progress within timeout.
+func (d *attemptDeadline) body(body io.ReadCloser, timeout time.Duration) io.ReadCloser {
+	d.timer.Reset(timeout)
+	return &deadlineBody{ReadCloser: body, deadline: d, timeout: timeout}
+}
+
+// deadlineBody is a response body guarded by an attemptDeadline.
+type deadlineBody struct {
+	io.ReadCloser
+	deadline *attemptDeadline
+	timeout  time.Duration
+}
+
+// Read re-arms the read timeout and reads from the underlying body.
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	b.deadline.timer.Reset(b.timeout)
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.deadline.expired.Load() {
+		err = fmt.Errorf("%w: no response data for %v", ErrTimeout, b.timeout)
+	}
+	return n, err
+}
+
+// Close closes the underlying body and releases the deadline.
+func (b *deadlineBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.deadline.stop()
+	return err
+}
+
+// gzipBody reads a gzip-decoded response body and closes the underlying body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes both the gzip reader and the underlying response body.
+func (b *gzipBody) Close() error {
+	_ = b.Reader.Close()
+	return b.body.Close()
+}
+
+// FetchUsers fetches all users with pagination.
+// Users are decoded one at a time and handed to the callback as they arrive,
+// so a full page is never materialized in memory.
+func (c *Client) FetchUsers(ctx context.Context, callback func(User) error) error {
+	return c.streamUsers(ctx, fmt.Sprintf("/api/v1/users?limit=%d", paginationLimit), "users", callback)
+}
+
+// SearchUsers streams the users matching an Okta search expression, such as
+// profile.department eq "Engineering".
+func (c *Client) SearchUsers(ctx context.Context, expression string, callback func(User) error) error {
+	query := url.Values{}
+	query.Set("search", expression)
+	query.Set("limit", strconv.Itoa(paginationLimit))
+	return c.streamUsers(ctx, "/api/v1/users?"+query.Encode(), "users", callback)
+}
+
+// FetchGroupMembers fetches all users in a group with pagination, streaming
+// them to the callback like FetchUsers.
+func (c *Client) FetchGroupMembers(ctx context.Context, groupID string, callback func(User) error) error {
+	path := fmt.Sprintf("/api/v1/groups/%s/users?limit=%d", url.PathEscape(groupID), paginationLimit)
+	if err := c.streamUsers(ctx, path, "group members", callback); err != nil {
+		return fmt.Errorf("group %s: %w", groupID, err)
+	}
+	return nil
+}
+
+// streamUsers pages through a user list endpoint starting at path.
+func (c *Client) streamUsers(ctx context.Context, path, endpoint string, callback func(User) error) error {
+	return streamPages(ctx, c, path, endpoint, false, callback)
+}
+
+// FetchLogEvents streams System Log events published between since and
+// until that match filter, an Okta System Log filter expression (empty for
+// all events). Bounding the query with until makes pagination terminate
+// instead of polling for new events.
+func (c *Client) FetchLogEvents(ctx context.Context, since, until time.Time, filter string, callback func(LogEvent) error) error {
+	query := url.Values{}
+	query.Set("since", since.UTC().Format(time.RFC3339))
+	query.Set("until", until.UTC().Format(time.RFC3339))
+	query.Set("sortOrder", "ASCENDING")
+	query.Set("limit", strconv.Itoa(logPageLimit))
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	path := "/api/v1/logs?" + query.Encode()
+
+	// An empty page means the window is exhausted
+	return streamPages(ctx, c, path, "logs", true, callback)
+}
+
+// FetchUserFactors fetches all MFA factors for a user.
+// Returns empty slice if user has no factors, error if request fails.
+func (c *Client) FetchUserFactors(ctx context.Context, userID string) ([]Factor, error) {
+	path := fmt.Sprintf("/api/v1/users/%s/factors", userID)
+
+	resp, err := c.doRequest(ctx, "GET", path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// User has no factors enrolled - this is valid, return empty slice
+		return []Factor{}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user %s: %w", userID, newAPIError("factors", resp))
+	}
+
+	var factors []Factor
+	if err := json.NewDecoder(resp.Body).Decode(&factors); err != nil {
+		return nil, err
+	}
+
+	return factors, nil
+}
+
+// FetchApplications fetches all applications with pagination.
+// Applications are streamed to the callback one at a time.
+func (c *Client) FetchApplications(ctx context.Context, callback func(Application) error) error {
+	path := fmt.Sprintf("/api/v1/apps?limit=%d", paginationLimit)
+	return streamPages(ctx, c, path, "apps", false, callback)
+}
+
+// FetchPolicies fetches all policies of a given type.
+func (c *Client) FetchPolicies(ctx context.Context, policyType string) ([]Policy, error) {
+	path := fmt.Sprintf("/api/v1/policies?type=%s", policyType)
+
+	resp, err := c.doRequest(ctx, "GET", path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("policies", resp)
+	}
+
+	var policies []Policy
+	if err := json.NewDecoder(resp.Body).Decode(&policies); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// FetchPolicyRules fetches all rules for a policy.
+func (c *Client) FetchPolicyRules(ctx context.Context, policyID string) ([]PolicyRule, error) {
+	path := fmt.Sprintf("/api/v1/policies/%s/rules", policyID)
+
+	resp, err := c.doRequest(ctx, "GET", path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("policy rules", resp)
+	}
+
+	var rules []PolicyRule
+	if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FetchOrgSettings fetches organization settings.
+func (c *Client) FetchOrgSettings(ctx context.Context) (*OrgSettings, error) {
+	path := "/api/v1/org"
+
+	resp, err := c.doRequest(ctx, "GET", path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError("org", resp)
+	}
+
+	var settings OrgSettings
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// FetchAgentPools fetches the agent pools of a type (AD, LDAP, IWA, ...),
+// including the status of each agent.
+func (c *Client) FetchAgentPools(ctx context.Context, poolType string) ([]AgentPool, error) {
+	var pools []AgentPool
+	path := "/api/v1/agentPools?poolType=" + url.QueryEscape(poolType)
+	if err := c.getJSON(ctx, path, "agent pools", &pools); err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+// FetchSecurityNotificationSettings fetches the end-user security
+// notification email settings. This is an internal Admin Console endpoint.
+func (c *Client) FetchSecurityNotificationSettings(ctx context.Context) (*SecurityNotificationSettings, error) {
+	var settings SecurityNotificationSettings
+	if err := c.getJSON(ctx, "/api/internal/org/settings/security-notification-settings", "security notification settings", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// FetchEndUserSettings fetches what users can change in their own account
+// and dashboard. Like the security notification settings, these come from
+// an internal Admin Console endpoint.
+func (c *Client) FetchEndUserSettings(ctx context.Context) (*EndUserSettings, error) {
+	var settings EndUserSettings
+	if err := c.getJSON(ctx, "/api/internal/org/settings/end-user-settings", "end-user settings", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// FetchOktaSupportSettings fetches whether Okta Support can access the org.
+func (c *Client) FetchOktaSupportSettings(ctx context.Context) (*OktaSupportSettings, error) {
+	var settings OktaSupportSettings
+	if err := c.getJSON(ctx, "/api/v1/org/privacy/oktaSupport", "okta support", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// FetchOktaSupportCases fetches the support cases that can be granted
+// impersonation access.
+func (c *Client) FetchOktaSupportCases(ctx context.Context) ([]OktaSupportCase, error) {
+	var cases struct {
+		SupportCases []OktaSupportCase `json:"supportCases"`
+	}
+	if err := c.getJSON(ctx, "/api/v1/org/privacy/oktaSupport/cases", "okta support cases", &cases); err != nil {
+		return nil, err
+	}
+	return cases.SupportCases, nil
+}
+
+// FetchOrgMetadata fetches the public org metadata, which identifies the
+// engine (pipeline) the org runs on.
+func (c *Client) FetchOrgMetadata(ctx context.Context) (*OrgMetadata, error) {
+	var metadata OrgMetadata
+	if err := c.getJSON(ctx, "/.well-known/okta-organization", "org metadata", &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// FetchCurrentAPIToken fetches the metadata of the API token the client
+// authenticates with. It fails with a 401 if the token is invalid.
+func (c *Client) FetchCurrentAPIToken(ctx context.Context) (*APIToken, error) {
+	var token APIToken
+	if err := c.getJSON(ctx, "/api/v1/api-tokens/current", "api token", &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FetchFeatures fetches the org's self-service features.
+func (c *Client) FetchFeatures(ctx context.Context) ([]Feature, error) {
+	return fetchList[Feature](ctx, c, "/api/v1/features", "features")
+}
+
+// FetchOrgCaptchaSettings fetches the org-wide CAPTCHA settings.
+func (c *Client) FetchOrgCaptchaSettings(ctx context.Context) (*OrgCaptchaSettings, error) {
+	var settings OrgCaptchaSettings
+	if err := c.getJSON(ctx, "/api/v1/org/captcha", "org captcha", &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// FetchThreatInsight fetches the org's ThreatInsight configuration.
+func (c *Client) FetchThreatInsight(ctx context.Context) (*ThreatInsightConfiguration, error) {
+	var config ThreatInsightConfiguration
+	if err := c.getJSON(ctx, "/api/v1/threats/configuration", "threat insight", &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// FetchNetworkZone fetches a single network zone.
+func (c *Client) FetchNetworkZone(ctx context.Context, zoneID string) (*NetworkZone, error) {
+	var zone NetworkZone
+	if err := c.getJSON(ctx, "/api/v1/zones/"+url.PathEscape(zoneID), "network zone", &zone); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// FetchNetworkZones fetches the org's network zones.
+func (c *Client) FetchNetworkZones(ctx context.Context) ([]NetworkZone, error) {
+	path := fmt.Sprintf("/api/v1/zones?limit=%d", paginationLimit)
+	return fetchList[NetworkZone](ctx, c, path, "network zones")
+}
+
+// FetchCaptchas fetches the configured CAPTCHA instances.
+func (c *Client) FetchCaptchas(ctx context.Context) ([]Captcha, error) {
+	var captchas []Captcha
+	if err := c.getJSON(ctx, "/api/v1/captchas", "captchas", &captchas); err != nil {
+		return nil, err
+	}
+	return captchas, nil
+}
+
+// FetchAppGroupAssignments fetches the groups an app is assigned to, with
+// each group embedded so built-in groups can be recognized without
+// okta.groups.read.
+func (c *Client) FetchAppGroupAssignments(ctx context.Context, appID string) ([]ApplicationGroupAssignment, error) {
+	path := fmt.Sprintf("/api/v1/apps/%s/groups?expand=group&limit=%d", url.PathEscape(appID), paginationLimit)
+	return fetchList[ApplicationGroupAssignment](ctx, c, path, "app group assignments")
+}
+
+// FetchAuthenticators fetches the org's authenticators. Classic Engine orgs
+// reject the request.
+func (c *Client) FetchAuthenticators(ctx context.Context) ([]Authenticator, error) {
+	var authenticators []Authenticator
+	if err := c.getJSON(ctx, "/api/v1/authenticators", "authenticators", &authenticators); err != nil {
+		return nil, err
+	}
+	return authenticators, nil
+}
+
+// FetchAuthenticatorMethods fetches the methods of an authenticator.
+func (c *Client) FetchAuthenticatorMethods(ctx context.Context, authenticatorID string) ([]AuthenticatorMethod, error) {
+	var methods []AuthenticatorMethod
+	path := fmt.Sprintf("/api/v1/authenticators/%s/methods", url.PathEscape(authenticatorID))
+	if err := c.getJSON(ctx, path, "authenticator methods", &methods); err != nil {
+		return nil, err
+	}
+	return methods, nil
+}
+
+// FetchLogStreams fetches the System Log streams (EventBridge, Splunk Cloud).
+func (c *Client) FetchLogStreams(ctx context.Context) ([]LogStream, error) {
+	var streams []LogStream
+	path := fmt.Sprintf("/api/v1/logStreams?limit=%d", paginationLimit)
+	if err := c.getJSON(ctx, path, "log streams", &streams); err != nil {
+		return nil, err
+	}
+	return streams, nil
+}
+
+// FetchCustomRoles fetches the org's custom admin roles.
+func (c *Client) FetchCustomRoles(ctx context.Context) ([]CustomRole, error) {
+	return fetchIAMList[CustomRole](ctx, c, "/api/v1/iam/roles", "custom roles", "roles")
+}
+
+// FetchRolePermissions fetches the permissions granted by a custom role.
+func (c *Client) FetchRolePermissions(ctx context.Context, roleID string) ([]RolePermission, error) {
+	path := "/api/v1/iam/roles/" + url.PathEscape(roleID) + "/permissions"
+	return fetchIAMList[RolePermission](ctx, c, path, "role permissions", "permissions")
+}
+
+// FetchResourceSets fetches the resource sets custom roles can be bound to.
+func (c *Client) FetchResourceSets(ctx context.Context) ([]ResourceSet, error) {
+	return fetchIAMList[ResourceSet](ctx, c, "/api/v1/iam/resource-sets", "resource sets", "resource-sets")
+}
+
+// FetchResourceSetBindings fetches the custom roles bound to a resource set.
+func (c *Client) FetchResourceSetBindings(ctx context.Context, resourceSetID string) ([]ResourceSetBinding, error) {
+	path := "/api/v1/iam/resource-sets/" + url.PathEscape(resourceSetID) + "/bindings"
+	return fetchIAMList[ResourceSetBinding](ctx, c, path, "resource set bindings", "roles")
+}
+
+// FetchBindingMembers fetches the users and groups holding a custom role on
+// a resource set.
+func (c *Client) FetchBindingMembers(ctx context.Context, resourceSetID, roleID string) ([]BindingMember, error) {
+	path := "/api/v1/iam/resource-sets/" + url.PathEscape(resourceSetID) + "/bindings/" + url.PathEscape(roleID) + "/members"
+	return fetchIAMList[BindingMember](ctx, c, path, "binding members", "members")
+}
+
+// FetchRoleAssignees fetches the users holding an admin role, directly or
+// through a group.
+func (c *Client) FetchRoleAssignees(ctx context.Context) ([]RoleAssignee, error) {
+	return fetchIAMList[RoleAssignee](ctx, c, "/api/v1/iam/assignees/users", "role assignees", "value")
+}
+
+// FetchUser fetches a single user by ID.
+func (c *Client) FetchUser(ctx context.Context, userID string) (*User, error) {
+	var user User
+	if err := c.getJSON(ctx, "/api/v1/users/"+url.PathEscape(userID), "user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FetchUserRoles fetches a user's admin role assignments.
+func (c *Client) FetchUserRoles(ctx context.Context, userID string) ([]RoleAssignment, error) {
+	return fetchList[RoleAssignment](ctx, c, "/api/v1/users/"+url.PathEscape(userID)+"/roles", "user roles")
+}
+
+// FetchUserGroups fetches the groups a user belongs to.
+func (c *Client) FetchUserGroups(ctx context.Context, userID string) ([]Group, error) {
+	path := fmt.Sprintf("/api/v1/users/%s/groups?limit=%d", url.PathEscape(userID), paginationLimit)
+	return fetchList[Group](ctx, c, path, "user groups")
+}
+
+// FetchGroupRoles fetches the admin roles assigned to a group.
+func (c *Client) FetchGroupRoles(ctx context.Context, groupID string) ([]RoleAssignment, error) {
+	return fetchList[RoleAssignment](ctx, c, "/api/v1/groups/"+url.PathEscape(groupID)+"/roles", "group roles")
+}
+
+// FetchGroupRules fetches the org's group rules.
+func (c *Client) FetchGroupRules(ctx context.Context) ([]GroupRule, error) {
+	path := fmt.Sprintf("/api/v1/groups/rules?limit=%d", paginationLimit)
+	return fetchList[GroupRule](ctx, c, path, "group rules")
+}
+
+// FetchGroup fetches a single group.
+func (c *Client) FetchGroup(ctx context.Context, groupID string) (*Group, error) {
+	var group Group
+	if err := c.getJSON(ctx, "/api/v1/groups/"+url.PathEscape(groupID), "group", &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// FetchGroupStats fetches a group's member and app counts, which Okta
+// keeps without listing the members.
+func (c *Client) FetchGroupStats(ctx context.Context, groupID string) (*GroupStats, error) {
+	var group Group
+	if err := c.getJSON(ctx, "/api/v1/groups/"+url.PathEscape(groupID)+"?expand=stats", "group stats", &group); err != nil {
+		return nil, err
+	}
+	if group.Embedded.Stats == nil {
+		return nil, fmt.Errorf("group %s: response has no stats", groupID)
+	}
+	return group.Embedded.Stats, nil
+}
+
+// SearchGroups fetches the groups matching an Okta search expression, e.g.
+// profile.name eq "Engineering".
+func (c *Client) SearchGroups(ctx context.Context, expression string) ([]Group, error) {
+	query := url.Values{}
+	query.Set("search", expression)
+	query.Set("limit", strconv.Itoa(paginationLimit))
+	return fetchList[Group](ctx, c, "/api/v1/groups?"+query.Encode(), "groups")
+}
+
+// FetchCampaigns fetches the org's access certification campaigns. Orgs
+// without Identity Governance answer with an error.
+func (c *Client) FetchCampaigns(ctx context.Context) ([]Campaign, error) {
+	return fetchIAMList[Campaign](ctx, c, "/governance/api/v1/campaigns", "campaigns", "data")
+}
+
+// FetchPendingReviews fetches the reviews of a campaign that no reviewer
+// has decided yet.
+func (c *Client) FetchPendingReviews(ctx context.Context, campaignID string) ([]Review, error) {
+	query := url.Values{}
+	query.Set("filter", fmt.Sprintf(`campaignId eq %q and decision eq "UNREVIEWED"`, campaignID))
+	return fetchIAMList[Review](ctx, c, "/governance/api/v1/reviews?"+query.Encode(), "reviews", "data")
+}
+
+// FetchEntitlementBundles fetches the org's entitlement bundles.
+func (c *Client) FetchEntitlementBundles(ctx context.Context) ([]EntitlementBundle, error) {
+	return fetchIAMList[EntitlementBundle](ctx, c, "/governance/api/v1/entitlement-bundles", "entitlement bundles", "data")
+}
+
+// FetchUserSchema fetches the profile schema of the default user type.
+func (c *Client) FetchUserSchema(ctx context.Context) (*UserSchema, error) {
+	var schema UserSchema
+	if err := c.getJSON(ctx, "/api/v1/meta/schemas/user/default", "user schema", &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// FetchProfileMappings fetches the org's profile mappings, without their
+// attribute expressions.
+func (c *Client) FetchProfileMappings(ctx context.Context) ([]ProfileMapping, error) {
+	path := fmt.Sprintf("/api/v1/mappings?limit=%d", paginationLimit)
+	return fetchList[ProfileMapping](ctx, c, path, "profile mappings")
+}
+
+// FetchProfileMapping fetches a profile mapping with its attribute
+// expressions.
+func (c *Client) FetchProfileMapping(ctx context.Context, mappingID string) (*ProfileMapping, error) {
+	var mapping ProfileMapping
+	if err := c.getJSON(ctx, "/api/v1/mappings/"+url.PathEscape(mappingID), "profile mapping", &mapping); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// FetchBrands fetches the org's brands.
+func (c *Client) FetchBrands(ctx context.Context) ([]Brand, error) {
+	path := fmt.Sprintf("/api/v1/brands?limit=%d", paginationLimit)
+	return fetchList[Brand](ctx, c, path, "brands")
+}
+
+// FetchSignInPage fetches a version of a brand's sign-in page: "default"
+// (Okta's page) or "customized" (the org's edits, 404 if there are none).
+func (c *Client) FetchSignInPage(ctx context.Context, brandID, version string) (*SignInPage, error) {
+	var page SignInPage
+	path := "/api/v1/brands/" + url.PathEscape(brandID) + "/pages/sign-in/" + url.PathEscape(version)
+	if err := c.getJSON(ctx, path, "sign-in page", &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// fetchList fetches every page of a list endpoint that links pages with the
+// Link header.
+func fetchList[T any](ctx context.Context, c *Client, path, endpoint string) ([]T, error) {
+	var items []T
+	err := streamPages(ctx, c, path, endpoint, false, func(item T) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// streamPages pages through a list endpoint starting at path, following the
+// Link header and handing each item to callback. A page that fails with a
+// transient error is retried from its cursor, skipping the items already
+// delivered, so one dropped connection doesn't restart the listing. With
+// stopOnEmpty, an empty page ends the listing even if it links a next page.
+func streamPages[T any](ctx context.Context, c *Client, path, endpoint string, stopOnEmpty bool, callback func(T) error) error {
+	for path != "" {
+		delivered := 0
+		var next string
+		for attempt := 1; ; attempt++ {
+			n, link, err := streamPage(ctx, c, path, endpoint, delivered, callback)
+			delivered += n
+			if err == nil {
+				next = link
+				break
+			}
+			var cbErr *callbackError
+			if errors.As(err, &cbErr) {
+				return cbErr.err
+			}
+			if attempt > maxPageRetries || !isTransient(ctx, err) {
+				return err
+			}
+
+			c.pageRetries.Add(1)
+			backoff := time.Duration(attempt) * pageRetryBackoff
+			c.countEndpoint(RateLimitBucketFor(path), func(s *EndpointStats) {
+				s.Retries++
+				s.Backoff += backoff
+			})
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		if stopOnEmpty && delivered == 0 {
+			break
+		}
+		path = next
+	}
+	return nil
+}
+
+// streamPage fetches one page, skipping its first skip items, and returns
+// how many items it handed to callback and the next page's path.
+func streamPage[T any](ctx context.Context, c *Client, path, endpoint string, skip int, callback func(T) error) (int, string, error) {
+	resp, err := c.send(ctx, "GET", path, true)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", newAPIError(endpoint, resp)
+	}
+
+	seen, delivered := 0, 0
+	err = decodeStream(resp.Body, func(item T) error {
+		seen++
+		if seen <= skip {
+			return nil
+		}
+		if err := callback(item); err != nil {
+			return &callbackError{err: err}
+		}
+		delivered++
+		return nil
+	})
+	if err != nil {
+		return delivered, "", err
+	}
+	return delivered, getNextLink(resp.Header.Get("Link")), nil
+}
+
+// callbackError marks an error returned by a caller's callback, which is
+// never retried.
+type callbackError struct {
+	err error
+}
+
+func (e *callbackError) Error() string { return e.err.Error() }
+func (e *callbackError) Unwrap() error { return e.err }
+
+// isTransient reports whether a page fetch failure is likely to succeed if
+// retried: dropped connections, stalled or truncated responses, and Okta
+// 5xx errors. Rate limits are already retried by doRequest.
+func isTransient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.Is(err, ErrTimeout) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+// fetchIAMList fetches every page of an IAM or Governance API list. These
+// lists wrap their items in an object under key and link the next page from
+// the body rather than the Link header.
+func fetchIAMList[T any](ctx context.Context, c *Client, path, endpoint, key string) ([]T, error) {
+	var items []T
+	for path != "" {
+		var page map[string]json.RawMessage
+		if err := c.getJSON(ctx, path, endpoint, &page); err != nil {
+			return nil, err
+		}
+
+		if raw, ok := page[key]; ok {
+			var pageItems []T
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, err
+			}
+			items = append(items, pageItems...)
+		}
+
+		path = ""
+		var links struct {
+			Next *Link `json:"next"`
+		}
+		if raw, ok := page["_links"]; ok && json.Unmarshal(raw, &links) == nil && links.Next != nil {
+			path = relativePath(links.Next.Href)
+		}
+	}
+	return items, nil
+}
+
+// getJSON fetches a single JSON document into out.
+func (c *Client) getJSON(ctx context.Context, path, endpoint string, out any) error {
+	resp, err := c.doRequest(ctx, "GET", path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(endpoint, resp)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// decodeStream decodes a JSON array element by element, invoking callback for
+// each item. Decoding stops at the first callback error.
+func decodeStream[T any](r io.Reader, callback func(T) error) error {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		if err := callback(item); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket
+	_, err = dec.Token()
+	return err
+}
+
+// getNextLink extracts the next page URL from the Link header.
+// Returns empty string if there is no next page.
+func getNextLink(linkHeader string) string {
+	if linkHeader == "" {
+		return ""
+	}
+
+	// Link header format: <url>; rel="next", <url>; rel="self"
+	parts := strings.Split(linkHeader, ",")
+	for _, part := range parts {
+		if strings.Contains(part, `rel="next"`) {
+			// Extract URL from <url>
+			urlPart := strings.TrimSpace(strings.Split(part, ";")[0])
+			urlPart = strings.TrimPrefix(urlPart, "<")
+			urlPart = strings.TrimSuffix(urlPart, ">")
+
+			return relativePath(urlPart)
+		}
+	}
+
+	return ""
+}
+
+// relativePath returns the path and query of an absolute URL. Paging links
+// come from the response, and the path is appended to the base URL, so
+// anything but an absolute path (e.g. "@evil.example/") is refused rather
+// than allowed to change the host the token is sent to.
+func relativePath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.HasPrefix(u.Path, "/") {
+		return ""
+	}
+	if u.RawQuery != "" {
+		return u.Path + "?" + u.RawQuery
+	}
+	return u.Path
+}
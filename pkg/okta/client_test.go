@@ -0,0 +1,1265 @@
+package okta
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestFetchUsers(t *testing.T) {
+	users := []User{
+		{ID: "user1", Status: "ACTIVE", LastLogin: time.Now()},
+		{ID: "user2", Status: "ACTIVE", LastLogin: time.Now()},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(users)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	var fetched []User
+	err := client.FetchUsers(context.Background(), func(u User) error {
+		fetched = append(fetched, u)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 users, got %d", len(fetched))
+	}
+}
+
+func TestFetchUsers_Pagination(t *testing.T) {
+	page := 0
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if page == 0 {
+			// First page - include Link header for next page
+			w.Header().Set("Link", `<`+serverURL+`/api/v1/users?after=cursor123>; rel="next"`)
+			_ = json.NewEncoder(w).Encode([]User{{ID: "user1"}})
+			page++
+		} else {
+			// Second page - no Link header
+			_ = json.NewEncoder(w).Encode([]User{{ID: "user2"}})
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	var fetched []User
+	err := client.FetchUsers(context.Background(), func(u User) error {
+		fetched = append(fetched, u)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 users across pages, got %d", len(fetched))
+	}
+}
+
+func TestFetchUsers_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	err := client.FetchUsers(context.Background(), func(u User) error {
+		return nil
+	})
+
+	if err == nil {
+		t.Error("expected error for 403 response")
+	}
+	if client.Stats().PageRetries != 0 {
+		t.Errorf("403 responses should not be retried, got %d retries", client.Stats().PageRetries)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	var search, limit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		search = r.URL.Query().Get("search")
+		limit = r.URL.Query().Get("limit")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]User{{ID: "user1"}})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	expression := `profile.department eq "Engineering" and status eq "ACTIVE"`
+	var fetched []User
+	err := client.SearchUsers(context.Background(), expression, func(u User) error {
+		fetched = append(fetched, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if search != expression {
+		t.Errorf("expected search %q, got %q", expression, search)
+	}
+	if limit != "200" {
+		t.Errorf("expected limit 200, got %q", limit)
+	}
+	if len(fetched) != 1 {
+		t.Errorf("expected 1 user, got %d", len(fetched))
+	}
+}
+
+func TestFetchGroupMembers(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]User{{ID: "user1"}, {ID: "user2"}})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	var fetched []User
+	err := client.FetchGroupMembers(context.Background(), "00g123", func(u User) error {
+		fetched = append(fetched, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if path != "/api/v1/groups/00g123/users" {
+		t.Errorf("unexpected path %s", path)
+	}
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 members, got %d", len(fetched))
+	}
+}
+
+func TestFetchLogEvents(t *testing.T) {
+	page := 0
+	var query url.Values
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// System Log keeps returning a next link; an empty page ends the window
+		w.Header().Set("Link", `<`+serverURL+`/api/v1/logs?after=cursor>; rel="next"`)
+		switch page {
+		case 0:
+			query = r.URL.Query()
+			_ = json.NewEncoder(w).Encode([]LogEvent{{UUID: "e1", EventType: "user.session.start"}})
+		case 1:
+			_ = json.NewEncoder(w).Encode([]LogEvent{{UUID: "e2", EventType: "user.session.start"}})
+		default:
+			_ = json.NewEncoder(w).Encode([]LogEvent{})
+		}
+		page++
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+	var fetched []LogEvent
+	err := client.FetchLogEvents(context.Background(), since, until, `eventType eq "user.session.start"`, func(e LogEvent) error {
+		fetched = append(fetched, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 events, got %d", len(fetched))
+	}
+	if page != 3 {
+		t.Errorf("expected 3 page requests, got %d", page)
+	}
+	if query.Get("since") != "2026-01-01T00:00:00Z" || query.Get("until") != "2026-01-02T00:00:00Z" {
+		t.Errorf("unexpected window since=%s until=%s", query.Get("since"), query.Get("until"))
+	}
+	if query.Get("filter") != `eventType eq "user.session.start"` {
+		t.Errorf("unexpected filter %q", query.Get("filter"))
+	}
+}
+
+func TestFetchAgentPools(t *testing.T) {
+	var poolType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		poolType = r.URL.Query().Get("poolType")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"p1","type":"AD","agents":[{"id":"a1","operationalStatus":"OPERATIONAL","lastConnection":1767225600000}]}]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	pools, err := client.FetchAgentPools(context.Background(), "AD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if poolType != "AD" {
+		t.Errorf("expected poolType=AD, got %q", poolType)
+	}
+	if len(pools) != 1 || len(pools[0].Agents) != 1 {
+		t.Fatalf("unexpected pools %+v", pools)
+	}
+	if agent := pools[0].Agents[0]; agent.OperationalStatus != "OPERATIONAL" || agent.LastConnection != 1767225600000 {
+		t.Errorf("unexpected agent %+v", agent)
+	}
+}
+
+func TestFetchSecurityNotificationSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/internal/org/settings/security-notification-settings" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sendEmailForNewDeviceEnabled":true,"sendEmailForFactorEnrollmentEnabled":false}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	settings, err := client.FetchSecurityNotificationSettings(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !settings.SendEmailForNewDeviceEnabled || settings.SendEmailForFactorEnrollmentEnabled {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+}
+
+func TestFetchCustomRoles_Pagination(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("after") == "" {
+			_, _ = w.Write([]byte(`{"roles":[{"id":"cr1","label":"Helpdesk"}],"_links":{"next":{"href":"` + serverURL + `/api/v1/iam/roles?after=cr1"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"roles":[{"id":"cr2","label":"Regional Admin"}],"_links":{}}`))
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	roles, err := client.FetchCustomRoles(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roles) != 2 || roles[1].Label != "Regional Admin" {
+		t.Errorf("unexpected roles %+v", roles)
+	}
+}
+
+func TestFetchPendingReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/governance/api/v1/reviews" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got, want := r.URL.Query().Get("filter"), `campaignId eq "icicamp1" and decision eq "UNREVIEWED"`; got != want {
+			t.Errorf("filter = %q, want %q", got, want)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"icr1","campaignId":"icicamp1","decision":"UNREVIEWED"}],"_links":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	reviews, err := client.FetchPendingReviews(context.Background(), "icicamp1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].ID != "icr1" {
+		t.Errorf("unexpected reviews %+v", reviews)
+	}
+}
+
+func TestFetchUserFactors(t *testing.T) {
+	factors := []Factor{
+		{ID: "f1", FactorType: "push", Status: "ACTIVE"},
+		{ID: "f2", FactorType: "webauthn", Status: "ACTIVE"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/user123/factors" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(factors)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	fetched, err := client.FetchUserFactors(context.Background(), "user123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 factors, got %d", len(fetched))
+	}
+
+	if fetched[1].FactorType != "webauthn" {
+		t.Errorf("expected webauthn factor, got %s", fetched[1].FactorType)
+	}
+}
+
+func TestFetchUserFactors_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	// 404 means user has no factors - should return empty slice, no error
+	factors, err := client.FetchUserFactors(context.Background(), "user123")
+	if err != nil {
+		t.Errorf("expected no error for 404, got %v", err)
+	}
+	if factors == nil {
+		t.Errorf("expected empty slice for 404, got nil")
+	}
+	if len(factors) != 0 {
+		t.Errorf("expected empty slice for 404, got %d factors", len(factors))
+	}
+}
+
+func TestFetchUserFactors_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	// 403 means permission error - should return error
+	_, err := client.FetchUserFactors(context.Background(), "user123")
+	if err == nil {
+		t.Error("expected error for 403 response")
+	}
+}
+
+func TestFetchApplications(t *testing.T) {
+	apps := []Application{
+		{ID: "app1", SignOnMode: "SAML_2_0", Status: "ACTIVE"},
+		{ID: "app2", SignOnMode: "OPENID_CONNECT", Status: "ACTIVE"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/apps" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apps)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	var fetched []Application
+	err := client.FetchApplications(context.Background(), func(a Application) error {
+		fetched = append(fetched, a)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 apps, got %d", len(fetched))
+	}
+}
+
+func TestFetchPolicies(t *testing.T) {
+	policies := []Policy{
+		{ID: "policy1", Status: "ACTIVE", Type: "OKTA_SIGN_ON"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/policies" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("type") != "OKTA_SIGN_ON" {
+			t.Errorf("expected type=OKTA_SIGN_ON, got %s", r.URL.Query().Get("type"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(policies)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	fetched, err := client.FetchPolicies(context.Background(), "OKTA_SIGN_ON")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 1 {
+		t.Errorf("expected 1 policy, got %d", len(fetched))
+	}
+}
+
+func TestFetchPolicyRules(t *testing.T) {
+	rules := []PolicyRule{
+		{ID: "rule1", Status: "ACTIVE"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/policies/policy123/rules" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(rules)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	fetched, err := client.FetchPolicyRules(context.Background(), "policy123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fetched) != 1 {
+		t.Errorf("expected 1 rule, got %d", len(fetched))
+	}
+}
+
+func TestFetchOrgSettings(t *testing.T) {
+	settings := OrgSettings{
+		ID:          "org123",
+		Subdomain:   "company",
+		CompanyName: "Company Inc",
+		Status:      "ACTIVE",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/org" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(settings)
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	fetched, err := client.FetchOrgSettings(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fetched.CompanyName != "Company Inc" {
+		t.Errorf("expected Company Inc, got %s", fetched.CompanyName)
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	var acceptEncoding string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_ = json.NewEncoder(gz).Encode([]User{{ID: "user1"}, {ID: "user2"}})
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	var fetched []User
+	err := client.FetchUsers(context.Background(), func(u User) error {
+		fetched = append(fetched, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if acceptEncoding != "gzip" {
+		t.Errorf("expected Accept-Encoding gzip, got %q", acceptEncoding)
+	}
+	if len(fetched) != 2 {
+		t.Errorf("expected 2 users from gzip body, got %d", len(fetched))
+	}
+}
+
+func TestStats(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := client.Stats()
+	if stats.Requests != 2 {
+		t.Errorf("expected 2 requests, got %d", stats.Requests)
+	}
+	if stats.RateLimited != 1 {
+		t.Errorf("expected 1 rate-limited response, got %d", stats.RateLimited)
+	}
+	org := stats.Endpoints["/api/v1/org"]
+	if org.Requests != 2 || org.RateLimited != 1 || org.Retries != 1 {
+		t.Errorf("expected 2 requests, 1 rate-limited, and 1 retry for /api/v1/org, got %+v", org)
+	}
+	if org.Backoff != defaultBackoff {
+		t.Errorf("expected %v backoff, got %v", defaultBackoff, org.Backoff)
+	}
+}
+
+func TestReadTimeout_SlowBodyStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		flusher := w.(http.Flusher)
+		_, _ = w.Write([]byte(`[`))
+		for i := range 5 {
+			if i > 0 {
+				_, _ = w.Write([]byte(`,`))
+			}
+			_, _ = w.Write([]byte(`{"id":"user` + strconv.Itoa(i) + `","status":"ACTIVE"}`))
+			flusher.Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.SetReadTimeout(150 * time.Millisecond)
+
+	count := 0
+	err := client.FetchUsers(context.Background(), func(User) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("a body that keeps making progress should not time out: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("expected 5 users, got %d", count)
+	}
+}
+
+func TestReadTimeout_StalledBody(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"org1",`))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.SetReadTimeout(100 * time.Millisecond)
+
+	_, err := client.FetchOrgSettings(context.Background())
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestPageRetry_ResumesFromCursor(t *testing.T) {
+	var firstPage, secondPage atomic.Int32
+	var serverURL string
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("after") == "" {
+			firstPage.Add(1)
+			w.Header().Set("Link", `<`+serverURL+`/api/v1/users?after=cursor1>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":"user1","status":"ACTIVE"},{"id":"user2","status":"ACTIVE"}]`))
+			return
+		}
+		if secondPage.Add(1) == 1 {
+			// Deliver one user, then stall until the read timeout drops the page
+			_, _ = w.Write([]byte(`[{"id":"user3","status":"ACTIVE"},`))
+			w.(http.Flusher).Flush()
+			<-release
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"user3","status":"ACTIVE"},{"id":"user4","status":"ACTIVE"}]`))
+	}))
+	defer server.Close()
+	defer close(release)
+	serverURL = server.URL
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.SetReadTimeout(100 * time.Millisecond)
+
+	var ids []string
+	err := client.FetchUsers(context.Background(), func(u User) error {
+		ids = append(ids, u.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(ids, ",") != "user1,user2,user3,user4" {
+		t.Errorf("expected each user once, got %v", ids)
+	}
+	if firstPage.Load() != 1 {
+		t.Errorf("expected the first page to be fetched once, got %d", firstPage.Load())
+	}
+	if secondPage.Load() != 2 {
+		t.Errorf("expected the failed page to be retried once, got %d fetches", secondPage.Load())
+	}
+	if client.Stats().PageRetries != 1 {
+		t.Errorf("expected 1 page retry, got %d", client.Stats().PageRetries)
+	}
+}
+
+func TestPageRetry_NotForClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"0oa1","status":"ACTIVE"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	stop := errors.New("stop")
+	err := client.FetchApplications(context.Background(), func(Application) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("callback errors should not be retried, got %d requests", calls.Load())
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	var ids []string
+	err := decodeStream(strings.NewReader(`[{"id":"a"},{"id":"b"},{"id":"c"}]`), func(u User) error {
+		ids = append(ids, u.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[2] != "c" {
+		t.Errorf("expected [a b c], got %v", ids)
+	}
+
+	// Callback errors stop decoding
+	stop := errors.New("stop")
+	count := 0
+	err = decodeStream(strings.NewReader(`[{"id":"a"},{"id":"b"}]`), func(u User) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected decoding to stop after 1 item, got %d", count)
+	}
+
+	// Non-array bodies are rejected
+	err = decodeStream(strings.NewReader(`{"errorCode":"E0000011"}`), func(u User) error { return nil })
+	if err == nil {
+		t.Error("expected error for non-array body")
+	}
+}
+
+func TestGetNextLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{
+			name:     "empty header",
+			header:   "",
+			expected: "",
+		},
+		{
+			name:     "no next link",
+			header:   `<https://example.okta.com/api/v1/users>; rel="self"`,
+			expected: "",
+		},
+		{
+			name:     "has next link",
+			header:   `<https://example.okta.com/api/v1/users?after=abc123>; rel="next"`,
+			expected: "/api/v1/users?after=abc123",
+		},
+		{
+			name:     "multiple links",
+			header:   `<https://example.okta.com/api/v1/users>; rel="self", <https://example.okta.com/api/v1/users?after=xyz>; rel="next"`,
+			expected: "/api/v1/users?after=xyz",
+		},
+		{
+			name:     "link that would change the host",
+			header:   `<@evil.example/api/v1/users?after=abc123>; rel="next"`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getNextLink(tt.header)
+			if result != tt.expected {
+				t.Errorf("getNextLink(%q) = %q, want %q", tt.header, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildBaseURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"company.okta.com", "https://company.okta.com"},
+		{"https://company.okta.com", "https://company.okta.com"},
+		{"http://company.okta.com", "https://company.okta.com"},
+		{"company.okta.com/", "https://company.okta.com"},
+	}
+
+	for _, tt := range tests {
+		result := buildBaseURL(tt.input)
+		if result != tt.expected {
+			t.Errorf("buildBaseURL(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestParseOrgDomain(t *testing.T) {
+	tests := []struct {
+		input string
+		host  string
+		cell  string
+	}{
+		{"company.okta.com", "company.okta.com", CellCommercial},
+		{"https://Company.okta.com/", "company.okta.com", CellCommercial},
+		{"company.oktapreview.com", "company.oktapreview.com", CellPreview},
+		{"company.okta-emea.com", "company.okta-emea.com", CellEMEA},
+		{"agency.okta-gov.com", "agency.okta-gov.com", CellGov},
+		{"agency.okta.mil", "agency.okta.mil", CellMil},
+		{"login.company.com", "login.company.com", CellCustom},
+	}
+
+	for _, tt := range tests {
+		domain, err := ParseOrgDomain(tt.input)
+		if err != nil {
+			t.Errorf("ParseOrgDomain(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if domain.Host != tt.host || domain.Cell != tt.cell {
+			t.Errorf("ParseOrgDomain(%q) = %+v, want host %q cell %q", tt.input, domain, tt.host, tt.cell)
+		}
+	}
+}
+
+func TestParseOrgDomain_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"company",
+		"okta.com",
+		"company.okta.com/oauth2",
+		"company.okta.com:8443",
+		"user@company.okta.com",
+		"company admin.okta.com",
+		"-company.okta.com",
+		"us.company.okta.com",
+		"company-admin.okta.com",
+	} {
+		if _, err := ParseOrgDomain(input); err == nil {
+			t.Errorf("ParseOrgDomain(%q) expected error", input)
+		}
+	}
+
+	_, err := ParseOrgDomain("company-admin.oktapreview.com")
+	if err == nil || !strings.Contains(err.Error(), "company.oktapreview.com") {
+		t.Errorf("expected Admin Console error to suggest the org domain, got %v", err)
+	}
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	var capturedAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]User{})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("my-test-token")
+
+	_ = client.FetchUsers(context.Background(), func(u User) error { return nil })
+
+	if capturedAuth != "SSWS my-test-token" {
+		t.Errorf("expected 'SSWS my-test-token', got %q", capturedAuth)
+	}
+}
+
+func TestSetAuth(t *testing.T) {
+	var capturedAuth string
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		capturedAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetAuth(AuthProviderFunc(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Fake credentials")
+		return nil
+	}))
+	if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if capturedAuth != "Fake credentials" {
+		t.Errorf("expected the provider's header, got %q", capturedAuth)
+	}
+
+	// A provider error fails the request before it is sent
+	errNoCredentials := errors.New("no credentials")
+	client.SetAuth(AuthProviderFunc(func(context.Context, *http.Request) error {
+		return errNoCredentials
+	}))
+	if _, err := client.FetchOrgSettings(context.Background()); !errors.Is(err, errNoCredentials) {
+		t.Errorf("expected the provider's error, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestUserAgentHeader(t *testing.T) {
+	var capturedUA string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedUA = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]User{})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	_ = client.FetchUsers(context.Background(), func(u User) error { return nil })
+	if capturedUA != DefaultUserAgent {
+		t.Errorf("expected default User-Agent %q, got %q", DefaultUserAgent, capturedUA)
+	}
+
+	client.SetUserAgent("epack-collector-okta/1.2.3 (run abc)")
+	_ = client.FetchUsers(context.Background(), func(u User) error { return nil })
+	if capturedUA != "epack-collector-okta/1.2.3 (run abc)" {
+		t.Errorf("expected custom User-Agent, got %q", capturedUA)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Okta-Request-Id", "req-abc123")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errorCode":"E0000006","errorSummary":"You do not have permission to perform the requested action","errorId":"oae123","errorCauses":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+
+	_, err := client.FetchPolicies(context.Background(), "OKTA_SIGN_ON")
+	if err == nil {
+		t.Fatal("expected error for 403 response")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", apiErr.StatusCode)
+	}
+	if apiErr.ErrorCode != "E0000006" {
+		t.Errorf("expected error code E0000006, got %q", apiErr.ErrorCode)
+	}
+	if apiErr.ErrorID != "oae123" {
+		t.Errorf("expected error ID oae123, got %q", apiErr.ErrorID)
+	}
+	if apiErr.RequestID != "req-abc123" {
+		t.Errorf("expected request ID req-abc123, got %q", apiErr.RequestID)
+	}
+	if !strings.Contains(err.Error(), "req-abc123") {
+		t.Errorf("expected request ID in error message, got %q", err.Error())
+	}
+
+	// Factor errors wrap the APIError with the user ID
+	_, err = client.FetchUserFactors(context.Background(), "user123")
+	if !errors.As(err, &apiErr) || apiErr.Endpoint != "factors" {
+		t.Errorf("expected factors APIError, got %v", err)
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/v1/token" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if scope := r.FormValue("scope"); scope != "okta.users.read okta.groups.read" {
+			t.Errorf("unexpected scope %q", scope)
+		}
+		n := exchanges.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "token-" + string(rune('0'+n)),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL}
+	auth := newOAuthAuth(client, &oauthCredentials{clientID: "client", key: key, scope: "okta.users.read okta.groups.read"})
+	auth.setToken("stale", time.Now().Add(time.Minute))
+
+	// Token within the refresh margin is replaced
+	token, err := auth.currentToken(context.Background())
+	if err != nil {
+		t.Fatalf("currentToken() error = %v", err)
+	}
+	if token != "token-1" {
+		t.Errorf("currentToken() = %q, want token-1", token)
+	}
+
+	// Fresh token is reused
+	token, err = auth.currentToken(context.Background())
+	if err != nil {
+		t.Fatalf("currentToken() error = %v", err)
+	}
+	if token != "token-1" {
+		t.Errorf("currentToken() = %q, want token-1", token)
+	}
+	if got := exchanges.Load(); got != 1 {
+		t.Errorf("exchanges = %d, want 1", got)
+	}
+}
+
+func TestTokenExchange_Retries(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var exchanges atomic.Int32
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		switch exchanges.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-2", "expires_in": 3600})
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "invalid_client", "error_description": "Invalid value for 'client_id' parameter."})
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL, userAgent: "collector-test"}
+	auth := newOAuthAuth(client, &oauthCredentials{clientID: "client", key: key, scope: "okta.users.read"})
+
+	// A 503 is retried
+	if err := auth.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	if auth.token != "token-2" || exchanges.Load() != 2 {
+		t.Errorf("expected token-2 after 2 exchanges, got %q after %d", auth.token, exchanges.Load())
+	}
+	if userAgent != "collector-test" {
+		t.Errorf("expected the client's User-Agent, got %q", userAgent)
+	}
+
+	// Rejected credentials are not
+	err = auth.refresh(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.ErrorCode != "invalid_client" {
+		t.Fatalf("expected invalid_client APIError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "token exchange failed") {
+		t.Errorf("expected token exchange failure, got %q", err.Error())
+	}
+	if exchanges.Load() != 3 {
+		t.Errorf("expected no retry of a 401, got %d exchanges", exchanges.Load())
+	}
+
+	// A canceled context stops the exchange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := auth.refresh(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTokenExchange_AuthorizationServer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var path, audience string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(r.FormValue("client_assertion"), claims); err != nil {
+			t.Errorf("parsing client assertion: %v", err)
+		}
+		audience, _ = claims["aud"].(string)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		authServerID string
+		path         string
+	}{
+		{"", "/oauth2/v1/token"},
+		{"default", "/oauth2/default/v1/token"},
+		{"aus1a2b3c4d5", "/oauth2/aus1a2b3c4d5/v1/token"},
+	} {
+		client := &Client{httpClient: server.Client(), baseURL: server.URL}
+		auth := newOAuthAuth(client, &oauthCredentials{clientID: "client", key: key, scope: "okta.users.read", authServerID: tc.authServerID})
+		if err := auth.refresh(context.Background()); err != nil {
+			t.Fatalf("refresh(%q) error = %v", tc.authServerID, err)
+		}
+		if path != tc.path {
+			t.Errorf("authorization server %q: expected %s, got %s", tc.authServerID, tc.path, path)
+		}
+		if audience != server.URL+tc.path {
+			t.Errorf("authorization server %q: expected audience %s, got %s", tc.authServerID, server.URL+tc.path, audience)
+		}
+	}
+
+	if _, err := NewClientWithOAuthConfig(OAuthConfig{OrgDomain: "company.okta.com", AuthServerID: "../v1"}); err == nil || !strings.Contains(err.Error(), "invalid authorization server ID") {
+		t.Errorf("expected invalid authorization server ID error, got %v", err)
+	}
+}
+
+func TestTokenExchange_KeyRotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	// Okta knows only the new key
+	var kids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _, err := jwt.NewParser().ParseUnverified(r.FormValue("client_assertion"), jwt.MapClaims{})
+		if err != nil {
+			t.Errorf("parsing client assertion: %v", err)
+			return
+		}
+		kid, _ := token.Header["kid"].(string)
+		kids = append(kids, kid)
+		w.Header().Set("Content-Type", "application/json")
+		if kid != "new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "invalid_client", "error_description": "The client_assertion signature is invalid."})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL}
+	auth := newOAuthAuth(client, &oauthCredentials{
+		clientID:  "client",
+		key:       oldKey,
+		keyID:     "old",
+		fallbacks: []signingKey{{key: newKey, id: "new"}},
+		scope:     "okta.users.read",
+	})
+	if err := auth.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	if strings.Join(kids, ",") != "old,new" {
+		t.Errorf("expected the old key then the new key, got %v", kids)
+	}
+
+	// The key that worked is tried first from then on
+	kids = nil
+	if err := auth.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	if strings.Join(kids, ",") != "new" {
+		t.Errorf("expected only the new key, got %v", kids)
+	}
+
+	// With every key rejected, the preferred key's error is returned
+	auth.creds.fallbacks = []signingKey{{key: newKey, id: "retired"}}
+	auth.activeKey = 0
+	kids = nil
+	err = auth.refresh(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != "invalid_client" {
+		t.Fatalf("expected invalid_client error, got %v", err)
+	}
+	if strings.Join(kids, ",") != "old,retired" {
+		t.Errorf("expected both keys to be tried, got %v", kids)
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "epack-collector"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+
+	var commonName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Expected handshake failures
+	server.StartTLS()
+	defer server.Close()
+
+	// Trust the test server's certificate in the client's own transport
+	connect := func(client *Client) error {
+		client.baseURL = server.URL
+		transport := client.httpClient.Transport.(*http.Transport)
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+		transport.TLSClientConfig.RootCAs.AddCert(server.Certificate())
+		_, err := client.FetchOrgSettings(context.Background())
+		return err
+	}
+
+	if err := connect(NewClientWithCertificate("company.okta.com", "test-token", cert)); err != nil {
+		t.Fatalf("FetchOrgSettings() error = %v", err)
+	}
+	if commonName != "epack-collector" {
+		t.Errorf("expected client certificate epack-collector, got %q", commonName)
+	}
+
+	if err := connect(NewClient("company.okta.com", "test-token")); err == nil {
+		t.Error("expected the handshake to fail without a client certificate")
+	}
+}
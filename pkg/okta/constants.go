@@ -0,0 +1,64 @@
+package okta
+
+import "time"
+
+// HTTP timeouts. Each request attempt has HTTPTimeout to connect and
+// receive response headers. The body may then stream for as long as data
+// keeps arriving, failing only after the read timeout passes without any.
+// Rate limit waits happen between attempts and count against neither.
+const (
+	HTTPTimeout        = 30 * time.Second
+	DefaultReadTimeout = 30 * time.Second
+)
+
+// DefaultUserAgent identifies collector traffic when no versioned agent is set.
+const DefaultUserAgent = "epack-collector-okta"
+
+// Transport tuning for the bursty per-user factor workload.
+const (
+	maxIdleConns        = 100
+	maxIdleConnsPerHost = 32
+	idleConnTimeout     = 90 * time.Second
+	dialTimeout         = 10 * time.Second
+	keepAliveInterval   = 30 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+// Rate limiting.
+const (
+	maxRateLimitRetries = 3
+	maxRateLimitWait    = 60 * time.Second
+	defaultBackoff      = time.Second
+
+	// rateLimitReservePercent of each bucket is left for list pagination
+	// and for the org's other integrations
+	rateLimitReservePercent = 10
+)
+
+// Page retries. A list page that fails with a transient error is fetched
+// again from its cursor rather than failing the whole listing.
+const (
+	maxPageRetries   = 3
+	pageRetryBackoff = time.Second // Multiplied by the attempt number
+)
+
+// DefaultScopes are the OAuth scopes every collection requests.
+var DefaultScopes = []string{"okta.users.read", "okta.apps.read", "okta.policies.read"}
+
+// OAuth configuration.
+const (
+	jwtExpiry          = 5 * time.Minute
+	tokenRefreshMargin = 5 * time.Minute // Refresh access tokens this long before expiry
+	maxTokenRetries    = 3
+	tokenRetryBackoff  = time.Second // Multiplied by the attempt number
+
+	// oauthErrorInvalidClient is the OAuth error for a client assertion
+	// Okta can't verify, e.g. one signed with a key it doesn't know
+	oauthErrorInvalidClient = "invalid_client"
+)
+
+// Pagination.
+const (
+	paginationLimit = 200
+	logPageLimit    = 1000 // System Log allows larger pages
+)
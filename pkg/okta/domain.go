@@ -0,0 +1,97 @@
+package okta
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Okta cells, identified by the org domain suffix.
+const (
+	CellCommercial = "commercial" // *.okta.com
+	CellPreview    = "preview"    // *.oktapreview.com sandboxes
+	CellEMEA       = "emea"       // *.okta-emea.com
+	CellGov        = "gov"        // *.okta-gov.com (FedRAMP)
+	CellMil        = "mil"        // *.okta.mil (DoD)
+	CellCustom     = "custom"     // Vanity (custom URL) domain
+)
+
+// cellSuffixes maps Okta-owned domain suffixes to their cell.
+var cellSuffixes = []struct {
+	suffix string
+	cell   string
+}{
+	{".oktapreview.com", CellPreview},
+	{".okta-emea.com", CellEMEA},
+	{".okta-gov.com", CellGov},
+	{".okta.mil", CellMil},
+	{".okta.com", CellCommercial},
+}
+
+// OrgDomain is a validated org domain.
+type OrgDomain struct {
+	Host string // Lowercased hostname, e.g. company.okta.com
+	Cell string // One of the Cell constants
+}
+
+// ParseOrgDomain validates an org domain and detects its cell. A scheme and
+// trailing slash are tolerated; paths, ports, credentials, Admin Console
+// domains, and malformed hostnames are rejected.
+func ParseOrgDomain(orgDomain string) (OrgDomain, error) {
+	host := strings.ToLower(normalizeOrgDomain(orgDomain))
+	if host == "" {
+		return OrgDomain{}, fmt.Errorf("org_domain is empty")
+	}
+	if strings.ContainsAny(host, "/:@?#") {
+		return OrgDomain{}, fmt.Errorf("org_domain %q must be a hostname, without path or port", orgDomain)
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return OrgDomain{}, fmt.Errorf("org_domain %q is not a fully qualified domain", orgDomain)
+	}
+	for _, label := range labels {
+		if !isDNSLabel(label) {
+			return OrgDomain{}, fmt.Errorf("org_domain %q is not a valid hostname", orgDomain)
+		}
+	}
+
+	for _, known := range cellSuffixes {
+		if "."+host == known.suffix {
+			return OrgDomain{}, fmt.Errorf("org_domain %q is missing the org subdomain", orgDomain)
+		}
+		if !strings.HasSuffix(host, known.suffix) {
+			continue
+		}
+		subdomain := strings.TrimSuffix(host, known.suffix)
+		if strings.Contains(subdomain, ".") {
+			return OrgDomain{}, fmt.Errorf("org_domain %q has an unexpected subdomain", orgDomain)
+		}
+		if strings.HasSuffix(subdomain, "-admin") {
+			return OrgDomain{}, fmt.Errorf("org_domain %q is the Admin Console domain; use %s%s", orgDomain, strings.TrimSuffix(subdomain, "-admin"), known.suffix)
+		}
+		return OrgDomain{Host: host, Cell: known.cell}, nil
+	}
+	return OrgDomain{Host: host, Cell: CellCustom}, nil
+}
+
+// normalizeOrgDomain strips a scheme and trailing slash from an org domain.
+func normalizeOrgDomain(orgDomain string) string {
+	orgDomain = strings.TrimSpace(orgDomain)
+	orgDomain = strings.TrimPrefix(orgDomain, "https://")
+	orgDomain = strings.TrimPrefix(orgDomain, "http://")
+	return strings.TrimSuffix(orgDomain, "/")
+}
+
+// isDNSLabel reports whether s is a valid DNS label: 1-63 letters, digits,
+// or hyphens, not starting or ending with a hyphen.
+func isDNSLabel(s string) bool {
+	if len(s) == 0 || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package okta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrRateLimited is returned when rate limit retries are exhausted.
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrTimeout is returned when Okta stops responding to a request.
+var ErrTimeout = errors.New("request timed out")
+
+// maxErrorBodySize caps how much of an error response body is read.
+const maxErrorBodySize = 64 * 1024
+
+// APIError is returned when the Okta API responds with an unexpected status.
+// It carries the standard Okta error body and the request ID so failures can
+// be traced in the Okta System Log or raised with Okta support.
+type APIError struct {
+	Endpoint     string   // Logical endpoint name, e.g. "users" or "policies"
+	StatusCode   int      // HTTP status code
+	ErrorCode    string   // Okta error code, e.g. "E0000006"
+	ErrorSummary string   // Human-readable summary
+	ErrorID      string   // Okta error ID
+	ErrorCauses  []string // Additional error cause summaries
+	RequestID    string   // X-Okta-Request-Id response header
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s API returned status %d", e.Endpoint, e.StatusCode)
+	if e.ErrorCode != "" {
+		fmt.Fprintf(&b, ": %s - %s", e.ErrorCode, e.ErrorSummary)
+	}
+	if len(e.ErrorCauses) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(e.ErrorCauses, "; "))
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request_id=%s]", e.RequestID)
+	}
+	return b.String()
+}
+
+// newAPIError builds an APIError from a non-success response.
+// The response body is read but not closed.
+func newAPIError(endpoint string, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Okta-Request-Id"),
+	}
+
+	var body struct {
+		ErrorCode    string `json:"errorCode"`
+		ErrorSummary string `json:"errorSummary"`
+		ErrorLink    string `json:"errorLink"`
+		ErrorID      string `json:"errorId"`
+		ErrorCauses  []struct {
+			ErrorSummary string `json:"errorSummary"`
+		} `json:"errorCauses"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodySize)).Decode(&body); err == nil {
+		apiErr.ErrorCode = body.ErrorCode
+		apiErr.ErrorSummary = body.ErrorSummary
+		apiErr.ErrorID = body.ErrorID
+		for _, cause := range body.ErrorCauses {
+			if cause.ErrorSummary != "" {
+				apiErr.ErrorCauses = append(apiErr.ErrorCauses, cause.ErrorSummary)
+			}
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,153 @@
+//go:build chaos
+// +build chaos
+
+package okta
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Faults configures the failures a FaultInjector adds to API requests.
+// Rates are probabilities between 0 and 1, drawn once per request; at most
+// one fault other than latency hits a request.
+type Faults struct {
+	Seed            uint64        // Seeds the draws, so a failing run can be repeated
+	Latency         time.Duration // Added before every matching request
+	RateLimitRate   float64       // Answer 429 without reaching Okta
+	RateLimitReset  time.Duration // Reset window advertised on injected 429s
+	ServerErrorRate float64       // Answer 503 without reaching Okta
+	MalformedRate   float64       // Cut a successful response body off mid-document
+
+	// Match selects the requests faults apply to; nil matches every request
+	Match func(req *http.Request) bool
+}
+
+// FaultCounts is how many faults were injected.
+type FaultCounts struct {
+	Delayed      int64
+	RateLimited  int64
+	ServerErrors int64
+	Malformed    int64
+}
+
+// FaultInjector injects latency, rate limits, server errors, and malformed
+// JSON into a client's requests, for resilience tests of retries and
+// partial results. It is built only with the chaos build tag.
+type FaultInjector struct {
+	faults Faults
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	counts FaultCounts
+}
+
+// NewFaultInjector creates an injector for the given faults.
+func NewFaultInjector(faults Faults) *FaultInjector {
+	return &FaultInjector{
+		faults: faults,
+		rng:    rand.New(rand.NewPCG(faults.Seed, faults.Seed)),
+	}
+}
+
+// Counts returns the faults injected so far.
+func (f *FaultInjector) Counts() FaultCounts {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts
+}
+
+// fault kinds drawn for a request.
+const (
+	faultNone = iota
+	faultRateLimit
+	faultServerError
+	faultMalformed
+)
+
+// draw picks the fault for one request and counts it.
+func (f *FaultInjector) draw() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.faults.Latency > 0 {
+		f.counts.Delayed++
+	}
+	r := f.rng.Float64()
+	switch {
+	case r < f.faults.RateLimitRate:
+		f.counts.RateLimited++
+		return faultRateLimit
+	case r < f.faults.RateLimitRate+f.faults.ServerErrorRate:
+		f.counts.ServerErrors++
+		return faultServerError
+	case r < f.faults.RateLimitRate+f.faults.ServerErrorRate+f.faults.MalformedRate:
+		f.counts.Malformed++
+		return faultMalformed
+	}
+	return faultNone
+}
+
+// Middleware returns the middleware that injects the faults. Register it
+// with Client.Use.
+func (f *FaultInjector) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if f.faults.Match != nil && !f.faults.Match(req) {
+				return next.RoundTrip(req)
+			}
+			fault := f.draw()
+
+			if f.faults.Latency > 0 {
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-time.After(f.faults.Latency):
+				}
+			}
+
+			switch fault {
+			case faultRateLimit:
+				reset := time.Now().Add(f.faults.RateLimitReset)
+				header := http.Header{"X-Rate-Limit-Reset": []string{strconv.FormatInt(reset.Unix(), 10)}}
+				return faultResponse(req, http.StatusTooManyRequests, header,
+					`{"errorCode":"E0000047","errorSummary":"API call exceeded rate limit due to too many requests."}`), nil
+			case faultServerError:
+				return faultResponse(req, http.StatusServiceUnavailable, http.Header{},
+					`{"errorCode":"E0000009","errorSummary":"Internal Server Error"}`), nil
+			case faultMalformed:
+				resp, err := next.RoundTrip(req)
+				if err != nil || resp.StatusCode != http.StatusOK {
+					return resp, err
+				}
+				body, err := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body[:len(body)/2]))
+				resp.Header.Del("Content-Length")
+				resp.ContentLength = -1
+				return resp, nil
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// faultResponse builds an Okta-style JSON error response.
+func faultResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode:    status,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,252 @@
+package okta
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/users" && r.URL.Query().Get("after") == "":
+			w.Header().Set("Link", `<`+serverURL+`/api/v1/users?after=cursor1>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":"user1","status":"ACTIVE","profile":{"login":"alice@corp.com","email":"alice@corp.com","firstName":"Alice"}}]`))
+		case r.URL.Path == "/api/v1/users":
+			_, _ = w.Write([]byte(`[{"id":"user2","status":"ACTIVE","profile":{"login":"bob@corp.com"}}]`))
+		case r.URL.Path == "/api/v1/users/user1/factors":
+			_, _ = w.Write([]byte(`[{"id":"f1","factorType":"webauthn","status":"ACTIVE"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	// Record
+	rec := NewRecorder()
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("secret-token")
+	client.EnableRecording(rec)
+
+	var recorded []User
+	if err := client.FetchUsers(context.Background(), func(u User) error {
+		recorded = append(recorded, u)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+	if _, err := client.FetchUserFactors(context.Background(), "user1"); err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+	if recorded[0].Profile.Login != "alice@corp.com" {
+		t.Errorf("recording should not alter live responses, got %q", recorded[0].Profile.Login)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("failed to save fixture: %v", err)
+	}
+
+	// Fixture is sanitized
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	for _, leaked := range []string{"alice@corp.com", "Alice", "secret-token", strings.TrimPrefix(server.URL, "http://")} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("fixture leaks %q", leaked)
+		}
+	}
+
+	// Replay
+	replay, err := NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	var replayed []User
+	if err := replay.FetchUsers(context.Background(), func(u User) error {
+		replayed = append(replayed, u)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error while replaying: %v", err)
+	}
+	if len(replayed) != 2 {
+		t.Fatalf("expected 2 replayed users across pages, got %d", len(replayed))
+	}
+	if !strings.HasSuffix(replayed[0].Profile.Login, "@example.com") {
+		t.Errorf("expected redacted login, got %q", replayed[0].Profile.Login)
+	}
+
+	factors, err := replay.FetchUserFactors(context.Background(), "user1")
+	if err != nil || len(factors) != 1 || factors[0].FactorType != "webauthn" {
+		t.Errorf("unexpected replayed factors: %v, %v", factors, err)
+	}
+
+	// Unrecorded requests return an Okta-style 404
+	if _, err := replay.FetchPolicies(context.Background(), "OKTA_SIGN_ON"); err == nil {
+		t.Error("expected error for unrecorded request")
+	}
+}
+
+func TestRecord_SanitizesPII(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/users":
+			_, _ = w.Write([]byte(`[{"id":"00u1","status":"ACTIVE",` +
+				`"profile":{"login":"alice.smith@corp.com","email":"alice.smith@corp.com","mobilePhone":"+1-555-0100"},` +
+				`"_links":{"self":{"href":"` + serverURL + `/api/v1/users/00u1"}}}]`))
+		case "/api/v1/users/00u1/factors":
+			_, _ = w.Write([]byte(`[` +
+				`{"id":"f1","factorType":"sms","status":"ACTIVE","profile":{"phoneNumber":"+1-555-0199"}},` +
+				`{"id":"f2","factorType":"email","status":"ACTIVE","profile":{"email":"alice.smith@corp.com"}},` +
+				`{"id":"f3","factorType":"webauthn","status":"ACTIVE","profile":{"credentialId":"alice.smith@corp.com"}}]`))
+		case "/api/v1/logs":
+			_, _ = w.Write([]byte(`[{"uuid":"e1","eventType":"user.session.start","published":"2026-01-01T00:00:00Z",` +
+				`"actor":{"id":"00u1","type":"User","alternateId":"alice.smith@corp.com","displayName":"Alice Smith"},` +
+				`"client":{"ipAddress":"203.0.113.7","geographicalContext":{"city":"Springfield","postalCode":"62701"}},` +
+				`"target":[{"id":"00u2","type":"User","alternateId":"bob.jones@corp.com"}]}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	rec := NewRecorder()
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("secret-token")
+	client.EnableRecording(rec)
+
+	ctx := context.Background()
+	if err := client.FetchUsers(ctx, func(User) error { return nil }); err != nil {
+		t.Fatalf("recording users: %v", err)
+	}
+	if _, err := client.FetchUserFactors(ctx, "00u1"); err != nil {
+		t.Fatalf("recording factors: %v", err)
+	}
+	until := time.Now()
+	if err := client.FetchLogEvents(ctx, until.Add(-time.Hour), until, "", func(LogEvent) error { return nil }); err != nil {
+		t.Fatalf("recording logs: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("failed to save fixture: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	for _, leaked := range []string{
+		"alice.smith", "bob.jones", "corp.com", "Alice Smith", "555-0100", "555-0199",
+		"203.0.113.7", "Springfield", "62701", strings.TrimPrefix(server.URL, "http://"),
+	} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("fixture leaks %q", leaked)
+		}
+	}
+	if !strings.Contains(string(data), `"/api/v1/users/00u1"`) {
+		t.Error("hrefs should keep their path")
+	}
+}
+
+func TestRecordReplay_TokenRefreshNotRecorded(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	const liveToken = "live-access-token-0123456789"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth2/v1/token":
+			_, _ = w.Write([]byte(`{"access_token":"` + liveToken + `","token_type":"Bearer","expires_in":3600}`))
+		case "/api/v1/users":
+			if r.Header.Get("Authorization") != "Bearer "+liveToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"id":"user1","status":"ACTIVE","profile":{"login":"alice@corp.com"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	rec := NewRecorder()
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	auth := newOAuthAuth(client, &oauthCredentials{clientID: "client", key: key, scope: "okta.users.read"})
+	client.SetAuth(auth)
+	client.EnableRecording(rec)
+
+	// The token is about to expire, so the first request refreshes it
+	// through the recording transport
+	auth.setToken("expiring", time.Now().Add(time.Minute))
+	if err := client.FetchUsers(context.Background(), func(User) error { return nil }); err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("failed to save fixture: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if strings.Contains(string(data), liveToken) || strings.Contains(string(data), "/oauth2/v1/token") {
+		t.Errorf("fixture records the token exchange:\n%s", data)
+	}
+	if !strings.Contains(string(data), "GET /api/v1/users") {
+		t.Error("fixture is missing the users response")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	rec := NewRecorder()
+	if rec.redact("alice@corp.com") != rec.redact("alice@corp.com") {
+		t.Error("redaction should be consistent within a recording")
+	}
+	if rec.redact("alice@corp.com") == rec.redact("bob@corp.com") {
+		t.Error("distinct values should redact differently")
+	}
+	if !strings.HasSuffix(rec.redact("alice@corp.com"), "@example.com") {
+		t.Error("redacted emails should remain email-shaped")
+	}
+	// Placeholders are keyed per recording, so they can't be precomputed
+	// from guessed logins
+	if rec.redact("alice@corp.com") == NewRecorder().redact("alice@corp.com") {
+		t.Error("placeholders should differ between recordings")
+	}
+	sum := sha256.Sum256([]byte("alice@corp.com"))
+	if strings.Contains(rec.redact("alice@corp.com"), hex.EncodeToString(sum[:4])) {
+		t.Error("placeholder should not be derived from an unkeyed hash")
+	}
+
+	var doc map[string]any
+	_ = json.Unmarshal(rec.sanitizeJSON([]byte(`{"profile":{"email":"a@b.com","department":"Eng"}}`)), &doc)
+	profile := doc["profile"].(map[string]any)
+	if profile["department"] != "Eng" {
+		t.Errorf("non-PII fields should be kept, got %v", profile["department"])
+	}
+
+	if token := string(rec.sanitizeJSON([]byte(`{"access_token":"eyJ-secret","token_type":"Bearer"}`))); strings.Contains(token, "eyJ-secret") {
+		t.Errorf("access_token should be redacted, got %s", token)
+	}
+}
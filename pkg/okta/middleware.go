@@ -0,0 +1,59 @@
+package okta
+
+import "net/http"
+
+// Middleware wraps the transport that sends API requests, to observe or
+// alter each attempt: logging, metrics, throttling, or injecting faults in
+// tests. Requests reaching a middleware already carry the Authorization and
+// User-Agent headers; rate limit retries pass through it once per attempt.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Use registers middleware around the client's transport. The first
+// middleware given is the outermost, and each call to Use wraps the
+// middleware registered before it. Use is not safe to call concurrently
+// with requests; register middleware before collecting.
+//
+// OAuth token refreshes pass through middleware as well, without an
+// Authorization header. The first token exchange happens when the client
+// is created, before any middleware is registered.
+func (c *Client) Use(middleware ...Middleware) {
+	if len(middleware) == 0 {
+		return
+	}
+	transport := c.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		transport = middleware[i](transport)
+	}
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
+
+// LimitConcurrency returns middleware that allows at most n requests to
+// await a response at once across everything sharing the client. Requests
+// waiting for a slot give up when their context is canceled.
+func LimitConcurrency(n int) Middleware {
+	slots := make(chan struct{}, max(n, 1))
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			select {
+			case slots <- struct{}{}:
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+			defer func() { <-slots }()
+			return next.RoundTrip(req)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUse_Order(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.Use(trace("inner"))
+	client.Use(trace("outer"), trace("middle"))
+
+	if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(order, ",") != "outer,middle,inner" {
+		t.Errorf("expected outer,middle,inner, got %v", order)
+	}
+}
+
+func TestUse_SeesAuthenticatedRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	defer server.Close()
+
+	var auth, path string
+	var status int
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			auth = req.Header.Get("Authorization")
+			path = req.URL.Path
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				status = resp.StatusCode
+			}
+			return resp, err
+		})
+	})
+
+	if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth != "SSWS test-token" {
+		t.Errorf("expected SSWS test-token, got %q", auth)
+	}
+	if path != "/api/v1/org" {
+		t.Errorf("expected /api/v1/org, got %q", path)
+	}
+	if status != http.StatusOK {
+		t.Errorf("expected status 200, got %d", status)
+	}
+}
+
+func TestUse_FaultInjection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.Use(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"errorCode":"E0000006","errorSummary":"You do not have permission to perform the requested action"}`
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		})
+	})
+
+	_, err := client.FetchOrgSettings(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.ErrorCode != "E0000006" {
+		t.Errorf("expected injected 403 E0000006, got %d %s", apiErr.StatusCode, apiErr.ErrorCode)
+	}
+}
+
+func TestLimitConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	client.Use(LimitConcurrency(2))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", peak.Load())
+	}
+}
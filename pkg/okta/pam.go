@@ -0,0 +1,167 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// PAMClient reads an Okta Privileged Access team. The Privileged Access API
+// is served from the team's own domain and authenticates with a service
+// user's API key, not with Okta org credentials.
+type PAMClient struct {
+	httpClient *http.Client
+	baseURL    string // e.g. https://acme.pam.okta.com
+	team       string
+	keyID      string
+	keySecret  string
+
+	mu    sync.Mutex
+	token string // Bearer token, fetched on first use
+}
+
+// NewPAMClient creates a client for the team at https://<team>.pam.okta.com.
+func NewPAMClient(team, keyID, keySecret string) *PAMClient {
+	return NewPAMClientWithHTTP(&http.Client{Timeout: HTTPTimeout}, "https://"+team+".pam.okta.com", team, keyID, keySecret)
+}
+
+// NewPAMClientWithHTTP creates a client with a custom HTTP client and base
+// URL, for testing or for teams on a custom domain.
+func NewPAMClientWithHTTP(httpClient *http.Client, baseURL, team, keyID, keySecret string) *PAMClient {
+	return &PAMClient{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		team:       team,
+		keyID:      keyID,
+		keySecret:  keySecret,
+	}
+}
+
+// PAMResourceGroup groups the projects of a Privileged Access team.
+type PAMResourceGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PAMProject is a set of servers with shared access policy.
+type PAMProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PAMServer is a server enrolled in a project.
+type PAMServer struct {
+	ID       string `json:"id"`
+	Hostname string `json:"hostname"`
+	OSType   string `json:"os_type"` // linux or windows
+}
+
+// FetchResourceGroups fetches the team's resource groups.
+func (c *PAMClient) FetchResourceGroups(ctx context.Context) ([]PAMResourceGroup, error) {
+	return fetchPAMList[PAMResourceGroup](ctx, c, c.teamPath("resource_groups"), "pam resource groups")
+}
+
+// FetchProjects fetches the projects of a resource group.
+func (c *PAMClient) FetchProjects(ctx context.Context, resourceGroupID string) ([]PAMProject, error) {
+	path := c.teamPath("resource_groups", resourceGroupID, "projects")
+	return fetchPAMList[PAMProject](ctx, c, path, "pam projects")
+}
+
+// FetchServers fetches the servers enrolled in a project.
+func (c *PAMClient) FetchServers(ctx context.Context, resourceGroupID, projectID string) ([]PAMServer, error) {
+	path := c.teamPath("resource_groups", resourceGroupID, "projects", projectID, "servers")
+	return fetchPAMList[PAMServer](ctx, c, path, "pam servers")
+}
+
+// teamPath joins escaped path segments below /v1/teams/<team>/.
+func (c *PAMClient) teamPath(segments ...string) string {
+	path := "/v1/teams/" + url.PathEscape(c.team)
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return path
+}
+
+// authenticate exchanges the service user's key for a bearer token.
+func (c *PAMClient) authenticate(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" {
+		return c.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"key_id": c.keyID, "key_secret": c.keySecret})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+c.teamPath("service_token"), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError("pam service token", resp)
+	}
+
+	var token struct {
+		BearerToken string `json:"bearer_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.BearerToken == "" {
+		return "", fmt.Errorf("pam service token: no bearer token in response")
+	}
+	c.token = token.BearerToken
+	return c.token, nil
+}
+
+// fetchPAMList fetches every page of a Privileged Access list. These lists
+// wrap their items under "list" and link the next page with the Link header.
+func fetchPAMList[T any](ctx context.Context, c *PAMClient, path, endpoint string) ([]T, error) {
+	token, err := c.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []T
+	for path != "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			List []T `json:"list"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = newAPIError(endpoint, resp)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(&page)
+		}
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.List...)
+		path = getNextLink(resp.Header.Get("Link"))
+	}
+	return items, nil
+}
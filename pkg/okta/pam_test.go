@@ -0,0 +1,80 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPAMClient(t *testing.T) {
+	tokens := 0
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/teams/acme/service_token" {
+			var key struct {
+				KeyID     string `json:"key_id"`
+				KeySecret string `json:"key_secret"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&key)
+			if r.Method != "POST" || key.KeyID != "kid" || key.KeySecret != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			tokens++
+			_, _ = w.Write([]byte(`{"bearer_token":"pam-token","team_name":"acme"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer pam-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.RequestURI() {
+		case "/v1/teams/acme/resource_groups":
+			w.Header().Set("Link", `<`+serverURL+`/v1/teams/acme/resource_groups?offset=rg1>; rel="next"`)
+			_, _ = w.Write([]byte(`{"list":[{"id":"rg1","name":"Production"}]}`))
+		case "/v1/teams/acme/resource_groups?offset=rg1":
+			_, _ = w.Write([]byte(`{"list":[{"id":"rg2","name":"Staging"}]}`))
+		case "/v1/teams/acme/resource_groups/rg1/projects/p1/servers":
+			_, _ = w.Write([]byte(`{"list":[{"id":"s1","hostname":"web-1","os_type":"linux"}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := NewPAMClientWithHTTP(server.Client(), server.URL, "acme", "kid", "secret")
+	groups, err := client.FetchResourceGroups(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 || groups[1].Name != "Staging" {
+		t.Errorf("unexpected resource groups %+v", groups)
+	}
+	servers, err := client.FetchServers(context.Background(), "rg1", "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(servers) != 1 || servers[0].Hostname != "web-1" {
+		t.Errorf("unexpected servers %+v", servers)
+	}
+	if tokens != 1 {
+		t.Errorf("service token fetched %d times, want once", tokens)
+	}
+
+	_, err = client.FetchProjects(context.Background(), "rg2")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Endpoint != "pam projects" {
+		t.Errorf("expected a 403 APIError, got %v", err)
+	}
+
+	// A rejected key fails before any list is read
+	_, err = NewPAMClientWithHTTP(server.Client(), server.URL, "acme", "kid", "wrong").FetchResourceGroups(context.Background())
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a 401 APIError, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package okta
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitBucket is the state of one Okta rate limit bucket as last
+// reported by the X-Rate-Limit-* response headers.
+type RateLimitBucket struct {
+	Bucket    string    // e.g. "/api/v1/users" or "/api/v1/users*"
+	Limit     int       // Requests allowed per window
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the window resets
+}
+
+// rateLimits tracks Okta rate limit buckets and holds back requests that
+// would drain one. Okta's limits are shared by every integration and admin
+// in the org, so background requests leave part of each bucket unused, and
+// list pagination, which can't cheaply restart, may use it all.
+type rateLimits struct {
+	mu      sync.Mutex
+	buckets map[string]*RateLimitBucket
+}
+
+// RateLimitBucketFor returns the bucket an API path falls under. Okta
+// limits a collection endpoint (/api/v1/users) separately from the
+// endpoints beneath it (/api/v1/users*), so per-user factor lookups and the
+// user listing are tracked apart. Path patterns such as
+// /api/v1/users/{id}/factors map to the same bucket as real paths.
+func RateLimitBucketFor(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) <= 3 || segments[0] != "api" {
+		return path
+	}
+	return "/" + strings.Join(segments[:3], "/") + "*"
+}
+
+// reserve returns how much of a bucket a request must leave unused.
+func reserve(limit int, pagination bool) int {
+	if pagination {
+		return 0
+	}
+	return limit * rateLimitReservePercent / 100
+}
+
+// wait blocks until bucket has room for a request above its reserve, or
+// until its window resets, and then counts the request against it. It
+// reports whether the request was held back. Waits longer than
+// maxRateLimitWait are skipped; the request goes out and any 429 is
+// handled by the retry loop instead.
+func (r *rateLimits) wait(ctx context.Context, bucket string, pagination bool) (bool, error) {
+	r.mu.Lock()
+	b, ok := r.buckets[bucket]
+	if !ok {
+		r.mu.Unlock()
+		return false, nil
+	}
+	var delay time.Duration
+	if b.Remaining <= reserve(b.Limit, pagination) {
+		delay = time.Until(b.Reset) + time.Second
+	}
+	if delay <= 0 || delay > maxRateLimitWait {
+		b.Remaining--
+		r.mu.Unlock()
+		return false, nil
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return true, ctx.Err()
+	case <-time.After(delay):
+		return true, nil
+	}
+}
+
+// observe records the bucket state reported by a response. Responses
+// within one window can arrive out of order, so the lowest remaining count
+// wins until the window resets.
+func (r *rateLimits) observe(bucket string, header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-Rate-Limit-Limit"))
+	if err != nil || limit <= 0 {
+		return
+	}
+	remaining, err := strconv.Atoi(header.Get("X-Rate-Limit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetUnix, err := strconv.ParseInt(header.Get("X-Rate-Limit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	reset := time.Unix(resetUnix, 0)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buckets == nil {
+		r.buckets = make(map[string]*RateLimitBucket)
+	}
+	b, ok := r.buckets[bucket]
+	if ok && reset.Equal(b.Reset) && remaining >= b.Remaining {
+		return
+	}
+	if ok && reset.Before(b.Reset) {
+		return
+	}
+	r.buckets[bucket] = &RateLimitBucket{Bucket: bucket, Limit: limit, Remaining: remaining, Reset: reset}
+}
+
+// snapshot returns the tracked buckets sorted by name.
+func (r *rateLimits) snapshot() []RateLimitBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	buckets := make([]RateLimitBucket, 0, len(r.buckets))
+	for _, b := range r.buckets {
+		buckets = append(buckets, *b)
+	}
+	slices.SortFunc(buckets, func(a, b RateLimitBucket) int { return cmp.Compare(a.Bucket, b.Bucket) })
+	return buckets
+}
+
+// RateLimits returns the rate limit buckets the client has seen, as last
+// reported by Okta.
+func (c *Client) RateLimits() []RateLimitBucket {
+	return c.limits.snapshot()
+}
@@ -0,0 +1,110 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimitBucket(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/users?limit=200", "/api/v1/users"},
+		{"/api/v1/users/00u1abcd2EFGH3ijk4x7/factors", "/api/v1/users*"},
+		{"/api/v1/apps", "/api/v1/apps"},
+		{"/api/v1/apps/0oa1abcd2EFGH3ijk4x7/groups?limit=200", "/api/v1/apps*"},
+		{"/api/v1/logs?since=2026-01-01T00%3A00%3A00Z", "/api/v1/logs"},
+		{"/oauth2/v1/token", "/oauth2/v1/token"},
+	}
+	for _, tt := range tests {
+		if got := RateLimitBucketFor(tt.path); got != tt.want {
+			t.Errorf("RateLimitBucketFor(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimits_Wait(t *testing.T) {
+	header := func(remaining int, reset time.Time) http.Header {
+		h := http.Header{}
+		h.Set("X-Rate-Limit-Limit", "100")
+		h.Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+		h.Set("X-Rate-Limit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		return h
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var limits rateLimits
+	reset := time.Now().Add(30 * time.Second)
+	limits.observe("/api/v1/users", header(10, reset))
+
+	// Pagination may use the reserve
+	if throttled, err := limits.wait(canceled, "/api/v1/users", true); throttled || err != nil {
+		t.Fatalf("expected pagination to proceed, got %v, %v", throttled, err)
+	}
+
+	// Other requests wait for the reset
+	if throttled, err := limits.wait(canceled, "/api/v1/users", false); !throttled || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the request to be held back, got %v, %v", throttled, err)
+	}
+
+	// A late response from the same window doesn't raise the count
+	limits.observe("/api/v1/users", header(50, reset))
+	if got := limits.snapshot()[0].Remaining; got != 9 {
+		t.Errorf("expected 9 remaining, got %d", got)
+	}
+
+	// A new window replaces the old one
+	limits.observe("/api/v1/users", header(99, reset.Add(time.Minute)))
+	if got := limits.snapshot()[0].Remaining; got != 99 {
+		t.Errorf("expected 99 remaining, got %d", got)
+	}
+
+	// Resets too far away are left to the 429 handling
+	limits.observe("/api/v1/apps", header(0, time.Now().Add(time.Hour)))
+	if throttled, err := limits.wait(canceled, "/api/v1/apps", false); throttled || err != nil {
+		t.Errorf("expected the request to proceed, got %v, %v", throttled, err)
+	}
+
+	// Unknown buckets are not held back
+	if throttled, err := limits.wait(canceled, "/api/v1/groups", false); throttled || err != nil {
+		t.Errorf("expected the request to proceed, got %v, %v", throttled, err)
+	}
+}
+
+func TestRateLimits_FromResponses(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Limit-Limit", "1000")
+		w.Header().Set("X-Rate-Limit-Remaining", "998")
+		w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(reset, 10))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgSettings{ID: "org1"})
+	}))
+	defer server.Close()
+
+	client := NewClientWithHTTP(server.Client(), server.URL)
+	client.SetToken("test-token")
+	if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buckets := client.RateLimits()
+	if len(buckets) != 1 {
+		t.Fatalf("expected 1 bucket, got %+v", buckets)
+	}
+	b := buckets[0]
+	if b.Bucket != "/api/v1/org" || b.Limit != 1000 || b.Remaining != 998 || b.Reset.Unix() != reset {
+		t.Errorf("unexpected bucket %+v", b)
+	}
+	if client.Stats().Throttled != 0 {
+		t.Errorf("expected no throttled requests, got %d", client.Stats().Throttled)
+	}
+}
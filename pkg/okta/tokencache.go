@@ -0,0 +1,125 @@
+package okta
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tokenCacheKeyLabel separates the cache key from other uses of the
+// private key material.
+const tokenCacheKeyLabel = "epack-collector-okta token cache\x00"
+
+// tokenCache keeps an OAuth access token in a file between runs, so
+// short-lived processes don't each make a client credentials exchange,
+// which Okta rate limits. The file is sealed with AES-GCM under a key
+// derived from the client's private key, so it is only readable with the
+// key that could mint the token anyway. The org, client ID, authorization
+// server, and scopes are bound to the ciphertext, and a cache written for
+// different credentials reads as a miss.
+type tokenCache struct {
+	path    string
+	key     [32]byte
+	binding []byte // Additional data identifying the credentials
+}
+
+// cachedToken is the sealed content of a token cache file.
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// newTokenCache returns the cache at path for the given credentials.
+func newTokenCache(path, baseURL string, oauth *oauthCredentials) *tokenCache {
+	material := append([]byte(tokenCacheKeyLabel), x509.MarshalPKCS1PrivateKey(oauth.key)...)
+	return &tokenCache{
+		path:    path,
+		key:     sha256.Sum256(material),
+		binding: []byte(strings.Join([]string{baseURL + oauth.tokenPath(), oauth.clientID, oauth.scope}, "\n")),
+	}
+}
+
+// load returns the cached token if it is readable, was cached for the same
+// credentials, and is not within tokenRefreshMargin of expiring.
+func (t *tokenCache) load() (string, time.Time, bool) {
+	sealed, err := os.ReadFile(t.path)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	aead, err := t.aead()
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", time.Time{}, false
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, t.binding)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	var cached cachedToken
+	if err := json.Unmarshal(plaintext, &cached); err != nil || cached.AccessToken == "" {
+		return "", time.Time{}, false
+	}
+	if time.Until(cached.Expiry) < tokenRefreshMargin {
+		return "", time.Time{}, false
+	}
+	return cached.AccessToken, cached.Expiry, true
+}
+
+// save seals the token into the cache file, readable only by its owner.
+// The file is replaced atomically so a concurrent run never reads a
+// partial write.
+func (t *tokenCache) save(accessToken string, expiry time.Time) error {
+	plaintext, err := json.Marshal(cachedToken{AccessToken: accessToken, Expiry: expiry})
+	if err != nil {
+		return err
+	}
+	aead, err := t.aead()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, t.binding)
+
+	tmp, err := os.CreateTemp(filepath.Dir(t.path), ".token-cache-*")
+	if err != nil {
+		return err
+	}
+	_, writeErr := tmp.Write(sealed)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), t.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// clear removes the cache file, for a token Okta no longer accepts.
+// Removal is best-effort: a file left behind is rejected again next run.
+func (t *tokenCache) clear() {
+	_ = os.Remove(t.path)
+}
+
+// aead returns the AES-GCM cipher for the cache key.
+func (t *tokenCache) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(t.key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,184 @@
+package okta
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenCache(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "minted", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	oauth := &oauthCredentials{clientID: "client", key: key, scope: "okta.users.read"}
+	oauth.cache = newTokenCache(path, server.URL, oauth)
+	auth := newOAuthAuth(&Client{httpClient: server.Client(), baseURL: server.URL}, oauth)
+
+	// A minted token is written to the cache
+	if err := auth.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected cache file: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+	if token, _, ok := newTokenCache(path, server.URL, oauth).load(); !ok || token != "minted" {
+		t.Errorf("expected cached token, got %q %v", token, ok)
+	}
+
+	// Other scopes, clients, or keys don't read it
+	other := *oauth
+	other.scope = "okta.users.read okta.groups.read"
+	if _, _, ok := newTokenCache(path, server.URL, &other).load(); ok {
+		t.Error("expected a miss for different scopes")
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	other = *oauth
+	other.key = otherKey
+	if _, _, ok := newTokenCache(path, server.URL, &other).load(); ok {
+		t.Error("expected a miss for a different key")
+	}
+
+	// Tokens near expiry are not reused
+	if err := oauth.cache.save("stale", time.Now().Add(tokenRefreshMargin/2)); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+	if _, _, ok := oauth.cache.load(); ok {
+		t.Error("expected a miss for a token near expiry")
+	}
+
+	// Corrupt files are a miss
+	if err := os.WriteFile(path, []byte("not a token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := oauth.cache.load(); ok {
+		t.Error("expected a miss for a corrupt file")
+	}
+	if exchanges.Load() != 1 {
+		t.Errorf("expected 1 exchange, got %d", exchanges.Load())
+	}
+}
+
+func TestNewClientWithOAuthConfig_CachedToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	config := OAuthConfig{
+		OrgDomain:      "company.okta.com",
+		ClientID:       "client",
+		PrivateKey:     pemKey,
+		TokenCachePath: filepath.Join(t.TempDir(), "token"),
+	}
+
+	// Seed the cache as a previous run would have
+	oauth := &oauthCredentials{clientID: config.ClientID, key: key, scope: strings.Join(DefaultScopes, " ")}
+	expiry := time.Now().Add(time.Hour)
+	if err := newTokenCache(config.TokenCachePath, "https://company.okta.com", oauth).save("cached", expiry); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	// No token exchange is attempted; company.okta.com is never contacted
+	client, err := NewClientWithOAuthConfig(config)
+	if err != nil {
+		t.Fatalf("NewClientWithOAuthConfig() error = %v", err)
+	}
+	token, err := client.auth.(*oauthAuth).currentToken(context.Background())
+	if err != nil || token != "cached" {
+		t.Errorf("expected cached token, got %q, %v", token, err)
+	}
+}
+
+func TestCachedTokenRejected(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var exchanges, rejections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/oauth2/v1/token":
+			exchanges.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "minted", "token_type": "Bearer", "expires_in": 3600})
+		case r.Header.Get("Authorization") != "Bearer minted":
+			rejections.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errorCode":"E0000011","errorSummary":"Invalid token provided"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"org1","subdomain":"company"}`))
+		}
+	}))
+	defer server.Close()
+
+	// Seed the cache with a token Okta has since revoked
+	path := filepath.Join(t.TempDir(), "token")
+	creds := &oauthCredentials{clientID: "client", key: key, scope: strings.Join(DefaultScopes, " ")}
+	creds.cache = newTokenCache(path, server.URL, creds)
+	if err := creds.cache.save("revoked", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	client := &Client{httpClient: server.Client(), baseURL: server.URL}
+	auth := newOAuthAuth(client, creds)
+	client.auth = auth
+	if !auth.loadCachedToken() {
+		t.Fatal("loadCachedToken() = false, want true")
+	}
+
+	// The rejected cached token is replaced once and the request retried
+	if _, err := client.FetchOrgSettings(context.Background()); err != nil {
+		t.Fatalf("FetchOrgSettings() error = %v", err)
+	}
+	if n := exchanges.Load(); n != 1 {
+		t.Errorf("token exchanges = %d, want 1", n)
+	}
+	if n := rejections.Load(); n != 1 {
+		t.Errorf("rejections = %d, want 1", n)
+	}
+	if token, _, ok := creds.cache.load(); !ok || token != "minted" {
+		t.Errorf("cache holds %q, %v; want the minted token", token, ok)
+	}
+
+	// A rejected token that didn't come from the cache is not retried
+	auth.setToken("revoked", time.Now().Add(time.Hour))
+	if _, err := client.FetchOrgSettings(context.Background()); err == nil {
+		t.Fatal("FetchOrgSettings() error = nil, want 401")
+	}
+	if n := exchanges.Load(); n != 1 {
+		t.Errorf("token exchanges = %d, want 1", n)
+	}
+	if n := rejections.Load(); n != 2 {
+		t.Errorf("rejections = %d, want 2", n)
+	}
+}
@@ -0,0 +1,748 @@
+// Package okta provides Okta API client functionality.
+package okta
+
+import "time"
+
+// User represents an Okta user.
+type User struct {
+	ID              string      `json:"id"`
+	Status          string      `json:"status"` // STAGED, PROVISIONED, ACTIVE, RECOVERY, LOCKED_OUT, PASSWORD_EXPIRED, SUSPENDED, DEPROVISIONED
+	Created         time.Time   `json:"created"`
+	Activated       time.Time   `json:"activated"`
+	LastLogin       time.Time   `json:"lastLogin"`
+	LastUpdated     time.Time   `json:"lastUpdated"`
+	PasswordChanged time.Time   `json:"passwordChanged"`
+	StatusChanged   time.Time   `json:"statusChanged"`
+	Profile         UserProfile `json:"profile"`
+}
+
+// UserProfile contains user profile information.
+type UserProfile struct {
+	Login     string `json:"login"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	UserType  string `json:"userType"`
+}
+
+// Factor represents an MFA factor enrolled by a user.
+type Factor struct {
+	ID         string `json:"id"`
+	FactorType string `json:"factorType"` // sms, call, email, token:software:totp, token:hotp, push, u2f, webauthn, token:hardware, question
+	Provider   string `json:"provider"`   // OKTA, GOOGLE, RSA, SYMANTEC, YUBICO, etc.
+	VendorName string `json:"vendorName"`
+	Status     string `json:"status"` // ACTIVE, PENDING_ACTIVATION, ENROLLED, INACTIVE, NOT_SETUP
+}
+
+// Application represents an Okta application.
+type Application struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Label       string        `json:"label"`
+	Status      string        `json:"status"`     // ACTIVE, INACTIVE
+	SignOnMode  string        `json:"signOnMode"` // SAML_2_0, OPENID_CONNECT, WS_FEDERATION, BROWSER_PLUGIN, etc.
+	Created     time.Time     `json:"created"`
+	LastUpdated time.Time     `json:"lastUpdated"`
+	Features    []string      `json:"features"` // PUSH_NEW_USERS, PUSH_USER_DEACTIVATION, etc.
+	Visibility  AppVisibility `json:"visibility"`
+	Links       AppLinks      `json:"_links"`
+}
+
+// AppLinks contains the application links the collector follows.
+type AppLinks struct {
+	AccessPolicy *Link `json:"accessPolicy,omitempty"` // Authentication policy (Identity Engine)
+}
+
+// Link is a HAL link.
+type Link struct {
+	Href string `json:"href"`
+}
+
+// AppVisibility contains application visibility settings.
+type AppVisibility struct {
+	AutoSubmitToolbar bool `json:"autoSubmitToolbar"`
+	Hide              struct {
+		IOS bool `json:"iOS"`
+		Web bool `json:"web"`
+	} `json:"hide"`
+}
+
+// Policy represents an Okta policy.
+type Policy struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`   // OKTA_SIGN_ON, PASSWORD, MFA_ENROLL, ACCESS_POLICY
+	Status     string           `json:"status"` // ACTIVE, INACTIVE
+	Priority   int              `json:"priority"`
+	System     bool             `json:"system"` // Is this a system policy?
+	Conditions PolicyConditions `json:"conditions"`
+	Settings   PolicySettings   `json:"settings"`
+}
+
+// PolicyConditions contains policy conditions.
+type PolicyConditions struct {
+	People *PolicyPeopleCondition `json:"people,omitempty"`
+}
+
+// PolicyPeopleCondition specifies which users/groups a policy applies to.
+type PolicyPeopleCondition struct {
+	Groups *struct {
+		Include []string `json:"include,omitempty"`
+		Exclude []string `json:"exclude,omitempty"`
+	} `json:"groups,omitempty"`
+	Users *struct {
+		Include []string `json:"include,omitempty"`
+		Exclude []string `json:"exclude,omitempty"`
+	} `json:"users,omitempty"`
+}
+
+// PolicySettings contains policy settings.
+type PolicySettings struct {
+	// Password policy settings
+	Password *PasswordPolicySettings `json:"password,omitempty"`
+
+	// Recovery settings
+	Recovery *RecoverySettings `json:"recovery,omitempty"`
+
+	// Delegation settings
+	Delegation *DelegationSettings `json:"delegation,omitempty"`
+
+	// MFA enrollment settings: factors on Classic Engine, authenticators on
+	// Identity Engine
+	Type           string                             `json:"type,omitempty"` // FACTORS, AUTHENTICATORS
+	Factors        map[string]FactorEnrollmentSetting `json:"factors,omitempty"`
+	Authenticators []AuthenticatorEnrollmentSetting   `json:"authenticators,omitempty"`
+}
+
+// FactorEnrollmentSetting is the enrollment requirement for a factor.
+type FactorEnrollmentSetting struct {
+	Enroll struct {
+		Self string `json:"self"` // REQUIRED, OPTIONAL, NOT_ALLOWED
+	} `json:"enroll"`
+}
+
+// AuthenticatorEnrollmentSetting is the enrollment requirement for an authenticator.
+type AuthenticatorEnrollmentSetting struct {
+	Key    string `json:"key"` // e.g. okta_verify, phone_number, webauthn
+	Enroll struct {
+		Self string `json:"self"` // REQUIRED, OPTIONAL, NOT_ALLOWED
+	} `json:"enroll"`
+}
+
+// PasswordPolicySettings contains password policy configuration.
+type PasswordPolicySettings struct {
+	Complexity *PasswordComplexity `json:"complexity,omitempty"`
+	Age        *PasswordAge        `json:"age,omitempty"`
+	Lockout    *PasswordLockout    `json:"lockout,omitempty"`
+
+	// Identity Engine only
+	BreachedProtection *BreachedProtection `json:"breachedProtection,omitempty"`
+}
+
+// PasswordComplexity defines password complexity requirements.
+type PasswordComplexity struct {
+	MinLength         int      `json:"minLength"`
+	MinLowerCase      int      `json:"minLowerCase"`
+	MinUpperCase      int      `json:"minUpperCase"`
+	MinNumber         int      `json:"minNumber"`
+	MinSymbol         int      `json:"minSymbol"`
+	ExcludeUsername   bool     `json:"excludeUsername"`
+	ExcludeAttributes []string `json:"excludeAttributes"`
+	Dictionary        *struct {
+		Common *struct {
+			Exclude bool `json:"exclude"` // Reject passwords from the common-password list
+		} `json:"common,omitempty"`
+	} `json:"dictionary,omitempty"`
+}
+
+// BreachedProtection defines the response to credentials found in a breach.
+type BreachedProtection struct {
+	ExpireAfterDays     *int   `json:"expireAfterDays"` // Days until the password expires; nil for no action
+	LogoutEnabled       bool   `json:"logoutEnabled"`
+	DelegatedWorkflowID string `json:"delegatedWorkflowId,omitempty"`
+}
+
+// PasswordAge defines password age requirements.
+type PasswordAge struct {
+	MaxAgeDays     int `json:"maxAgeDays"`
+	ExpireWarnDays int `json:"expireWarnDays"`
+	MinAgeMinutes  int `json:"minAgeMinutes"`
+	HistoryCount   int `json:"historyCount"`
+}
+
+// PasswordLockout defines lockout settings.
+type PasswordLockout struct {
+	MaxAttempts         int  `json:"maxAttempts"`
+	AutoUnlockMinutes   int  `json:"autoUnlockMinutes"`
+	ShowLockoutFailures bool `json:"showLockoutFailures"`
+}
+
+// RecoverySettings contains account recovery settings.
+type RecoverySettings struct {
+	Factors *RecoveryFactors `json:"factors,omitempty"`
+}
+
+// RecoveryFactors defines allowed recovery factors.
+type RecoveryFactors struct {
+	RecoveryQuestion *RecoveryQuestionSettings `json:"recovery_question,omitempty"`
+	OktaEmail        *RecoveryEmailSettings    `json:"okta_email,omitempty"`
+	OktaSMS          *RecoverySMSSettings      `json:"okta_sms,omitempty"`
+	OktaCall         *RecoveryCallSettings     `json:"okta_call,omitempty"`
+}
+
+// RecoveryQuestionSettings for recovery question factor.
+type RecoveryQuestionSettings struct {
+	Status     string `json:"status"` // ACTIVE, INACTIVE
+	Properties struct {
+		Complexity struct {
+			MinLength int `json:"minLength"`
+		} `json:"complexity"`
+	} `json:"properties"`
+}
+
+// RecoveryEmailSettings for email recovery factor.
+type RecoveryEmailSettings struct {
+	Status     string `json:"status"`
+	Properties struct {
+		RecoveryToken struct {
+			TokenLifetimeMinutes int `json:"tokenLifetimeMinutes"`
+		} `json:"recoveryToken"`
+	} `json:"properties"`
+}
+
+// RecoverySMSSettings for SMS recovery factor.
+type RecoverySMSSettings struct {
+	Status string `json:"status"`
+}
+
+// RecoveryCallSettings for voice call recovery factor.
+type RecoveryCallSettings struct {
+	Status string `json:"status"`
+}
+
+// DelegationSettings contains delegation settings.
+type DelegationSettings struct {
+	Options struct {
+		SkipUnlock bool `json:"skipUnlock"`
+	} `json:"options"`
+}
+
+// PolicyRule represents a rule within a policy.
+type PolicyRule struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	Status     string               `json:"status"` // ACTIVE, INACTIVE
+	Priority   int                  `json:"priority"`
+	System     bool                 `json:"system"`
+	Type       string               `json:"type"`
+	Conditions PolicyRuleConditions `json:"conditions"`
+	Actions    PolicyRuleActions    `json:"actions"`
+}
+
+// PolicyRuleConditions contains rule conditions.
+type PolicyRuleConditions struct {
+	People    *PolicyPeopleCondition `json:"people,omitempty"`
+	RiskScore *struct {
+		Level string `json:"level"` // ANY, LOW, MEDIUM, HIGH
+	} `json:"riskScore,omitempty"`
+	Risk *struct {
+		Behaviors []string `json:"behaviors,omitempty"` // Behavior detection names
+	} `json:"risk,omitempty"`
+	Network *struct {
+		Connection string   `json:"connection"` // ANYWHERE, ZONE, ON_NETWORK
+		Include    []string `json:"include,omitempty"`
+		Exclude    []string `json:"exclude,omitempty"`
+	} `json:"network,omitempty"`
+}
+
+// PolicyRuleActions contains rule actions.
+type PolicyRuleActions struct {
+	Signon                   *SignonActions    `json:"signon,omitempty"`
+	Enroll                   *EnrollActions    `json:"enroll,omitempty"`
+	AppSignOn                *AppSignOnActions `json:"appSignOn,omitempty"`
+	SelfServicePasswordReset *SSPRActions      `json:"selfServicePasswordReset,omitempty"`
+}
+
+// SSPRActions for password policy rules. Requirement is only set on
+// Identity Engine, where it replaces the policy's recovery factors.
+type SSPRActions struct {
+	Access      string `json:"access"` // ALLOW, DENY
+	Requirement *struct {
+		Primary *struct {
+			Methods []string `json:"methods"` // e.g. email, sms, voice, push
+		} `json:"primary,omitempty"`
+		StepUp *struct {
+			Required bool     `json:"required"`
+			Methods  []string `json:"methods,omitempty"`
+		} `json:"stepUp,omitempty"`
+	} `json:"requirement,omitempty"`
+}
+
+// SignonActions for sign-on policy rules.
+type SignonActions struct {
+	Access                  string `json:"access"` // ALLOW, DENY
+	RequireFactor           bool   `json:"requireFactor"`
+	FactorPromptMode        string `json:"factorPromptMode"` // ALWAYS, DEVICE, SESSION
+	PrimaryFactor           string `json:"primaryFactor"`    // PASSWORD_IDP, PASSWORD_IDP_ANY_FACTOR (Identity Engine)
+	RememberDeviceByDefault bool   `json:"rememberDeviceByDefault"`
+	FactorLifetime          int    `json:"factorLifetime"` // Minutes
+	Session                 struct {
+		UsePersistentCookie       bool `json:"usePersistentCookie"`
+		MaxSessionIdleMinutes     int  `json:"maxSessionIdleMinutes"`
+		MaxSessionLifetimeMinutes int  `json:"maxSessionLifetimeMinutes"`
+	} `json:"session"`
+}
+
+// AppSignOnActions for authentication policy (ACCESS_POLICY) rules.
+type AppSignOnActions struct {
+	Access             string `json:"access"` // ALLOW, DENY
+	VerificationMethod struct {
+		Type             string                    `json:"type"`             // ASSURANCE, AUTH_METHOD_CHAIN
+		FactorMode       string                    `json:"factorMode"`       // 1FA, 2FA
+		ReauthenticateIn string                    `json:"reauthenticateIn"` // ISO 8601 duration, e.g. PT2H
+		Constraints      []AuthenticatorConstraint `json:"constraints,omitempty"`
+	} `json:"verificationMethod"`
+}
+
+// AuthenticatorConstraint restricts which authenticators satisfy a rule.
+type AuthenticatorConstraint struct {
+	Knowledge  *AuthenticatorRequirement `json:"knowledge,omitempty"`
+	Possession *AuthenticatorRequirement `json:"possession,omitempty"`
+}
+
+// AuthenticatorRequirement lists required authenticator characteristics.
+type AuthenticatorRequirement struct {
+	PhishingResistant  string `json:"phishingResistant,omitempty"`  // REQUIRED, OPTIONAL
+	HardwareProtection string `json:"hardwareProtection,omitempty"` // REQUIRED, OPTIONAL
+	UserPresence       string `json:"userPresence,omitempty"`       // REQUIRED, OPTIONAL
+}
+
+// EnrollActions for MFA enrollment policy rules.
+type EnrollActions struct {
+	Self string `json:"self"` // CHALLENGE, LOGIN, NEVER
+}
+
+// Group represents an Okta group.
+type Group struct {
+	ID       string       `json:"id"`
+	Created  time.Time    `json:"created"`
+	Profile  GroupProfile `json:"profile"`
+	Type     string       `json:"type"` // OKTA_GROUP, APP_GROUP, BUILT_IN
+	Embedded struct {
+		Stats *GroupStats `json:"stats,omitempty"`
+	} `json:"_embedded"` // Populated when requested with expand=stats
+}
+
+// GroupStats are a group's member and app counts.
+type GroupStats struct {
+	UsersCount int `json:"usersCount"`
+	AppsCount  int `json:"appsCount"`
+}
+
+// GroupRule assigns users matching an expression to groups.
+type GroupRule struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Status     string              `json:"status"` // ACTIVE, INACTIVE, INVALID
+	Conditions GroupRuleConditions `json:"conditions"`
+	Actions    struct {
+		AssignUserToGroups struct {
+			GroupIDs []string `json:"groupIds"`
+		} `json:"assignUserToGroups"`
+	} `json:"actions"`
+}
+
+// GroupRuleConditions select the users a group rule applies to.
+type GroupRuleConditions struct {
+	Expression struct {
+		Value string `json:"value"` // Okta Expression Language, e.g. isMemberOfAnyGroup("00g...")
+	} `json:"expression"`
+	People struct {
+		Users struct {
+			Exclude []string `json:"exclude"`
+		} `json:"users"`
+		Groups struct {
+			Exclude []string `json:"exclude"`
+		} `json:"groups"`
+	} `json:"people"`
+}
+
+// ApplicationGroupAssignment assigns an app to a group. The group is
+// embedded when requested with expand=group.
+type ApplicationGroupAssignment struct {
+	ID       string `json:"id"` // Group ID
+	Priority int    `json:"priority"`
+	Embedded struct {
+		Group *Group `json:"group,omitempty"`
+	} `json:"_embedded"`
+}
+
+// GroupProfile contains group profile information.
+type GroupProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Feature is a self-service (Early Access or Beta) org feature.
+type Feature struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`   // self-service
+	Status string `json:"status"` // ENABLED, DISABLED
+	Stage  struct {
+		Value string `json:"value"` // EA, BETA
+		State string `json:"state"` // OPEN, CLOSED (Beta only)
+	} `json:"stage"`
+}
+
+// OrgMetadata is the public org metadata at /.well-known/okta-organization.
+type OrgMetadata struct {
+	ID       string `json:"id"`
+	Pipeline string `json:"pipeline"` // idx (Identity Engine) or v1 (Classic Engine)
+}
+
+// OrgSettings represents Okta organization settings.
+type OrgSettings struct {
+	ID          string    `json:"id"`
+	Subdomain   string    `json:"subdomain"`
+	CompanyName string    `json:"companyName"`
+	Status      string    `json:"status"`
+	Created     time.Time `json:"created"`
+}
+
+// SecurityNotificationSettings are the org's end-user security notification emails.
+type SecurityNotificationSettings struct {
+	SendEmailForNewDeviceEnabled        bool `json:"sendEmailForNewDeviceEnabled"`
+	SendEmailForFactorEnrollmentEnabled bool `json:"sendEmailForFactorEnrollmentEnabled"`
+	SendEmailForFactorResetEnabled      bool `json:"sendEmailForFactorResetEnabled"`
+	SendEmailForPasswordChangedEnabled  bool `json:"sendEmailForPasswordChangedEnabled"`
+	ReportSuspiciousActivityEnabled     bool `json:"reportSuspiciousActivityEnabled"`
+}
+
+// EndUserSettings are the org's end-user self-service settings.
+type EndUserSettings struct {
+	SelfServiceFactorResetEnabled bool `json:"selfServiceFactorResetEnabled"` // Users can reset their own enrolled factors
+	ProfileEditEnabled            bool `json:"profileEditEnabled"`            // Users can edit their profile from Settings
+	PersonalAppsEnabled           bool `json:"personalAppsEnabled"`           // Users can add personal apps to their dashboard
+}
+
+// OktaSupportSettings is the org's Okta Support access setting.
+type OktaSupportSettings struct {
+	Support    string     `json:"support"`    // ENABLED, DISABLED
+	Expiration *time.Time `json:"expiration"` // When access ends; null when disabled
+}
+
+// APIToken is the metadata of an API (SSWS) token. Okta does not report
+// when a token was last used.
+type APIToken struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	UserID      string     `json:"userId"`      // Admin the token acts as
+	TokenWindow string     `json:"tokenWindow"` // Idle expiry window as an ISO 8601 duration, e.g. P30D
+	Created     time.Time  `json:"created"`
+	LastUpdated time.Time  `json:"lastUpdated"`
+	ExpiresAt   *time.Time `json:"expiresAt"` // Extended each time the token is used
+}
+
+// OktaSupportCase is a support case and its impersonation grant.
+type OktaSupportCase struct {
+	CaseNumber    string            `json:"caseNumber"`
+	Subject       string            `json:"subject"`
+	Impersonation SupportCaseAccess `json:"impersonation"`
+}
+
+// SupportCaseAccess is an access grant for a support case.
+type SupportCaseAccess struct {
+	Status     string     `json:"status"` // ENABLED, DISABLED
+	Expiration *time.Time `json:"expiration"`
+}
+
+// LogStream is a System Log stream to an external destination.
+type LogStream struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`   // aws_eventbridge, splunk_cloud_logstreaming
+	Status string `json:"status"` // ACTIVE, INACTIVE
+}
+
+// CustomRole is a custom admin role.
+type CustomRole struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+// RolePermission is a permission granted by a custom role.
+type RolePermission struct {
+	Label string `json:"label"` // e.g. okta.users.manage
+}
+
+// ResourceSet is a set of resources that custom roles are granted on.
+type ResourceSet struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+// ResourceSetBinding binds a custom role to a resource set.
+type ResourceSetBinding struct {
+	ID string `json:"id"` // Custom role ID
+}
+
+// BindingMember is a user or group holding a custom role through a binding.
+type BindingMember struct {
+	ID    string `json:"id"` // Membership ID
+	Links struct {
+		Self *Link `json:"self,omitempty"` // The user or group
+	} `json:"_links"`
+}
+
+// RoleAssignee is a user holding at least one admin role.
+type RoleAssignee struct {
+	ID string `json:"id"`
+}
+
+// RoleAssignment is an admin role assigned to a user or group.
+type RoleAssignment struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"` // SUPER_ADMIN, ORG_ADMIN, APP_ADMIN, ..., CUSTOM
+	Label          string `json:"label"`
+	Status         string `json:"status"`
+	AssignmentType string `json:"assignmentType"` // USER, GROUP
+}
+
+// OrgCaptchaSettings selects the CAPTCHA instance and the pages it protects.
+type OrgCaptchaSettings struct {
+	CaptchaID    string   `json:"captchaId"`    // Empty when CAPTCHA is off
+	EnabledPages []string `json:"enabledPages"` // SIGN_IN, SSR, SSPR
+}
+
+// ThreatInsightConfiguration is the org's ThreatInsight setting.
+type ThreatInsightConfiguration struct {
+	Action       string   `json:"action"`       // none, audit, or block
+	ExcludeZones []string `json:"excludeZones"` // Network zone IDs exempt from ThreatInsight
+}
+
+// NetworkZone is a named set of IP addresses or locations.
+type NetworkZone struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`   // IP or DYNAMIC
+	Status   string        `json:"status"` // ACTIVE or INACTIVE
+	Usage    string        `json:"usage"`  // POLICY or BLOCKLIST
+	Gateways []ZoneAddress `json:"gateways"`
+	Proxies  []ZoneAddress `json:"proxies"`
+}
+
+// ZoneAddress is an IP zone entry.
+type ZoneAddress struct {
+	Type  string `json:"type"`  // CIDR or RANGE
+	Value string `json:"value"` // e.g. "10.0.0.0/8" or "10.0.0.1-10.0.0.255"
+}
+
+// Captcha is a CAPTCHA provider instance.
+type Captcha struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // HCAPTCHA, RECAPTCHA_V2
+}
+
+// Authenticator is an Identity Engine authenticator such as Okta Verify.
+type Authenticator struct {
+	ID       string                `json:"id"`
+	Key      string                `json:"key"` // e.g. okta_verify, webauthn, okta_password
+	Name     string                `json:"name"`
+	Status   string                `json:"status"` // ACTIVE, INACTIVE
+	Settings AuthenticatorSettings `json:"settings"`
+}
+
+// AuthenticatorSettings holds authenticator-specific settings. Only the
+// Okta Verify fields are decoded.
+type AuthenticatorSettings struct {
+	ChannelBinding   *ChannelBinding `json:"channelBinding,omitempty"`
+	UserVerification string          `json:"userVerification,omitempty"` // REQUIRED, PREFERRED
+	AllowedFor       string          `json:"allowedFor,omitempty"`       // any, recovery, sso, none
+}
+
+// AuthenticatorMethod is a method of an authenticator, such as the sms and
+// voice methods of the phone authenticator.
+type AuthenticatorMethod struct {
+	Type   string `json:"type"`   // e.g. sms, voice, push, totp
+	Status string `json:"status"` // ACTIVE, INACTIVE
+}
+
+// ChannelBinding configures the Okta Verify push number challenge.
+type ChannelBinding struct {
+	Style    string `json:"style"`    // NUMBER_CHALLENGE
+	Required string `json:"required"` // ALWAYS, HIGH_RISK_ONLY, NEVER
+}
+
+// LogEvent represents a System Log event.
+type LogEvent struct {
+	UUID      string      `json:"uuid"`
+	Published time.Time   `json:"published"`
+	EventType string      `json:"eventType"` // e.g. user.session.start, user.authentication.sso
+	Severity  string      `json:"severity"`  // DEBUG, INFO, WARN, ERROR
+	Actor     LogActor    `json:"actor"`
+	Outcome   *LogOutcome `json:"outcome,omitempty"`
+	Target    []LogTarget `json:"target,omitempty"`
+
+	AuthenticationContext struct {
+		ExternalSessionID string `json:"externalSessionId"` // Okta session the event belongs to
+	} `json:"authenticationContext"`
+
+	Client struct {
+		GeographicalContext struct {
+			Country string `json:"country"` // Country name resolved from the client IP, e.g. "United States"
+		} `json:"geographicalContext"`
+	} `json:"client"`
+}
+
+// LogActor identifies who performed a logged action.
+type LogActor struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"` // User, PublicClientApp, SystemPrincipal, etc.
+	AlternateID string `json:"alternateId"`
+	DisplayName string `json:"displayName"`
+}
+
+// LogOutcome is the result of a logged action.
+type LogOutcome struct {
+	Result string `json:"result"` // SUCCESS, FAILURE, SKIPPED, ALLOW, DENY, CHALLENGE, UNKNOWN
+	Reason string `json:"reason"`
+}
+
+// LogTarget identifies an entity acted upon by a logged action.
+type LogTarget struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	AlternateID string `json:"alternateId"`
+	DisplayName string `json:"displayName"`
+}
+
+// AgentPool is a group of directory or on-premises agents of one type.
+type AgentPool struct {
+	ID                string  `json:"id"`
+	Name              string  `json:"name"`
+	Type              string  `json:"type"`              // AD, LDAP, IWA, RADIUS, MFA, OPP, RUM
+	OperationalStatus string  `json:"operationalStatus"` // OPERATIONAL, DEGRADED, DISRUPTED, INACTIVE
+	Agents            []Agent `json:"agents"`
+}
+
+// Agent is a single agent installation.
+type Agent struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	Type                string `json:"type"`
+	Version             string `json:"version"`
+	OperationalStatus   string `json:"operationalStatus"` // OPERATIONAL, DEGRADED, DISRUPTED, INACTIVE
+	LastConnection      int64  `json:"lastConnection"`    // Unix time in milliseconds
+	IsLatestGAedVersion bool   `json:"isLatestGAedVersion"`
+	IsHidden            bool   `json:"isHidden"`
+}
+
+// Campaign is an Identity Governance access certification campaign.
+type Campaign struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	Status           string           `json:"status"`       // READY, SCHEDULED, LAUNCHING, ACTIVE, COMPLETED, ERROR, DELETED
+	CampaignType     string           `json:"campaignType"` // RESOURCE or USER
+	ScheduleSettings CampaignSchedule `json:"scheduleSettings"`
+}
+
+// CampaignSchedule is when a campaign runs. Reviewers have DurationInDays
+// from StartDate to decide.
+type CampaignSchedule struct {
+	StartDate      time.Time `json:"startDate"`
+	DurationInDays int       `json:"durationInDays"`
+}
+
+// Review is one access decision requested from a reviewer in a campaign.
+type Review struct {
+	ID         string `json:"id"`
+	CampaignID string `json:"campaignId"`
+	Decision   string `json:"decision"` // UNREVIEWED, APPROVE, or REVOKE
+}
+
+// EntitlementBundle is a named set of app entitlements granted together.
+type EntitlementBundle struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // ACTIVE or INACTIVE
+}
+
+// UserSchema is the profile schema of a user type. Base holds the
+// attributes Okta defines; Custom holds the ones the org added.
+type UserSchema struct {
+	Definitions UserSchemaDefinitions `json:"definitions"`
+}
+
+// UserSchemaDefinitions splits a user schema into base and custom attributes.
+type UserSchemaDefinitions struct {
+	Base   UserSchemaDefinition `json:"base"`
+	Custom UserSchemaDefinition `json:"custom"`
+}
+
+// UserSchemaDefinition maps attribute variable names to their definitions.
+type UserSchemaDefinition struct {
+	Properties map[string]UserSchemaAttribute `json:"properties"`
+}
+
+// UserSchemaAttribute is one profile attribute.
+type UserSchemaAttribute struct {
+	Title       string                 `json:"title"`
+	Type        string                 `json:"type"`
+	Sensitive   bool                   `json:"sensitive"` // Hidden from admins without the permission to view sensitive attributes
+	Permissions []UserSchemaPermission `json:"permissions"`
+}
+
+// UserSchemaPermission is what a principal may do with an attribute.
+type UserSchemaPermission struct {
+	Principal string `json:"principal"` // SELF is the user the profile belongs to
+	Action    string `json:"action"`    // READ_WRITE, READ_ONLY, or HIDE
+}
+
+// ProfileMapping maps the profile attributes of one user type or app to
+// another. Properties is keyed by target attribute and is only returned
+// when a single mapping is fetched.
+type ProfileMapping struct {
+	ID         string                            `json:"id"`
+	Source     ProfileMappingEndpoint            `json:"source"`
+	Target     ProfileMappingEndpoint            `json:"target"`
+	Properties map[string]ProfileMappingProperty `json:"properties"`
+}
+
+// ProfileMappingEndpoint is the source or target of a profile mapping.
+type ProfileMappingEndpoint struct {
+	ID   string `json:"id"`   // User type or app ID
+	Name string `json:"name"` // e.g. "user" or the app name
+	Type string `json:"type"` // user (an Okta user type) or appuser (an app's user profile)
+}
+
+// ProfileMappingProperty is the Okta expression that sets a target
+// attribute, e.g. user.firstName.
+type ProfileMappingProperty struct {
+	Expression string `json:"expression"`
+	PushStatus string `json:"pushStatus"` // PUSH or DONT_PUSH (set on create only)
+}
+
+// Brand is a set of customizations for the pages and emails users see.
+type Brand struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	IsDefault           bool   `json:"isDefault"`
+	RemovePoweredByOkta bool   `json:"removePoweredByOkta"` // Hides the "Powered by Okta" footer
+}
+
+// SignInPage is the HTML of a brand's sign-in page and the settings of
+// the Sign-In Widget it hosts.
+type SignInPage struct {
+	PageContent          string                     `json:"pageContent"`
+	WidgetVersion        string                     `json:"widgetVersion"`
+	WidgetCustomizations SignInWidgetCustomizations `json:"widgetCustomizations"`
+}
+
+// SignInWidgetCustomizations are the Sign-In Widget's labels and links.
+type SignInWidgetCustomizations struct {
+	HelpURL string `json:"helpUrl"` // Empty when the widget links to Okta's help page
+}